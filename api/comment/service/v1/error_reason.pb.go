@@ -0,0 +1,206 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.15.8
+// source: api/comment/service/v1/error_reason.proto
+
+package v1
+
+import (
+	_ "github.com/go-kratos/kratos/v2/errors"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ErrorReason int32
+
+const (
+	ErrorReason_SUBJECT_NOT_FOUND     ErrorReason = 0
+	ErrorReason_COMMENT_NOT_FOUND     ErrorReason = 1
+	ErrorReason_SUBJECT_EXISTED       ErrorReason = 2
+	ErrorReason_CONTENT_MISSING       ErrorReason = 3
+	ErrorReason_PARENT_INVALID        ErrorReason = 4  // root/parent 不属于同一主题或层级不对
+	ErrorReason_RATE_LIMITED          ErrorReason = 5  // 发评论过于频繁, metadata retry_after 为需要等待的秒数
+	ErrorReason_CONTENT_DUPLICATED    ErrorReason = 6  // 短时间内重复或相似的评论
+	ErrorReason_MEMBER_BLOCKED        ErrorReason = 7  // 被主题作者拉黑或被全站封禁
+	ErrorReason_MUTE_LIMIT_EXCEEDED   ErrorReason = 8  // 屏蔽的用户数量达到上限
+	ErrorReason_MENTION_INVALID       ErrorReason = 9  // at_member_ids 中的人没有在 message 中 @
+	ErrorReason_CONTENT_INVALID       ErrorReason = 10 // 富文本内容不合法
+	ErrorReason_ATTACHMENT_INVALID    ErrorReason = 11 // 图片格式、大小或尺寸不合法, 或 token 无效
+	ErrorReason_EDIT_FORBIDDEN        ErrorReason = 12 // 不是作者或已超过可编辑时间
+	ErrorReason_TOO_MANY_IDS          ErrorReason = 13 // 批量查询的ID过多
+	ErrorReason_ERASURE_NOT_FOUND     ErrorReason = 14
+	ErrorReason_ARGUMENT_INVALID      ErrorReason = 15 // 参数不合法
+	ErrorReason_OPERATOR_UNAUTHORIZED ErrorReason = 16 // 管理接口缺少操作员 token, token 无效或与 operator_id 不符
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "SUBJECT_NOT_FOUND",
+		1:  "COMMENT_NOT_FOUND",
+		2:  "SUBJECT_EXISTED",
+		3:  "CONTENT_MISSING",
+		4:  "PARENT_INVALID",
+		5:  "RATE_LIMITED",
+		6:  "CONTENT_DUPLICATED",
+		7:  "MEMBER_BLOCKED",
+		8:  "MUTE_LIMIT_EXCEEDED",
+		9:  "MENTION_INVALID",
+		10: "CONTENT_INVALID",
+		11: "ATTACHMENT_INVALID",
+		12: "EDIT_FORBIDDEN",
+		13: "TOO_MANY_IDS",
+		14: "ERASURE_NOT_FOUND",
+		15: "ARGUMENT_INVALID",
+		16: "OPERATOR_UNAUTHORIZED",
+	}
+	ErrorReason_value = map[string]int32{
+		"SUBJECT_NOT_FOUND":     0,
+		"COMMENT_NOT_FOUND":     1,
+		"SUBJECT_EXISTED":       2,
+		"CONTENT_MISSING":       3,
+		"PARENT_INVALID":        4,
+		"RATE_LIMITED":          5,
+		"CONTENT_DUPLICATED":    6,
+		"MEMBER_BLOCKED":        7,
+		"MUTE_LIMIT_EXCEEDED":   8,
+		"MENTION_INVALID":       9,
+		"CONTENT_INVALID":       10,
+		"ATTACHMENT_INVALID":    11,
+		"EDIT_FORBIDDEN":        12,
+		"TOO_MANY_IDS":          13,
+		"ERASURE_NOT_FOUND":     14,
+		"ARGUMENT_INVALID":      15,
+		"OPERATOR_UNAUTHORIZED": 16,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_comment_service_v1_error_reason_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_api_comment_service_v1_error_reason_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_comment_service_v1_error_reason_proto_rawDescGZIP(), []int{0}
+}
+
+var File_api_comment_service_v1_error_reason_proto protoreflect.FileDescriptor
+
+var file_api_comment_service_v1_error_reason_proto_rawDesc = []byte{
+	0x0a, 0x29, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x63, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x1a,
+	0x13, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2a, 0xec, 0x03, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x11, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x00, 0x1a, 0x04, 0xa8, 0x45, 0x94,
+	0x03, 0x12, 0x1b, 0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54,
+	0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x19,
+	0x0a, 0x0f, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x45,
+	0x44, 0x10, 0x02, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x43, 0x4f, 0x4e,
+	0x54, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x1a, 0x04,
+	0xa8, 0x45, 0x90, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x49,
+	0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x04, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x16,
+	0x0a, 0x0c, 0x52, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x45, 0x44, 0x10, 0x05,
+	0x1a, 0x04, 0xa8, 0x45, 0xad, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x43, 0x4f, 0x4e, 0x54, 0x45, 0x4e,
+	0x54, 0x5f, 0x44, 0x55, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x45, 0x44, 0x10, 0x06, 0x1a, 0x04,
+	0xa8, 0x45, 0x99, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x4d, 0x45, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x42,
+	0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x07, 0x1a, 0x04, 0xa8, 0x45, 0x93, 0x03, 0x12, 0x1d,
+	0x0a, 0x13, 0x4d, 0x55, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x5f, 0x45, 0x58, 0x43,
+	0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x08, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x19, 0x0a,
+	0x0f, 0x4d, 0x45, 0x4e, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44,
+	0x10, 0x09, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x43, 0x4f, 0x4e, 0x54,
+	0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x0a, 0x1a, 0x04, 0xa8,
+	0x45, 0x90, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x4d, 0x45, 0x4e,
+	0x54, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x0b, 0x1a, 0x04, 0xa8, 0x45, 0x90,
+	0x03, 0x12, 0x18, 0x0a, 0x0e, 0x45, 0x44, 0x49, 0x54, 0x5f, 0x46, 0x4f, 0x52, 0x42, 0x49, 0x44,
+	0x44, 0x45, 0x4e, 0x10, 0x0c, 0x1a, 0x04, 0xa8, 0x45, 0x93, 0x03, 0x12, 0x16, 0x0a, 0x0c, 0x54,
+	0x4f, 0x4f, 0x5f, 0x4d, 0x41, 0x4e, 0x59, 0x5f, 0x49, 0x44, 0x53, 0x10, 0x0d, 0x1a, 0x04, 0xa8,
+	0x45, 0x90, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x45, 0x52, 0x41, 0x53, 0x55, 0x52, 0x45, 0x5f, 0x4e,
+	0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x0e, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03,
+	0x12, 0x1a, 0x0a, 0x10, 0x41, 0x52, 0x47, 0x55, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x56,
+	0x41, 0x4c, 0x49, 0x44, 0x10, 0x0f, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1f, 0x0a, 0x15,
+	0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x55, 0x4e, 0x41, 0x55, 0x54, 0x48, 0x4f,
+	0x52, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x10, 0x1a, 0x04, 0xa8, 0x45, 0x91, 0x03, 0x1a, 0x04, 0xa0,
+	0x45, 0xf4, 0x03, 0x42, 0x1b, 0x5a, 0x19, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_comment_service_v1_error_reason_proto_rawDescOnce sync.Once
+	file_api_comment_service_v1_error_reason_proto_rawDescData = file_api_comment_service_v1_error_reason_proto_rawDesc
+)
+
+func file_api_comment_service_v1_error_reason_proto_rawDescGZIP() []byte {
+	file_api_comment_service_v1_error_reason_proto_rawDescOnce.Do(func() {
+		file_api_comment_service_v1_error_reason_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_comment_service_v1_error_reason_proto_rawDescData)
+	})
+	return file_api_comment_service_v1_error_reason_proto_rawDescData
+}
+
+var file_api_comment_service_v1_error_reason_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_comment_service_v1_error_reason_proto_goTypes = []interface{}{
+	(ErrorReason)(0), // 0: comment.service.v1.ErrorReason
+}
+var file_api_comment_service_v1_error_reason_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_comment_service_v1_error_reason_proto_init() }
+func file_api_comment_service_v1_error_reason_proto_init() {
+	if File_api_comment_service_v1_error_reason_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_comment_service_v1_error_reason_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_comment_service_v1_error_reason_proto_goTypes,
+		DependencyIndexes: file_api_comment_service_v1_error_reason_proto_depIdxs,
+		EnumInfos:         file_api_comment_service_v1_error_reason_proto_enumTypes,
+	}.Build()
+	File_api_comment_service_v1_error_reason_proto = out.File
+	file_api_comment_service_v1_error_reason_proto_rawDesc = nil
+	file_api_comment_service_v1_error_reason_proto_goTypes = nil
+	file_api_comment_service_v1_error_reason_proto_depIdxs = nil
+}
@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go-errors. DO NOT EDIT.
+
+package v1
+
+import (
+	fmt "fmt"
+	errors "github.com/go-kratos/kratos/v2/errors"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the kratos package it is being compiled against.
+const _ = errors.SupportPackageIsVersion1
+
+func IsSubjectNotFound(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_SUBJECT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorSubjectNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_SUBJECT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsCommentNotFound(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_COMMENT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorCommentNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_COMMENT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsSubjectExisted(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_SUBJECT_EXISTED.String() && e.Code == 409
+}
+
+func ErrorSubjectExisted(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_SUBJECT_EXISTED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsContentMissing(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_CONTENT_MISSING.String() && e.Code == 400
+}
+
+func ErrorContentMissing(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_CONTENT_MISSING.String(), fmt.Sprintf(format, args...))
+}
+
+func IsParentInvalid(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PARENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorParentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_PARENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsRateLimited(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_RATE_LIMITED.String() && e.Code == 429
+}
+
+func ErrorRateLimited(format string, args ...interface{}) *errors.Error {
+	return errors.New(429, ErrorReason_RATE_LIMITED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsContentDuplicated(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_CONTENT_DUPLICATED.String() && e.Code == 409
+}
+
+func ErrorContentDuplicated(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_CONTENT_DUPLICATED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsMemberBlocked(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_MEMBER_BLOCKED.String() && e.Code == 403
+}
+
+func ErrorMemberBlocked(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, ErrorReason_MEMBER_BLOCKED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsMuteLimitExceeded(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_MUTE_LIMIT_EXCEEDED.String() && e.Code == 400
+}
+
+func ErrorMuteLimitExceeded(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_MUTE_LIMIT_EXCEEDED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsMentionInvalid(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_MENTION_INVALID.String() && e.Code == 400
+}
+
+func ErrorMentionInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_MENTION_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsContentInvalid(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_CONTENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorContentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_CONTENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsAttachmentInvalid(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ATTACHMENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorAttachmentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_ATTACHMENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsEditForbidden(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_EDIT_FORBIDDEN.String() && e.Code == 403
+}
+
+func ErrorEditForbidden(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, ErrorReason_EDIT_FORBIDDEN.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTooManyIds(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TOO_MANY_IDS.String() && e.Code == 400
+}
+
+func ErrorTooManyIds(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TOO_MANY_IDS.String(), fmt.Sprintf(format, args...))
+}
+
+func IsErasureNotFound(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ERASURE_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorErasureNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_ERASURE_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsArgumentInvalid(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ARGUMENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorArgumentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_ARGUMENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsOperatorUnauthorized(err error) bool {
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_OPERATOR_UNAUTHORIZED.String() && e.Code == 401
+}
+
+func ErrorOperatorUnauthorized(format string, args ...interface{}) *errors.Error {
+	return errors.New(401, ErrorReason_OPERATOR_UNAUTHORIZED.String(), fmt.Sprintf(format, args...))
+}
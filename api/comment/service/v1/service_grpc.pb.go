@@ -28,6 +28,48 @@ type CommentServiceClient interface {
 	ListComment(ctx context.Context, in *ListCommentReq, opts ...grpc.CallOption) (*ListCommentReply, error)
 	// 查回复
 	ListReply(ctx context.Context, in *ListReplyReq, opts ...grpc.CallOption) (*ListReplyReply, error)
+	// 举报评论
+	ReportComment(ctx context.Context, in *ReportCommentReq, opts ...grpc.CallOption) (*ReportCommentReply, error)
+	// 查被举报最多的评论(管理后台)
+	ListReportedComment(ctx context.Context, in *ListReportedCommentReq, opts ...grpc.CallOption) (*ListReportedCommentReply, error)
+	// 拉黑用户, 禁止其在主题作者的内容下评论
+	BlockMember(ctx context.Context, in *BlockMemberReq, opts ...grpc.CallOption) (*BlockMemberReply, error)
+	// 取消拉黑
+	UnblockMember(ctx context.Context, in *UnblockMemberReq, opts ...grpc.CallOption) (*UnblockMemberReply, error)
+	// 查拉黑的用户
+	ListBlocked(ctx context.Context, in *ListBlockedReq, opts ...grpc.CallOption) (*ListBlockedReply, error)
+	// 全站封禁用户(管理后台)
+	BanMember(ctx context.Context, in *BanMemberReq, opts ...grpc.CallOption) (*BanMemberReply, error)
+	// 取消全站封禁(管理后台)
+	UnbanMember(ctx context.Context, in *UnbanMemberReq, opts ...grpc.CallOption) (*UnbanMemberReply, error)
+	// 查全站封禁的用户(管理后台)
+	ListBanned(ctx context.Context, in *ListBannedReq, opts ...grpc.CallOption) (*ListBannedReply, error)
+	// 屏蔽用户, 查评论时不再看到其评论
+	MuteMember(ctx context.Context, in *MuteMemberReq, opts ...grpc.CallOption) (*MuteMemberReply, error)
+	// 取消屏蔽
+	UnmuteMember(ctx context.Context, in *UnmuteMemberReq, opts ...grpc.CallOption) (*UnmuteMemberReply, error)
+	// 查屏蔽的用户
+	ListMuted(ctx context.Context, in *ListMutedReq, opts ...grpc.CallOption) (*ListMutedReply, error)
+	// 上传评论图片, 返回的 token 用于 CreateCommentReq.attachments
+	UploadAttachment(ctx context.Context, in *UploadAttachmentReq, opts ...grpc.CallOption) (*UploadAttachmentReply, error)
+	// 作者在发布后的一段时间内编辑评论
+	EditComment(ctx context.Context, in *EditCommentReq, opts ...grpc.CallOption) (*EditCommentReply, error)
+	// 查评论的编辑历史(管理后台)
+	GetCommentHistory(ctx context.Context, in *GetCommentHistoryReq, opts ...grpc.CallOption) (*GetCommentHistoryReply, error)
+	// 按ID查评论, 包含主题和所在的楼层
+	GetComment(ctx context.Context, in *GetCommentReq, opts ...grpc.CallOption) (*GetCommentReply, error)
+	// 按ID批量查评论
+	BatchGetComments(ctx context.Context, in *BatchGetCommentsReq, opts ...grpc.CallOption) (*BatchGetCommentsReply, error)
+	// 定位评论在 ListComment 和 ListReply 中的页码, 用于从通知跳转到评论
+	LocateComment(ctx context.Context, in *LocateCommentReq, opts ...grpc.CallOption) (*LocateCommentReply, error)
+	// 查用户发过的评论, 新的在前
+	ListMemberComments(ctx context.Context, in *ListMemberCommentsReq, opts ...grpc.CallOption) (*ListMemberCommentsReply, error)
+	// 导出用户的全部评论、点赞和举报(管理后台), 每条回复为一行 JSON
+	ExportMemberData(ctx context.Context, in *ExportMemberDataReq, opts ...grpc.CallOption) (CommentService_ExportMemberDataClient, error)
+	// 抹除用户的全部评论内容和身份信息(管理后台), 由 comment job 异步执行
+	EraseMember(ctx context.Context, in *EraseMemberReq, opts ...grpc.CallOption) (*EraseMemberReply, error)
+	// 查抹除任务的进度
+	GetMemberErasure(ctx context.Context, in *GetMemberErasureReq, opts ...grpc.CallOption) (*GetMemberErasureReply, error)
 }
 
 type commentServiceClient struct {
@@ -83,6 +125,218 @@ func (c *commentServiceClient) ListReply(ctx context.Context, in *ListReplyReq,
 	return out, nil
 }
 
+func (c *commentServiceClient) ReportComment(ctx context.Context, in *ReportCommentReq, opts ...grpc.CallOption) (*ReportCommentReply, error) {
+	out := new(ReportCommentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ReportComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListReportedComment(ctx context.Context, in *ListReportedCommentReq, opts ...grpc.CallOption) (*ListReportedCommentReply, error) {
+	out := new(ListReportedCommentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ListReportedComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) BlockMember(ctx context.Context, in *BlockMemberReq, opts ...grpc.CallOption) (*BlockMemberReply, error) {
+	out := new(BlockMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/BlockMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) UnblockMember(ctx context.Context, in *UnblockMemberReq, opts ...grpc.CallOption) (*UnblockMemberReply, error) {
+	out := new(UnblockMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/UnblockMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListBlocked(ctx context.Context, in *ListBlockedReq, opts ...grpc.CallOption) (*ListBlockedReply, error) {
+	out := new(ListBlockedReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ListBlocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) BanMember(ctx context.Context, in *BanMemberReq, opts ...grpc.CallOption) (*BanMemberReply, error) {
+	out := new(BanMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/BanMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) UnbanMember(ctx context.Context, in *UnbanMemberReq, opts ...grpc.CallOption) (*UnbanMemberReply, error) {
+	out := new(UnbanMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/UnbanMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListBanned(ctx context.Context, in *ListBannedReq, opts ...grpc.CallOption) (*ListBannedReply, error) {
+	out := new(ListBannedReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ListBanned", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) MuteMember(ctx context.Context, in *MuteMemberReq, opts ...grpc.CallOption) (*MuteMemberReply, error) {
+	out := new(MuteMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/MuteMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) UnmuteMember(ctx context.Context, in *UnmuteMemberReq, opts ...grpc.CallOption) (*UnmuteMemberReply, error) {
+	out := new(UnmuteMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/UnmuteMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListMuted(ctx context.Context, in *ListMutedReq, opts ...grpc.CallOption) (*ListMutedReply, error) {
+	out := new(ListMutedReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ListMuted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) UploadAttachment(ctx context.Context, in *UploadAttachmentReq, opts ...grpc.CallOption) (*UploadAttachmentReply, error) {
+	out := new(UploadAttachmentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/UploadAttachment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) EditComment(ctx context.Context, in *EditCommentReq, opts ...grpc.CallOption) (*EditCommentReply, error) {
+	out := new(EditCommentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/EditComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) GetCommentHistory(ctx context.Context, in *GetCommentHistoryReq, opts ...grpc.CallOption) (*GetCommentHistoryReply, error) {
+	out := new(GetCommentHistoryReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/GetCommentHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) GetComment(ctx context.Context, in *GetCommentReq, opts ...grpc.CallOption) (*GetCommentReply, error) {
+	out := new(GetCommentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/GetComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) BatchGetComments(ctx context.Context, in *BatchGetCommentsReq, opts ...grpc.CallOption) (*BatchGetCommentsReply, error) {
+	out := new(BatchGetCommentsReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/BatchGetComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) LocateComment(ctx context.Context, in *LocateCommentReq, opts ...grpc.CallOption) (*LocateCommentReply, error) {
+	out := new(LocateCommentReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/LocateComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListMemberComments(ctx context.Context, in *ListMemberCommentsReq, opts ...grpc.CallOption) (*ListMemberCommentsReply, error) {
+	out := new(ListMemberCommentsReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/ListMemberComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ExportMemberData(ctx context.Context, in *ExportMemberDataReq, opts ...grpc.CallOption) (CommentService_ExportMemberDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommentService_ServiceDesc.Streams[0], "/comment.service.v1.CommentService/ExportMemberData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commentServiceExportMemberDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommentService_ExportMemberDataClient interface {
+	Recv() (*ExportMemberDataReply, error)
+	grpc.ClientStream
+}
+
+type commentServiceExportMemberDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *commentServiceExportMemberDataClient) Recv() (*ExportMemberDataReply, error) {
+	m := new(ExportMemberDataReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commentServiceClient) EraseMember(ctx context.Context, in *EraseMemberReq, opts ...grpc.CallOption) (*EraseMemberReply, error) {
+	out := new(EraseMemberReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/EraseMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) GetMemberErasure(ctx context.Context, in *GetMemberErasureReq, opts ...grpc.CallOption) (*GetMemberErasureReply, error) {
+	out := new(GetMemberErasureReply)
+	err := c.cc.Invoke(ctx, "/comment.service.v1.CommentService/GetMemberErasure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommentServiceServer is the server API for CommentService service.
 // All implementations must embed UnimplementedCommentServiceServer
 // for forward compatibility
@@ -97,6 +351,48 @@ type CommentServiceServer interface {
 	ListComment(context.Context, *ListCommentReq) (*ListCommentReply, error)
 	// 查回复
 	ListReply(context.Context, *ListReplyReq) (*ListReplyReply, error)
+	// 举报评论
+	ReportComment(context.Context, *ReportCommentReq) (*ReportCommentReply, error)
+	// 查被举报最多的评论(管理后台)
+	ListReportedComment(context.Context, *ListReportedCommentReq) (*ListReportedCommentReply, error)
+	// 拉黑用户, 禁止其在主题作者的内容下评论
+	BlockMember(context.Context, *BlockMemberReq) (*BlockMemberReply, error)
+	// 取消拉黑
+	UnblockMember(context.Context, *UnblockMemberReq) (*UnblockMemberReply, error)
+	// 查拉黑的用户
+	ListBlocked(context.Context, *ListBlockedReq) (*ListBlockedReply, error)
+	// 全站封禁用户(管理后台)
+	BanMember(context.Context, *BanMemberReq) (*BanMemberReply, error)
+	// 取消全站封禁(管理后台)
+	UnbanMember(context.Context, *UnbanMemberReq) (*UnbanMemberReply, error)
+	// 查全站封禁的用户(管理后台)
+	ListBanned(context.Context, *ListBannedReq) (*ListBannedReply, error)
+	// 屏蔽用户, 查评论时不再看到其评论
+	MuteMember(context.Context, *MuteMemberReq) (*MuteMemberReply, error)
+	// 取消屏蔽
+	UnmuteMember(context.Context, *UnmuteMemberReq) (*UnmuteMemberReply, error)
+	// 查屏蔽的用户
+	ListMuted(context.Context, *ListMutedReq) (*ListMutedReply, error)
+	// 上传评论图片, 返回的 token 用于 CreateCommentReq.attachments
+	UploadAttachment(context.Context, *UploadAttachmentReq) (*UploadAttachmentReply, error)
+	// 作者在发布后的一段时间内编辑评论
+	EditComment(context.Context, *EditCommentReq) (*EditCommentReply, error)
+	// 查评论的编辑历史(管理后台)
+	GetCommentHistory(context.Context, *GetCommentHistoryReq) (*GetCommentHistoryReply, error)
+	// 按ID查评论, 包含主题和所在的楼层
+	GetComment(context.Context, *GetCommentReq) (*GetCommentReply, error)
+	// 按ID批量查评论
+	BatchGetComments(context.Context, *BatchGetCommentsReq) (*BatchGetCommentsReply, error)
+	// 定位评论在 ListComment 和 ListReply 中的页码, 用于从通知跳转到评论
+	LocateComment(context.Context, *LocateCommentReq) (*LocateCommentReply, error)
+	// 查用户发过的评论, 新的在前
+	ListMemberComments(context.Context, *ListMemberCommentsReq) (*ListMemberCommentsReply, error)
+	// 导出用户的全部评论、点赞和举报(管理后台), 每条回复为一行 JSON
+	ExportMemberData(*ExportMemberDataReq, CommentService_ExportMemberDataServer) error
+	// 抹除用户的全部评论内容和身份信息(管理后台), 由 comment job 异步执行
+	EraseMember(context.Context, *EraseMemberReq) (*EraseMemberReply, error)
+	// 查抹除任务的进度
+	GetMemberErasure(context.Context, *GetMemberErasureReq) (*GetMemberErasureReply, error)
 	mustEmbedUnimplementedCommentServiceServer()
 }
 
@@ -119,6 +415,69 @@ func (UnimplementedCommentServiceServer) ListComment(context.Context, *ListComme
 func (UnimplementedCommentServiceServer) ListReply(context.Context, *ListReplyReq) (*ListReplyReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListReply not implemented")
 }
+func (UnimplementedCommentServiceServer) ReportComment(context.Context, *ReportCommentReq) (*ReportCommentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportComment not implemented")
+}
+func (UnimplementedCommentServiceServer) ListReportedComment(context.Context, *ListReportedCommentReq) (*ListReportedCommentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReportedComment not implemented")
+}
+func (UnimplementedCommentServiceServer) BlockMember(context.Context, *BlockMemberReq) (*BlockMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockMember not implemented")
+}
+func (UnimplementedCommentServiceServer) UnblockMember(context.Context, *UnblockMemberReq) (*UnblockMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockMember not implemented")
+}
+func (UnimplementedCommentServiceServer) ListBlocked(context.Context, *ListBlockedReq) (*ListBlockedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlocked not implemented")
+}
+func (UnimplementedCommentServiceServer) BanMember(context.Context, *BanMemberReq) (*BanMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanMember not implemented")
+}
+func (UnimplementedCommentServiceServer) UnbanMember(context.Context, *UnbanMemberReq) (*UnbanMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnbanMember not implemented")
+}
+func (UnimplementedCommentServiceServer) ListBanned(context.Context, *ListBannedReq) (*ListBannedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBanned not implemented")
+}
+func (UnimplementedCommentServiceServer) MuteMember(context.Context, *MuteMemberReq) (*MuteMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MuteMember not implemented")
+}
+func (UnimplementedCommentServiceServer) UnmuteMember(context.Context, *UnmuteMemberReq) (*UnmuteMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnmuteMember not implemented")
+}
+func (UnimplementedCommentServiceServer) ListMuted(context.Context, *ListMutedReq) (*ListMutedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMuted not implemented")
+}
+func (UnimplementedCommentServiceServer) UploadAttachment(context.Context, *UploadAttachmentReq) (*UploadAttachmentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedCommentServiceServer) EditComment(context.Context, *EditCommentReq) (*EditCommentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditComment not implemented")
+}
+func (UnimplementedCommentServiceServer) GetCommentHistory(context.Context, *GetCommentHistoryReq) (*GetCommentHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommentHistory not implemented")
+}
+func (UnimplementedCommentServiceServer) GetComment(context.Context, *GetCommentReq) (*GetCommentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComment not implemented")
+}
+func (UnimplementedCommentServiceServer) BatchGetComments(context.Context, *BatchGetCommentsReq) (*BatchGetCommentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetComments not implemented")
+}
+func (UnimplementedCommentServiceServer) LocateComment(context.Context, *LocateCommentReq) (*LocateCommentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LocateComment not implemented")
+}
+func (UnimplementedCommentServiceServer) ListMemberComments(context.Context, *ListMemberCommentsReq) (*ListMemberCommentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMemberComments not implemented")
+}
+func (UnimplementedCommentServiceServer) ExportMemberData(*ExportMemberDataReq, CommentService_ExportMemberDataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMemberData not implemented")
+}
+func (UnimplementedCommentServiceServer) EraseMember(context.Context, *EraseMemberReq) (*EraseMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseMember not implemented")
+}
+func (UnimplementedCommentServiceServer) GetMemberErasure(context.Context, *GetMemberErasureReq) (*GetMemberErasureReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemberErasure not implemented")
+}
 func (UnimplementedCommentServiceServer) mustEmbedUnimplementedCommentServiceServer() {}
 
 // UnsafeCommentServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -222,6 +581,387 @@ func _CommentService_ListReply_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommentService_ReportComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ReportComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ReportComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ReportComment(ctx, req.(*ReportCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListReportedComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportedCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListReportedComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ListReportedComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListReportedComment(ctx, req.(*ListReportedCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_BlockMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).BlockMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/BlockMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).BlockMember(ctx, req.(*BlockMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_UnblockMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnblockMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).UnblockMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/UnblockMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).UnblockMember(ctx, req.(*UnblockMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ListBlocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListBlocked(ctx, req.(*ListBlockedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_BanMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).BanMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/BanMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).BanMember(ctx, req.(*BanMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_UnbanMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbanMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).UnbanMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/UnbanMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).UnbanMember(ctx, req.(*UnbanMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListBanned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBannedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListBanned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ListBanned",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListBanned(ctx, req.(*ListBannedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_MuteMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuteMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).MuteMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/MuteMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).MuteMember(ctx, req.(*MuteMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_UnmuteMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnmuteMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).UnmuteMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/UnmuteMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).UnmuteMember(ctx, req.(*UnmuteMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListMuted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMutedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListMuted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ListMuted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListMuted(ctx, req.(*ListMutedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_UploadAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadAttachmentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).UploadAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/UploadAttachment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).UploadAttachment(ctx, req.(*UploadAttachmentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_EditComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).EditComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/EditComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).EditComment(ctx, req.(*EditCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_GetCommentHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetCommentHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/GetCommentHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetCommentHistory(ctx, req.(*GetCommentHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_GetComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/GetComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetComment(ctx, req.(*GetCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_BatchGetComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetCommentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).BatchGetComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/BatchGetComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).BatchGetComments(ctx, req.(*BatchGetCommentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_LocateComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocateCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).LocateComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/LocateComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).LocateComment(ctx, req.(*LocateCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListMemberComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMemberCommentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListMemberComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/ListMemberComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListMemberComments(ctx, req.(*ListMemberCommentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ExportMemberData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMemberDataReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommentServiceServer).ExportMemberData(m, &commentServiceExportMemberDataServer{stream})
+}
+
+type CommentService_ExportMemberDataServer interface {
+	Send(*ExportMemberDataReply) error
+	grpc.ServerStream
+}
+
+type commentServiceExportMemberDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *commentServiceExportMemberDataServer) Send(m *ExportMemberDataReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CommentService_EraseMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseMemberReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).EraseMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/EraseMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).EraseMember(ctx, req.(*EraseMemberReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_GetMemberErasure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberErasureReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetMemberErasure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/comment.service.v1.CommentService/GetMemberErasure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetMemberErasure(ctx, req.(*GetMemberErasureReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CommentService_ServiceDesc is the grpc.ServiceDesc for CommentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -249,7 +989,93 @@ var CommentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListReply",
 			Handler:    _CommentService_ListReply_Handler,
 		},
+		{
+			MethodName: "ReportComment",
+			Handler:    _CommentService_ReportComment_Handler,
+		},
+		{
+			MethodName: "ListReportedComment",
+			Handler:    _CommentService_ListReportedComment_Handler,
+		},
+		{
+			MethodName: "BlockMember",
+			Handler:    _CommentService_BlockMember_Handler,
+		},
+		{
+			MethodName: "UnblockMember",
+			Handler:    _CommentService_UnblockMember_Handler,
+		},
+		{
+			MethodName: "ListBlocked",
+			Handler:    _CommentService_ListBlocked_Handler,
+		},
+		{
+			MethodName: "BanMember",
+			Handler:    _CommentService_BanMember_Handler,
+		},
+		{
+			MethodName: "UnbanMember",
+			Handler:    _CommentService_UnbanMember_Handler,
+		},
+		{
+			MethodName: "ListBanned",
+			Handler:    _CommentService_ListBanned_Handler,
+		},
+		{
+			MethodName: "MuteMember",
+			Handler:    _CommentService_MuteMember_Handler,
+		},
+		{
+			MethodName: "UnmuteMember",
+			Handler:    _CommentService_UnmuteMember_Handler,
+		},
+		{
+			MethodName: "ListMuted",
+			Handler:    _CommentService_ListMuted_Handler,
+		},
+		{
+			MethodName: "UploadAttachment",
+			Handler:    _CommentService_UploadAttachment_Handler,
+		},
+		{
+			MethodName: "EditComment",
+			Handler:    _CommentService_EditComment_Handler,
+		},
+		{
+			MethodName: "GetCommentHistory",
+			Handler:    _CommentService_GetCommentHistory_Handler,
+		},
+		{
+			MethodName: "GetComment",
+			Handler:    _CommentService_GetComment_Handler,
+		},
+		{
+			MethodName: "BatchGetComments",
+			Handler:    _CommentService_BatchGetComments_Handler,
+		},
+		{
+			MethodName: "LocateComment",
+			Handler:    _CommentService_LocateComment_Handler,
+		},
+		{
+			MethodName: "ListMemberComments",
+			Handler:    _CommentService_ListMemberComments_Handler,
+		},
+		{
+			MethodName: "EraseMember",
+			Handler:    _CommentService_EraseMember_Handler,
+		},
+		{
+			MethodName: "GetMemberErasure",
+			Handler:    _CommentService_GetMemberErasure_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportMemberData",
+			Handler:       _CommentService_ExportMemberData_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "service.proto",
+	Metadata: "api/comment/service/v1/service.proto",
 }
@@ -1,227 +1,911 @@
 // Code generated by protoc-gen-go-http. DO NOT EDIT.
+// versions:
+// protoc-gen-go-http v2.0.0
 
 package v1
 
 import (
 	context "context"
-	http1 "github.com/go-kratos/kratos/v2/transport/http"
+	http "github.com/go-kratos/kratos/v2/transport/http"
 	binding "github.com/go-kratos/kratos/v2/transport/http/binding"
-	mux "github.com/gorilla/mux"
-	http "net/http"
 )
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the kratos package it is being compiled against.
-var _ = new(http.Request)
 var _ = new(context.Context)
-var _ = binding.MapProto
-var _ = mux.NewRouter
+var _ = binding.EncodeURL
 
-const _ = http1.SupportPackageIsVersion1
-
-type CommentServiceHandler interface {
-	CreateComment(context.Context, *CreateCommentReq) (*CreateCommentReply, error)
+const _ = http.SupportPackageIsVersion1
 
+type CommentServiceHTTPServer interface {
 	CreateSubject(context.Context, *CreateSubjectReq) (*CreateSubjectReply, error)
-
+	CreateComment(context.Context, *CreateCommentReq) (*CreateCommentReply, error)
 	DeleteComment(context.Context, *DeleteCommentReq) (*DeleteCommentReply, error)
-
 	ListComment(context.Context, *ListCommentReq) (*ListCommentReply, error)
-
 	ListReply(context.Context, *ListReplyReq) (*ListReplyReply, error)
+	ReportComment(context.Context, *ReportCommentReq) (*ReportCommentReply, error)
+	ListReportedComment(context.Context, *ListReportedCommentReq) (*ListReportedCommentReply, error)
+	BlockMember(context.Context, *BlockMemberReq) (*BlockMemberReply, error)
+	UnblockMember(context.Context, *UnblockMemberReq) (*UnblockMemberReply, error)
+	ListBlocked(context.Context, *ListBlockedReq) (*ListBlockedReply, error)
+	BanMember(context.Context, *BanMemberReq) (*BanMemberReply, error)
+	UnbanMember(context.Context, *UnbanMemberReq) (*UnbanMemberReply, error)
+	ListBanned(context.Context, *ListBannedReq) (*ListBannedReply, error)
+	MuteMember(context.Context, *MuteMemberReq) (*MuteMemberReply, error)
+	UnmuteMember(context.Context, *UnmuteMemberReq) (*UnmuteMemberReply, error)
+	ListMuted(context.Context, *ListMutedReq) (*ListMutedReply, error)
+	UploadAttachment(context.Context, *UploadAttachmentReq) (*UploadAttachmentReply, error)
+	EditComment(context.Context, *EditCommentReq) (*EditCommentReply, error)
+	GetCommentHistory(context.Context, *GetCommentHistoryReq) (*GetCommentHistoryReply, error)
+	GetComment(context.Context, *GetCommentReq) (*GetCommentReply, error)
+	BatchGetComments(context.Context, *BatchGetCommentsReq) (*BatchGetCommentsReply, error)
+	LocateComment(context.Context, *LocateCommentReq) (*LocateCommentReply, error)
+	ListMemberComments(context.Context, *ListMemberCommentsReq) (*ListMemberCommentsReply, error)
+	EraseMember(context.Context, *EraseMemberReq) (*EraseMemberReply, error)
+	GetMemberErasure(context.Context, *GetMemberErasureReq) (*GetMemberErasureReply, error)
 }
 
-func NewCommentServiceHandler(srv CommentServiceHandler, opts ...http1.HandleOption) http.Handler {
-	h := http1.DefaultHandleOptions()
-	for _, o := range opts {
-		o(&h)
-	}
-	r := mux.NewRouter()
+func RegisterCommentServiceHTTPServer(s *http.Server, srv CommentServiceHTTPServer) {
+	r := s.Route("/")
+	r.POST("/comment.service.v1.CommentService/CreateSubject", _CommentService_CreateSubject0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/CreateComment", _CommentService_CreateComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/DeleteComment", _CommentService_DeleteComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListComment", _CommentService_ListComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListReply", _CommentService_ListReply0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ReportComment", _CommentService_ReportComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListReportedComment", _CommentService_ListReportedComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/BlockMember", _CommentService_BlockMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/UnblockMember", _CommentService_UnblockMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListBlocked", _CommentService_ListBlocked0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/BanMember", _CommentService_BanMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/UnbanMember", _CommentService_UnbanMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListBanned", _CommentService_ListBanned0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/MuteMember", _CommentService_MuteMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/UnmuteMember", _CommentService_UnmuteMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListMuted", _CommentService_ListMuted0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/UploadAttachment", _CommentService_UploadAttachment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/EditComment", _CommentService_EditComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/GetCommentHistory", _CommentService_GetCommentHistory0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/GetComment", _CommentService_GetComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/BatchGetComments", _CommentService_BatchGetComments0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/LocateComment", _CommentService_LocateComment0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/ListMemberComments", _CommentService_ListMemberComments0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/EraseMember", _CommentService_EraseMember0_HTTP_Handler(srv))
+	r.POST("/comment.service.v1.CommentService/GetMemberErasure", _CommentService_GetMemberErasure0_HTTP_Handler(srv))
+}
 
-	r.HandleFunc("/comment.service.v1.CommentService/CreateSubject", func(w http.ResponseWriter, r *http.Request) {
+func _CommentService_CreateSubject0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
 		var in CreateSubjectReq
-		if err := h.Decode(r, &in); err != nil {
-			h.Error(w, r, err)
-			return
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-
-		next := func(ctx context.Context, req interface{}) (interface{}, error) {
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/CreateSubject")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
 			return srv.CreateSubject(ctx, req.(*CreateSubjectReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
-		if h.Middleware != nil {
-			next = h.Middleware(next)
+		reply := out.(*CreateSubjectReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_CreateComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CreateCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		out, err := next(r.Context(), &in)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/CreateComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreateComment(ctx, req.(*CreateCommentReq))
+		})
+		out, err := h(ctx, &in)
 		if err != nil {
-			h.Error(w, r, err)
-			return
+			return err
 		}
-		reply := out.(*CreateSubjectReply)
-		if err := h.Encode(w, r, reply); err != nil {
-			h.Error(w, r, err)
+		reply := out.(*CreateCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_DeleteComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in DeleteCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/DeleteComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.DeleteComment(ctx, req.(*DeleteCommentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
-	}).Methods("POST")
+		reply := out.(*DeleteCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	r.HandleFunc("/comment.service.v1.CommentService/CreateComment", func(w http.ResponseWriter, r *http.Request) {
-		var in CreateCommentReq
-		if err := h.Decode(r, &in); err != nil {
-			h.Error(w, r, err)
-			return
+func _CommentService_ListComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListComment(ctx, req.(*ListCommentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-		next := func(ctx context.Context, req interface{}) (interface{}, error) {
-			return srv.CreateComment(ctx, req.(*CreateCommentReq))
+func _CommentService_ListReply0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListReplyReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		if h.Middleware != nil {
-			next = h.Middleware(next)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListReply")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListReply(ctx, req.(*ListReplyReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListReplyReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_ReportComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ReportCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		out, err := next(r.Context(), &in)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ReportComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ReportComment(ctx, req.(*ReportCommentReq))
+		})
+		out, err := h(ctx, &in)
 		if err != nil {
-			h.Error(w, r, err)
-			return
+			return err
 		}
-		reply := out.(*CreateCommentReply)
-		if err := h.Encode(w, r, reply); err != nil {
-			h.Error(w, r, err)
+		reply := out.(*ReportCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_ListReportedComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListReportedCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-	}).Methods("POST")
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListReportedComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListReportedComment(ctx, req.(*ListReportedCommentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListReportedCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	r.HandleFunc("/comment.service.v1.CommentService/DeleteComment", func(w http.ResponseWriter, r *http.Request) {
-		var in DeleteCommentReq
-		if err := h.Decode(r, &in); err != nil {
-			h.Error(w, r, err)
-			return
+func _CommentService_BlockMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in BlockMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/BlockMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.BlockMember(ctx, req.(*BlockMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*BlockMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-		next := func(ctx context.Context, req interface{}) (interface{}, error) {
-			return srv.DeleteComment(ctx, req.(*DeleteCommentReq))
+func _CommentService_UnblockMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UnblockMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		if h.Middleware != nil {
-			next = h.Middleware(next)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/UnblockMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UnblockMember(ctx, req.(*UnblockMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
-		out, err := next(r.Context(), &in)
+		reply := out.(*UnblockMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_ListBlocked0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListBlockedReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListBlocked")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListBlocked(ctx, req.(*ListBlockedReq))
+		})
+		out, err := h(ctx, &in)
 		if err != nil {
-			h.Error(w, r, err)
-			return
+			return err
 		}
-		reply := out.(*DeleteCommentReply)
-		if err := h.Encode(w, r, reply); err != nil {
-			h.Error(w, r, err)
+		reply := out.(*ListBlockedReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_BanMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in BanMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/BanMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.BanMember(ctx, req.(*BanMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
-	}).Methods("POST")
+		reply := out.(*BanMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	r.HandleFunc("/comment.service.v1.CommentService/ListComment", func(w http.ResponseWriter, r *http.Request) {
-		var in ListCommentReq
-		if err := h.Decode(r, &in); err != nil {
-			h.Error(w, r, err)
-			return
+func _CommentService_UnbanMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UnbanMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/UnbanMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UnbanMember(ctx, req.(*UnbanMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
+		reply := out.(*UnbanMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-		next := func(ctx context.Context, req interface{}) (interface{}, error) {
-			return srv.ListComment(ctx, req.(*ListCommentReq))
+func _CommentService_ListBanned0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListBannedReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		if h.Middleware != nil {
-			next = h.Middleware(next)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListBanned")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListBanned(ctx, req.(*ListBannedReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListBannedReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_MuteMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in MuteMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		out, err := next(r.Context(), &in)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/MuteMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.MuteMember(ctx, req.(*MuteMemberReq))
+		})
+		out, err := h(ctx, &in)
 		if err != nil {
-			h.Error(w, r, err)
-			return
+			return err
 		}
-		reply := out.(*ListCommentReply)
-		if err := h.Encode(w, r, reply); err != nil {
-			h.Error(w, r, err)
+		reply := out.(*MuteMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_UnmuteMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UnmuteMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-	}).Methods("POST")
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/UnmuteMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UnmuteMember(ctx, req.(*UnmuteMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*UnmuteMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	r.HandleFunc("/comment.service.v1.CommentService/ListReply", func(w http.ResponseWriter, r *http.Request) {
-		var in ListReplyReq
-		if err := h.Decode(r, &in); err != nil {
-			h.Error(w, r, err)
-			return
+func _CommentService_ListMuted0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListMutedReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListMuted")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListMuted(ctx, req.(*ListMutedReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
+		reply := out.(*ListMutedReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-		next := func(ctx context.Context, req interface{}) (interface{}, error) {
-			return srv.ListReply(ctx, req.(*ListReplyReq))
+func _CommentService_UploadAttachment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UploadAttachmentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-		if h.Middleware != nil {
-			next = h.Middleware(next)
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/UploadAttachment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UploadAttachment(ctx, req.(*UploadAttachmentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
 		}
-		out, err := next(r.Context(), &in)
+		reply := out.(*UploadAttachmentReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_EditComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in EditCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/EditComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.EditComment(ctx, req.(*EditCommentReq))
+		})
+		out, err := h(ctx, &in)
 		if err != nil {
-			h.Error(w, r, err)
-			return
+			return err
 		}
-		reply := out.(*ListReplyReply)
-		if err := h.Encode(w, r, reply); err != nil {
-			h.Error(w, r, err)
+		reply := out.(*EditCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_GetCommentHistory0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetCommentHistoryReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
 		}
-	}).Methods("POST")
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/GetCommentHistory")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetCommentHistory(ctx, req.(*GetCommentHistoryReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetCommentHistoryReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	return r
+func _CommentService_GetComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/GetComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetComment(ctx, req.(*GetCommentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetCommentReply)
+		return ctx.Result(200, reply)
+	}
 }
 
-type CommentServiceHTTPClient interface {
-	CreateComment(ctx context.Context, req *CreateCommentReq, opts ...http1.CallOption) (rsp *CreateCommentReply, err error)
+func _CommentService_BatchGetComments0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in BatchGetCommentsReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/BatchGetComments")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.BatchGetComments(ctx, req.(*BatchGetCommentsReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*BatchGetCommentsReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	CreateSubject(ctx context.Context, req *CreateSubjectReq, opts ...http1.CallOption) (rsp *CreateSubjectReply, err error)
+func _CommentService_LocateComment0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in LocateCommentReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/LocateComment")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.LocateComment(ctx, req.(*LocateCommentReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*LocateCommentReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _CommentService_ListMemberComments0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListMemberCommentsReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/ListMemberComments")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListMemberComments(ctx, req.(*ListMemberCommentsReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListMemberCommentsReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	DeleteComment(ctx context.Context, req *DeleteCommentReq, opts ...http1.CallOption) (rsp *DeleteCommentReply, err error)
+func _CommentService_EraseMember0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in EraseMemberReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/EraseMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.EraseMember(ctx, req.(*EraseMemberReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*EraseMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	ListComment(ctx context.Context, req *ListCommentReq, opts ...http1.CallOption) (rsp *ListCommentReply, err error)
+func _CommentService_GetMemberErasure0_HTTP_Handler(srv CommentServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetMemberErasureReq
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/comment.service.v1.CommentService/GetMemberErasure")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetMemberErasure(ctx, req.(*GetMemberErasureReq))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetMemberErasureReply)
+		return ctx.Result(200, reply)
+	}
+}
 
-	ListReply(ctx context.Context, req *ListReplyReq, opts ...http1.CallOption) (rsp *ListReplyReply, err error)
+type CommentServiceHTTPClient interface {
+	CreateSubject(ctx context.Context, req *CreateSubjectReq, opts ...http.CallOption) (rsp *CreateSubjectReply, err error)
+	CreateComment(ctx context.Context, req *CreateCommentReq, opts ...http.CallOption) (rsp *CreateCommentReply, err error)
+	DeleteComment(ctx context.Context, req *DeleteCommentReq, opts ...http.CallOption) (rsp *DeleteCommentReply, err error)
+	ListComment(ctx context.Context, req *ListCommentReq, opts ...http.CallOption) (rsp *ListCommentReply, err error)
+	ListReply(ctx context.Context, req *ListReplyReq, opts ...http.CallOption) (rsp *ListReplyReply, err error)
+	ReportComment(ctx context.Context, req *ReportCommentReq, opts ...http.CallOption) (rsp *ReportCommentReply, err error)
+	ListReportedComment(ctx context.Context, req *ListReportedCommentReq, opts ...http.CallOption) (rsp *ListReportedCommentReply, err error)
+	BlockMember(ctx context.Context, req *BlockMemberReq, opts ...http.CallOption) (rsp *BlockMemberReply, err error)
+	UnblockMember(ctx context.Context, req *UnblockMemberReq, opts ...http.CallOption) (rsp *UnblockMemberReply, err error)
+	ListBlocked(ctx context.Context, req *ListBlockedReq, opts ...http.CallOption) (rsp *ListBlockedReply, err error)
+	BanMember(ctx context.Context, req *BanMemberReq, opts ...http.CallOption) (rsp *BanMemberReply, err error)
+	UnbanMember(ctx context.Context, req *UnbanMemberReq, opts ...http.CallOption) (rsp *UnbanMemberReply, err error)
+	ListBanned(ctx context.Context, req *ListBannedReq, opts ...http.CallOption) (rsp *ListBannedReply, err error)
+	MuteMember(ctx context.Context, req *MuteMemberReq, opts ...http.CallOption) (rsp *MuteMemberReply, err error)
+	UnmuteMember(ctx context.Context, req *UnmuteMemberReq, opts ...http.CallOption) (rsp *UnmuteMemberReply, err error)
+	ListMuted(ctx context.Context, req *ListMutedReq, opts ...http.CallOption) (rsp *ListMutedReply, err error)
+	UploadAttachment(ctx context.Context, req *UploadAttachmentReq, opts ...http.CallOption) (rsp *UploadAttachmentReply, err error)
+	EditComment(ctx context.Context, req *EditCommentReq, opts ...http.CallOption) (rsp *EditCommentReply, err error)
+	GetCommentHistory(ctx context.Context, req *GetCommentHistoryReq, opts ...http.CallOption) (rsp *GetCommentHistoryReply, err error)
+	GetComment(ctx context.Context, req *GetCommentReq, opts ...http.CallOption) (rsp *GetCommentReply, err error)
+	BatchGetComments(ctx context.Context, req *BatchGetCommentsReq, opts ...http.CallOption) (rsp *BatchGetCommentsReply, err error)
+	LocateComment(ctx context.Context, req *LocateCommentReq, opts ...http.CallOption) (rsp *LocateCommentReply, err error)
+	ListMemberComments(ctx context.Context, req *ListMemberCommentsReq, opts ...http.CallOption) (rsp *ListMemberCommentsReply, err error)
+	EraseMember(ctx context.Context, req *EraseMemberReq, opts ...http.CallOption) (rsp *EraseMemberReply, err error)
+	GetMemberErasure(ctx context.Context, req *GetMemberErasureReq, opts ...http.CallOption) (rsp *GetMemberErasureReply, err error)
 }
 
 type CommentServiceHTTPClientImpl struct {
-	cc *http1.Client
+	cc *http.Client
 }
 
-func NewCommentServiceHTTPClient(client *http1.Client) CommentServiceHTTPClient {
+func NewCommentServiceHTTPClient(client *http.Client) CommentServiceHTTPClient {
 	return &CommentServiceHTTPClientImpl{client}
 }
 
-func (c *CommentServiceHTTPClientImpl) CreateComment(ctx context.Context, in *CreateCommentReq, opts ...http1.CallOption) (out *CreateCommentReply, err error) {
-	path := binding.EncodePath("POST", "/comment.service.v1.CommentService/CreateComment", in)
-	out = &CreateCommentReply{}
+func (c *CommentServiceHTTPClientImpl) CreateSubject(ctx context.Context, in *CreateSubjectReq, opts ...http.CallOption) (*CreateSubjectReply, error) {
+	var out CreateSubjectReply
+	pattern := "/comment.service.v1.CommentService/CreateSubject"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/CreateSubject"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) CreateComment(ctx context.Context, in *CreateCommentReq, opts ...http.CallOption) (*CreateCommentReply, error) {
+	var out CreateCommentReply
+	pattern := "/comment.service.v1.CommentService/CreateComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/CreateComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) DeleteComment(ctx context.Context, in *DeleteCommentReq, opts ...http.CallOption) (*DeleteCommentReply, error) {
+	var out DeleteCommentReply
+	pattern := "/comment.service.v1.CommentService/DeleteComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/DeleteComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	err = c.cc.Invoke(ctx, path, nil, &out, http1.Method("POST"), http1.PathPattern("/comment.service.v1.CommentService/CreateComment"))
+func (c *CommentServiceHTTPClientImpl) ListComment(ctx context.Context, in *ListCommentReq, opts ...http.CallOption) (*ListCommentReply, error) {
+	var out ListCommentReply
+	pattern := "/comment.service.v1.CommentService/ListComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	return
+func (c *CommentServiceHTTPClientImpl) ListReply(ctx context.Context, in *ListReplyReq, opts ...http.CallOption) (*ListReplyReply, error) {
+	var out ListReplyReply
+	pattern := "/comment.service.v1.CommentService/ListReply"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListReply"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
 }
 
-func (c *CommentServiceHTTPClientImpl) CreateSubject(ctx context.Context, in *CreateSubjectReq, opts ...http1.CallOption) (out *CreateSubjectReply, err error) {
-	path := binding.EncodePath("POST", "/comment.service.v1.CommentService/CreateSubject", in)
-	out = &CreateSubjectReply{}
+func (c *CommentServiceHTTPClientImpl) ReportComment(ctx context.Context, in *ReportCommentReq, opts ...http.CallOption) (*ReportCommentReply, error) {
+	var out ReportCommentReply
+	pattern := "/comment.service.v1.CommentService/ReportComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ReportComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	err = c.cc.Invoke(ctx, path, nil, &out, http1.Method("POST"), http1.PathPattern("/comment.service.v1.CommentService/CreateSubject"))
+func (c *CommentServiceHTTPClientImpl) ListReportedComment(ctx context.Context, in *ListReportedCommentReq, opts ...http.CallOption) (*ListReportedCommentReply, error) {
+	var out ListReportedCommentReply
+	pattern := "/comment.service.v1.CommentService/ListReportedComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListReportedComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	return
+func (c *CommentServiceHTTPClientImpl) BlockMember(ctx context.Context, in *BlockMemberReq, opts ...http.CallOption) (*BlockMemberReply, error) {
+	var out BlockMemberReply
+	pattern := "/comment.service.v1.CommentService/BlockMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/BlockMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
 }
 
-func (c *CommentServiceHTTPClientImpl) DeleteComment(ctx context.Context, in *DeleteCommentReq, opts ...http1.CallOption) (out *DeleteCommentReply, err error) {
-	path := binding.EncodePath("POST", "/comment.service.v1.CommentService/DeleteComment", in)
-	out = &DeleteCommentReply{}
+func (c *CommentServiceHTTPClientImpl) UnblockMember(ctx context.Context, in *UnblockMemberReq, opts ...http.CallOption) (*UnblockMemberReply, error) {
+	var out UnblockMemberReply
+	pattern := "/comment.service.v1.CommentService/UnblockMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/UnblockMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	err = c.cc.Invoke(ctx, path, nil, &out, http1.Method("POST"), http1.PathPattern("/comment.service.v1.CommentService/DeleteComment"))
+func (c *CommentServiceHTTPClientImpl) ListBlocked(ctx context.Context, in *ListBlockedReq, opts ...http.CallOption) (*ListBlockedReply, error) {
+	var out ListBlockedReply
+	pattern := "/comment.service.v1.CommentService/ListBlocked"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListBlocked"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	return
+func (c *CommentServiceHTTPClientImpl) BanMember(ctx context.Context, in *BanMemberReq, opts ...http.CallOption) (*BanMemberReply, error) {
+	var out BanMemberReply
+	pattern := "/comment.service.v1.CommentService/BanMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/BanMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
 }
 
-func (c *CommentServiceHTTPClientImpl) ListComment(ctx context.Context, in *ListCommentReq, opts ...http1.CallOption) (out *ListCommentReply, err error) {
-	path := binding.EncodePath("POST", "/comment.service.v1.CommentService/ListComment", in)
-	out = &ListCommentReply{}
+func (c *CommentServiceHTTPClientImpl) UnbanMember(ctx context.Context, in *UnbanMemberReq, opts ...http.CallOption) (*UnbanMemberReply, error) {
+	var out UnbanMemberReply
+	pattern := "/comment.service.v1.CommentService/UnbanMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/UnbanMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	err = c.cc.Invoke(ctx, path, nil, &out, http1.Method("POST"), http1.PathPattern("/comment.service.v1.CommentService/ListComment"))
+func (c *CommentServiceHTTPClientImpl) ListBanned(ctx context.Context, in *ListBannedReq, opts ...http.CallOption) (*ListBannedReply, error) {
+	var out ListBannedReply
+	pattern := "/comment.service.v1.CommentService/ListBanned"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListBanned"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	return
+func (c *CommentServiceHTTPClientImpl) MuteMember(ctx context.Context, in *MuteMemberReq, opts ...http.CallOption) (*MuteMemberReply, error) {
+	var out MuteMemberReply
+	pattern := "/comment.service.v1.CommentService/MuteMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/MuteMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
 }
 
-func (c *CommentServiceHTTPClientImpl) ListReply(ctx context.Context, in *ListReplyReq, opts ...http1.CallOption) (out *ListReplyReply, err error) {
-	path := binding.EncodePath("POST", "/comment.service.v1.CommentService/ListReply", in)
-	out = &ListReplyReply{}
+func (c *CommentServiceHTTPClientImpl) UnmuteMember(ctx context.Context, in *UnmuteMemberReq, opts ...http.CallOption) (*UnmuteMemberReply, error) {
+	var out UnmuteMemberReply
+	pattern := "/comment.service.v1.CommentService/UnmuteMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/UnmuteMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	err = c.cc.Invoke(ctx, path, nil, &out, http1.Method("POST"), http1.PathPattern("/comment.service.v1.CommentService/ListReply"))
+func (c *CommentServiceHTTPClientImpl) ListMuted(ctx context.Context, in *ListMutedReq, opts ...http.CallOption) (*ListMutedReply, error) {
+	var out ListMutedReply
+	pattern := "/comment.service.v1.CommentService/ListMuted"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListMuted"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
 
-	return
+func (c *CommentServiceHTTPClientImpl) UploadAttachment(ctx context.Context, in *UploadAttachmentReq, opts ...http.CallOption) (*UploadAttachmentReply, error) {
+	var out UploadAttachmentReply
+	pattern := "/comment.service.v1.CommentService/UploadAttachment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/UploadAttachment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) EditComment(ctx context.Context, in *EditCommentReq, opts ...http.CallOption) (*EditCommentReply, error) {
+	var out EditCommentReply
+	pattern := "/comment.service.v1.CommentService/EditComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/EditComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) GetCommentHistory(ctx context.Context, in *GetCommentHistoryReq, opts ...http.CallOption) (*GetCommentHistoryReply, error) {
+	var out GetCommentHistoryReply
+	pattern := "/comment.service.v1.CommentService/GetCommentHistory"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/GetCommentHistory"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) GetComment(ctx context.Context, in *GetCommentReq, opts ...http.CallOption) (*GetCommentReply, error) {
+	var out GetCommentReply
+	pattern := "/comment.service.v1.CommentService/GetComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/GetComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) BatchGetComments(ctx context.Context, in *BatchGetCommentsReq, opts ...http.CallOption) (*BatchGetCommentsReply, error) {
+	var out BatchGetCommentsReply
+	pattern := "/comment.service.v1.CommentService/BatchGetComments"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/BatchGetComments"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) LocateComment(ctx context.Context, in *LocateCommentReq, opts ...http.CallOption) (*LocateCommentReply, error) {
+	var out LocateCommentReply
+	pattern := "/comment.service.v1.CommentService/LocateComment"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/LocateComment"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) ListMemberComments(ctx context.Context, in *ListMemberCommentsReq, opts ...http.CallOption) (*ListMemberCommentsReply, error) {
+	var out ListMemberCommentsReply
+	pattern := "/comment.service.v1.CommentService/ListMemberComments"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/ListMemberComments"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) EraseMember(ctx context.Context, in *EraseMemberReq, opts ...http.CallOption) (*EraseMemberReply, error) {
+	var out EraseMemberReply
+	pattern := "/comment.service.v1.CommentService/EraseMember"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/EraseMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *CommentServiceHTTPClientImpl) GetMemberErasure(ctx context.Context, in *GetMemberErasureReq, opts ...http.CallOption) (*GetMemberErasureReply, error) {
+	var out GetMemberErasureReply
+	pattern := "/comment.service.v1.CommentService/GetMemberErasure"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/comment.service.v1.CommentService/GetMemberErasure"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
 }
@@ -0,0 +1,22 @@
+// +build wireinject
+
+// The build tag makes sure the stub is not built in the final build.
+
+package main
+
+import (
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/wire"
+
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+	"github.com/zldongly/comment/app/comment/job/internal/data"
+	"github.com/zldongly/comment/app/comment/job/internal/server"
+	"github.com/zldongly/comment/app/comment/job/internal/service"
+)
+
+// initApp init kratos application.
+func initApp(*conf.Server, *conf.Data, *conf.Job, log.Logger) (*kratos.App, func(), error) {
+	panic(wire.Build(server.ProviderSet, data.ProviderSet, biz.ProviderSet, service.ProviderSet, newApp))
+}
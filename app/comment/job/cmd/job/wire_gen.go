@@ -0,0 +1,57 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//+build !wireinject
+
+package main
+
+import (
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+	"github.com/zldongly/comment/app/comment/job/internal/data"
+	"github.com/zldongly/comment/app/comment/job/internal/server"
+	"github.com/zldongly/comment/app/comment/job/internal/service"
+)
+
+// Injectors from wire.go:
+
+// initApp init kratos application.
+func initApp(confServer *conf.Server, confData *conf.Data, job *conf.Job, logger log.Logger) (*kratos.App, func(), error) {
+	dataData, cleanup, err := data.NewData(confData, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	eventRepo := data.NewEventRepo(dataData, logger)
+	eventUsecase := biz.NewEventUsecase(eventRepo, logger)
+	commentRepo := data.NewCommentRepo(dataData, logger)
+	commentUsecase := biz.NewCommentUsecase(commentRepo, logger)
+	blockRepo := data.NewBlockRepo(dataData, logger)
+	notificationSink, cleanup2, err := data.NewNotificationSink(job, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	mentionUsecase := biz.NewMentionUsecase(job, blockRepo, notificationSink, logger)
+	replyRepo := data.NewReplyRepo(dataData, logger)
+	replyUsecase := biz.NewReplyUsecase(job, blockRepo, notificationSink, replyRepo, logger)
+	erasureRepo := data.NewErasureRepo(dataData, logger)
+	erasureUsecase := biz.NewErasureUsecase(job, erasureRepo, logger)
+	cacheRepo := data.NewCacheRepo(dataData, logger)
+	cacheUsecase := biz.NewCacheUsecase(cacheRepo, logger)
+	counterRepo := data.NewCounterRepo(dataData, logger)
+	counterUsecase := biz.NewCounterUsecase(job, counterRepo, logger)
+	reconcileRepo := data.NewReconcileRepo(dataData, logger)
+	gauge := server.NewDriftGauge()
+	reconcileUsecase := biz.NewReconcileUsecase(job, reconcileRepo, gauge, logger)
+	jobService := service.NewJobService(commentUsecase, mentionUsecase, replyUsecase, erasureUsecase, cacheUsecase, counterUsecase, reconcileUsecase, logger)
+	eventServer := server.NewEventServer(confServer, eventUsecase, jobService, logger)
+	reconcileServer := server.NewReconcileServer(job, jobService, logger)
+	httpServer := server.NewHTTPServer(confServer, logger)
+	app := newApp(logger, eventServer, reconcileServer, httpServer)
+	return app, func() {
+		cleanup2()
+		cleanup()
+	}, nil
+}
@@ -0,0 +1,40 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// CacheRepo broadcasts the changes of subjects to the comment service instances.
+type CacheRepo interface {
+	// Invalidate drops the cached lists of the subject and the replies of
+	// root in every comment service instance.
+	Invalidate(ctx context.Context, objID int64, objType int32, root int64) error
+}
+
+// CacheUsecase invalidates the in process caches of the comment service.
+type CacheUsecase struct {
+	repo CacheRepo
+	log  *log.Helper
+}
+
+// NewCacheUsecase new a cache usecase.
+func NewCacheUsecase(repo CacheRepo, logger log.Logger) *CacheUsecase {
+	return &CacheUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// CommentCreated invalidates the subject of a normal comment, a pending
+// comment is not listed.
+func (uc *CacheUsecase) CommentCreated(ctx context.Context, e *v1.CommentCreated) error {
+	if e.State != 0 {
+		return nil
+	}
+	return uc.repo.Invalidate(ctx, e.ObjId, e.ObjType, e.Root)
+}
+
+// CommentChanged invalidates the subject of the comment.
+func (uc *CacheUsecase) CommentChanged(ctx context.Context, e *v1.CommentChanged) error {
+	return uc.repo.Invalidate(ctx, e.ObjId, e.ObjType, e.Root)
+}
@@ -0,0 +1,70 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// Moderation is a comment put into the moderation queue.
+type Moderation struct {
+	CommentID int64
+	ObjID     int64
+	ObjType   int32
+	Source    int8
+}
+
+// CommentRepo redoes the writes of a comment on the main database, they
+// commit after the shard of the comment and are lost when the commit fails.
+type CommentRepo interface {
+	// BindAttachments binds the attachments not bound yet to the comment.
+	BindAttachments(ctx context.Context, commentID int64, ids []int64) error
+	// EnqueueModeration puts the comment into the moderation queue unless
+	// it is queued.
+	EnqueueModeration(ctx context.Context, m *Moderation) error
+}
+
+// CommentUsecase completes the writes of the comments on the main database
+// from their events, the writes are idempotent.
+type CommentUsecase struct {
+	repo CommentRepo
+	log  *log.Helper
+}
+
+// NewCommentUsecase new a comment usecase.
+func NewCommentUsecase(repo CommentRepo, logger log.Logger) *CommentUsecase {
+	return &CommentUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// CommentCreated binds the attachments of the comment and queues a
+// quarantined comment for moderation.
+func (uc *CommentUsecase) CommentCreated(ctx context.Context, e *v1.CommentCreated) error {
+	if len(e.AttachmentIds) != 0 {
+		if err := uc.repo.BindAttachments(ctx, e.CommentId, e.AttachmentIds); err != nil {
+			return err
+		}
+	}
+	if e.ModerationSource == 0 {
+		return nil
+	}
+	return uc.repo.EnqueueModeration(ctx, &Moderation{
+		CommentID: e.CommentId,
+		ObjID:     e.ObjId,
+		ObjType:   e.ObjType,
+		Source:    int8(e.ModerationSource),
+	})
+}
+
+// CommentChanged queues the comment hidden for moderation by the change.
+func (uc *CommentUsecase) CommentChanged(ctx context.Context, e *v1.CommentChanged) error {
+	if e.ModerationSource == 0 {
+		return nil
+	}
+	return uc.repo.EnqueueModeration(ctx, &Moderation{
+		CommentID: e.CommentId,
+		ObjID:     e.ObjId,
+		ObjType:   e.ObjType,
+		Source:    int8(e.ModerationSource),
+	})
+}
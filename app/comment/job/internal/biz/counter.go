@@ -0,0 +1,115 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+const (
+	defaultCounterInterval = time.Second
+	defaultCounterBatch    = 500
+)
+
+// CounterDelta is a change of the counters of a comment, or of a subject
+// when CommentID is 0, written by the comment service.
+type CounterDelta struct {
+	CommentID int64
+	ObjID     int64
+	ObjType   int32
+	Like      int32
+	Hate      int32
+	RootCount int32 // 评论的现存回复数量或主题的现存根评论数量
+	AllCount  int32 // 主题的现存评论总数
+}
+
+// CounterRepo applies the counter changes pending on the shard databases.
+type CounterRepo interface {
+	// MergeCounters takes at most limit of the oldest pending changes on
+	// each shard database, applies the changes returned by merge and
+	// removes the changes taken in one transaction, so a crash neither
+	// loses nor repeats a change. It returns the most changes taken on a
+	// database.
+	MergeCounters(ctx context.Context, limit int, merge func([]*CounterDelta) []*CounterDelta) (int, error)
+}
+
+// CounterUsecase merges the counter changes coalesced by the comment
+// service at intervals, the changes of a comment or a subject in a batch
+// are written once.
+type CounterUsecase struct {
+	interval time.Duration
+	batch    int
+	repo     CounterRepo
+	log      *log.Helper
+
+	last time.Time
+}
+
+// NewCounterUsecase new a counter usecase.
+func NewCounterUsecase(c *conf.Job, repo CounterRepo, logger log.Logger) *CounterUsecase {
+	uc := &CounterUsecase{
+		interval: defaultCounterInterval,
+		batch:    defaultCounterBatch,
+		repo:     repo,
+		log:      log.NewHelper(logger),
+	}
+	if c.GetCounter().GetInterval() != nil {
+		uc.interval = c.Counter.Interval.AsDuration()
+	}
+	if c.GetCounter().GetBatchSize() > 0 {
+		uc.batch = int(c.Counter.BatchSize)
+	}
+	return uc
+}
+
+// Flush merges all the pending changes when the interval passed since the
+// last flush, the zero time flushes at once.
+func (uc *CounterUsecase) Flush(ctx context.Context, now time.Time) error {
+	if !now.IsZero() {
+		if now.Sub(uc.last) < uc.interval {
+			return nil
+		}
+		uc.last = now
+	}
+	for {
+		n, err := uc.repo.MergeCounters(ctx, uc.batch, coalesce)
+		if err != nil || n < uc.batch {
+			return err
+		}
+	}
+}
+
+// coalesce sums the changes per comment and subject, the sums of zero are dropped.
+func coalesce(ds []*CounterDelta) []*CounterDelta {
+	type key struct {
+		commentID int64
+		objID     int64
+		objType   int32
+	}
+	var (
+		sums = make(map[key]*CounterDelta, len(ds))
+		keys = make([]key, 0, len(ds))
+	)
+	for _, d := range ds {
+		k := key{commentID: d.CommentID, objID: d.ObjID, objType: d.ObjType}
+		s, ok := sums[k]
+		if !ok {
+			s = &CounterDelta{CommentID: d.CommentID, ObjID: d.ObjID, ObjType: d.ObjType}
+			sums[k] = s
+			keys = append(keys, k)
+		}
+		s.Like += d.Like
+		s.Hate += d.Hate
+		s.RootCount += d.RootCount
+		s.AllCount += d.AllCount
+	}
+	merged := make([]*CounterDelta, 0, len(keys))
+	for _, k := range keys {
+		if s := sums[k]; s.Like != 0 || s.Hate != 0 || s.RootCount != 0 || s.AllCount != 0 {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
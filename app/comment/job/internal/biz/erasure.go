@@ -0,0 +1,77 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+const defaultErasureBatch = 100
+
+// ErasureCursor is the position of an erasure, the comments after ID on
+// the DB-th shard database are next.
+type ErasureCursor struct {
+	DB int
+	ID int64
+}
+
+// ErasureRepo anonymizes the data of a member, every step is idempotent
+// so a redelivered event resumes the task.
+type ErasureRepo interface {
+	// StartErasure marks the task running, it returns false when the task
+	// is missing or done.
+	StartErasure(ctx context.Context, taskID, memberID int64) (bool, error)
+	// EraseComments clears the content of at most limit comments of the
+	// member from the cursor, adds them to the progress of the task and
+	// returns the cursor of the next batch, nil when all are erased.
+	EraseComments(ctx context.Context, taskID, memberID int64, cursor *ErasureCursor, limit int) (*ErasureCursor, error)
+	// EraseRelations removes the member from likes, reports, replies,
+	// attachments, blocks and mutes.
+	EraseRelations(ctx context.Context, memberID int64) error
+	// FinishErasure marks the task done.
+	FinishErasure(ctx context.Context, taskID, memberID int64) error
+}
+
+// ErasureUsecase carries out the member erasures requested to the comment service.
+type ErasureUsecase struct {
+	c    *conf.Job_Erasure
+	repo ErasureRepo
+	log  *log.Helper
+}
+
+// NewErasureUsecase new an erasure usecase.
+func NewErasureUsecase(c *conf.Job, repo ErasureRepo, logger log.Logger) *ErasureUsecase {
+	return &ErasureUsecase{c: c.GetErasure(), repo: repo, log: log.NewHelper(logger)}
+}
+
+// MemberErasureRequested anonymizes the comments of the member in batches,
+// then the rest of the data carrying the member id.
+func (uc *ErasureUsecase) MemberErasureRequested(ctx context.Context, e *v1.MemberErasureRequested) error {
+	if e.MemberId <= 0 {
+		uc.log.WithContext(ctx).Warnf("skip erasure %d of invalid member %d", e.TaskId, e.MemberId)
+		return nil
+	}
+	ok, err := uc.repo.StartErasure(ctx, e.TaskId, e.MemberId)
+	if err != nil || !ok {
+		return err
+	}
+	limit := int(uc.c.GetBatchSize())
+	if limit <= 0 {
+		limit = defaultErasureBatch
+	}
+	for cursor := new(ErasureCursor); cursor != nil; {
+		if cursor, err = uc.repo.EraseComments(ctx, e.TaskId, e.MemberId, cursor, limit); err != nil {
+			return err
+		}
+	}
+	if err = uc.repo.EraseRelations(ctx, e.MemberId); err != nil {
+		return err
+	}
+	if err = uc.repo.FinishErasure(ctx, e.TaskId, e.MemberId); err != nil {
+		return err
+	}
+	uc.log.WithContext(ctx).Infof("erasure %d of member %d done", e.TaskId, e.MemberId)
+	return nil
+}
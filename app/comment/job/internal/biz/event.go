@@ -0,0 +1,110 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// EventRepo reads the events written by the comment service into the
+// outbox of the main database and of each shard database.
+type EventRepo interface {
+	// Outboxes returns the number of the outboxes, the main database is 0.
+	Outboxes() int
+	// ListEvent returns at most limit events of the outbox after id in id
+	// order, the create time of an event is the time it is written.
+	ListEvent(ctx context.Context, outbox int, after int64, limit int) ([]*v1.Event, error)
+	// GetCursor returns the last event id handled by the consumer, 0 if none.
+	GetCursor(ctx context.Context, name string) (int64, error)
+	SaveCursor(ctx context.Context, name string, id int64) error
+}
+
+// EventUsecase consumes the events in order.
+type EventUsecase struct {
+	repo EventRepo
+	log  *log.Helper
+}
+
+// NewEventUsecase new an event usecase.
+func NewEventUsecase(repo EventRepo, logger log.Logger) *EventUsecase {
+	return &EventUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// Consume handles at most limit events of each outbox after the cursor of
+// the consumer on the outbox and moves the cursor forward, it returns the
+// most events handled from one outbox. An event is handled at least once,
+// the cursor stays before the first event failed to handle, the other
+// outboxes go on. The ids are allocated before the transactions commit, so
+// an event after a gap in the ids is handled only when it is older than
+// grace, an event committed later in the gap is not skipped.
+func (uc *EventUsecase) Consume(ctx context.Context, name string, limit int, grace time.Duration,
+	handle func(context.Context, *v1.Event) error) (int, error) {
+	var (
+		most int
+		err  error
+	)
+	for outbox := 0; outbox < uc.repo.Outboxes(); outbox++ {
+		n, cerr := uc.consume(ctx, outbox, cursorName(name, outbox), limit, grace, handle)
+		if cerr != nil && err == nil {
+			err = cerr
+		}
+		if n > most {
+			most = n
+		}
+	}
+	return most, err
+}
+
+// cursorName returns the name of the cursor of the consumer on the outbox.
+func cursorName(name string, outbox int) string {
+	if outbox == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s:%d", name, outbox)
+}
+
+func (uc *EventUsecase) consume(ctx context.Context, outbox int, name string, limit int, grace time.Duration,
+	handle func(context.Context, *v1.Event) error) (int, error) {
+	cursor, err := uc.repo.GetCursor(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	es, err := uc.repo.ListEvent(ctx, outbox, cursor, limit)
+	if err != nil {
+		return 0, err
+	}
+	if es = settled(es, cursor, time.Now().Add(-grace)); len(es) == 0 {
+		return 0, nil
+	}
+	var n int
+	for _, e := range es {
+		if err = handle(ctx, e); err != nil {
+			uc.log.WithContext(ctx).Errorf("handle event %d of outbox %d: %v", e.Id, outbox, err)
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, err
+	}
+	if serr := uc.repo.SaveCursor(ctx, name, es[n-1].Id); serr != nil {
+		return 0, serr
+	}
+	return n, err
+}
+
+// settled returns the events up to the first gap in the ids after the
+// cursor that is followed by an event created after before.
+func settled(es []*v1.Event, cursor int64, before time.Time) []*v1.Event {
+	last := cursor
+	for i, e := range es {
+		if e.Id != last+1 && time.Unix(e.CreateTime, 0).After(before) {
+			return es[:i]
+		}
+		last = e.Id
+	}
+	return es
+}
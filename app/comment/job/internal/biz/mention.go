@@ -0,0 +1,101 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// BlockRepo reads the members blocked by owners.
+type BlockRepo interface {
+	// ListBlocker returns the owners in owners actively blocking member at now,
+	// owner 0 is returned when member is banned.
+	ListBlocker(ctx context.Context, memberID int64, owners []int64, now time.Time) ([]int64, error)
+}
+
+// MentionUsecase notifies the members mentioned in comments.
+type MentionUsecase struct {
+	c     *conf.Job_Mention
+	block BlockRepo
+	sink  NotificationSink
+	log   *log.Helper
+}
+
+// NewMentionUsecase new a mention usecase.
+func NewMentionUsecase(c *conf.Job, block BlockRepo, sink NotificationSink, logger log.Logger) *MentionUsecase {
+	return &MentionUsecase{c: c.GetMention(), block: block, sink: sink, log: log.NewHelper(logger)}
+}
+
+// CommentCreated sends a mention notification to each distinct member in
+// at_member_ids, at most max_members of them, skipping the author and the
+// members blocking the author, nothing is sent for a banned author.
+func (uc *MentionUsecase) CommentCreated(ctx context.Context, e *v1.CommentCreated) error {
+	if e.State != 0 || len(e.AtMemberIds) == 0 {
+		return nil
+	}
+	var (
+		ids  = make([]int64, 0, len(e.AtMemberIds))
+		seen = make(map[int64]bool, len(e.AtMemberIds))
+	)
+	for _, id := range e.AtMemberIds {
+		if id == 0 || id == e.MemberId || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if max := int(uc.c.GetMaxMembers()); max > 0 && len(ids) > max {
+		ids = ids[:max]
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	blocked, err := blockedBy(ctx, uc.block, e.MemberId, ids)
+	if err != nil {
+		return err
+	}
+	if blocked[0] {
+		return nil
+	}
+	now := time.Now()
+	ns := make([]*Notification, 0, len(ids))
+	for _, id := range ids {
+		if blocked[id] {
+			continue
+		}
+		ns = append(ns, &Notification{
+			Type:         NotificationTypeMention,
+			MemberID:     id,
+			FromMemberID: e.MemberId,
+			CommentID:    e.CommentId,
+			ObjID:        e.ObjId,
+			ObjType:      e.ObjType,
+			Root:         e.Root,
+			Parent:       e.Parent,
+			Count:        1,
+			CreateTime:   now,
+		})
+	}
+	if len(ns) == 0 {
+		return nil
+	}
+	return uc.sink.Send(ctx, ns...)
+}
+
+// blockedBy returns the members in ids blocking member, with 0 set when
+// member is banned.
+func blockedBy(ctx context.Context, repo BlockRepo, memberID int64, ids []int64) (map[int64]bool, error) {
+	blockers, err := repo.ListBlocker(ctx, memberID, append([]int64{0}, ids...), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[int64]bool, len(blockers))
+	for _, id := range blockers {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
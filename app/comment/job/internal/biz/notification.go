@@ -0,0 +1,34 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// 通知类型
+const (
+	NotificationTypeMention = "mention" // 评论中 @ 了接收人
+	NotificationTypeReply   = "reply"   // 回复了接收人的评论
+	NotificationTypeComment = "comment" // 评论了接收人的主题
+)
+
+// Notification is a message sent to a member about a comment, a
+// notification aggregated from several comments carries the latest one,
+// eg. "FromMemberID and Count-1 others replied to you".
+type Notification struct {
+	Type         string
+	MemberID     int64 // 接收人
+	FromMemberID int64 // 评论作者
+	CommentID    int64
+	ObjID        int64
+	ObjType      int32
+	Root         int64
+	Parent       int64
+	Count        int32 // 聚合的评论作者数量
+	CreateTime   time.Time
+}
+
+// NotificationSink delivers notifications to members.
+type NotificationSink interface {
+	Send(ctx context.Context, ns ...*Notification) error
+}
@@ -0,0 +1,100 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/metrics"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+const defaultReconcileBatch = 100
+
+// 对账的计数
+const (
+	CounterSubjectRoot = "subject_root" // 主题的现存根评论数量
+	CounterSubjectAll  = "subject_all"  // 主题的现存评论总数
+	CounterReply       = "reply"        // 根评论的现存回复数量
+)
+
+// CounterDrift is a counter differing from the count of the index rows,
+// Stored includes the changes pending merge.
+type CounterDrift struct {
+	Counter   string
+	ObjID     int64
+	ObjType   int32
+	CommentID int64 // 回复数的根评论
+	Stored    int32
+	Actual    int32
+}
+
+// ReconcileCursor is the position of a reconciliation, the subjects after
+// ID on the DB-th shard database are next.
+type ReconcileCursor struct {
+	DB int
+	ID int64
+}
+
+// ReconcileRepo recomputes the counters of the subjects from the index rows.
+type ReconcileRepo interface {
+	// ReconcileSubjects recounts at most limit subjects from the cursor,
+	// corrects the counters differing from the counts by the difference
+	// and returns the drifts corrected and the cursor of the next batch,
+	// nil when all subjects are done.
+	ReconcileSubjects(ctx context.Context, cursor *ReconcileCursor, limit int) ([]*CounterDrift, *ReconcileCursor, error)
+}
+
+// ReconcileUsecase repairs the counters drifted from the index rows and
+// reports the drift of each counter found by the last run.
+type ReconcileUsecase struct {
+	batch int
+	repo  ReconcileRepo
+	drift metrics.Gauge
+	log   *log.Helper
+}
+
+// NewReconcileUsecase new a reconcile usecase.
+func NewReconcileUsecase(c *conf.Job, repo ReconcileRepo, drift metrics.Gauge, logger log.Logger) *ReconcileUsecase {
+	uc := &ReconcileUsecase{
+		batch: defaultReconcileBatch,
+		repo:  repo,
+		drift: drift,
+		log:   log.NewHelper(logger),
+	}
+	if c.GetReconcile().GetBatchSize() > 0 {
+		uc.batch = int(c.Reconcile.BatchSize)
+	}
+	return uc
+}
+
+// Reconcile recounts all the subjects, the drift is the sum of the
+// absolute differences of each counter.
+func (uc *ReconcileUsecase) Reconcile(ctx context.Context) error {
+	var (
+		cursor = new(ReconcileCursor)
+		drift  = make(map[string]int64)
+	)
+	for cursor != nil {
+		ds, next, err := uc.repo.ReconcileSubjects(ctx, cursor, uc.batch)
+		if err != nil {
+			return err
+		}
+		for _, d := range ds {
+			uc.log.WithContext(ctx).Warnf("repaired %s counter of subject %d %d comment %d from %d to %d",
+				d.Counter, d.ObjID, d.ObjType, d.CommentID, d.Stored, d.Actual)
+			diff := int64(d.Actual - d.Stored)
+			if diff < 0 {
+				diff = -diff
+			}
+			drift[d.Counter] += diff
+		}
+		cursor = next
+	}
+	for _, counter := range []string{CounterSubjectRoot, CounterSubjectAll, CounterReply} {
+		uc.drift.With(counter).Set(float64(drift[counter]))
+	}
+	uc.log.WithContext(ctx).Infof("reconciled counters, drift %s %d %s %d %s %d",
+		CounterSubjectRoot, drift[CounterSubjectRoot], CounterSubjectAll, drift[CounterSubjectAll],
+		CounterReply, drift[CounterReply])
+	return nil
+}
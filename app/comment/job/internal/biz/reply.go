@@ -0,0 +1,160 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// replyFlushBatch is the number of pending notifications sent at once.
+const replyFlushBatch = 100
+
+// PendingReply is a notification aggregated on its target within the window.
+type PendingReply struct {
+	ID         int64
+	Target     int64 // 被回复的评论或主题ID
+	TargetType int32 // 主题类型, 回复为 0
+	FirstTime  time.Time
+	*Notification
+}
+
+// ReplyRepo stores the pending notifications, they survive a restart of
+// the job once the events are handled.
+type ReplyRepo interface {
+	// AddReply merges the notification into the pending one of the same
+	// type, receiver and target, the latest comment wins, each author is
+	// counted once and the count of the notification is set.
+	AddReply(ctx context.Context, p *PendingReply) error
+	// ListReply returns at most limit pending notifications whose window
+	// started before the time, any of them for the zero time.
+	ListReply(ctx context.Context, before time.Time, limit int) ([]*PendingReply, error)
+	// DeleteReply deletes the pending notifications, the ones merged with
+	// another comment since they were listed are kept.
+	DeleteReply(ctx context.Context, ps []*PendingReply) error
+}
+
+// ReplyUsecase notifies the parent author of a reply and the subject owner
+// of a comment, the notifications on the same target are aggregated within
+// the window.
+type ReplyUsecase struct {
+	window time.Duration
+	block  BlockRepo
+	sink   NotificationSink
+	repo   ReplyRepo
+	log    *log.Helper
+}
+
+// NewReplyUsecase new a reply usecase.
+func NewReplyUsecase(c *conf.Job, block BlockRepo, sink NotificationSink, repo ReplyRepo, logger log.Logger) *ReplyUsecase {
+	return &ReplyUsecase{
+		window: c.GetReply().GetWindow().AsDuration(),
+		block:  block,
+		sink:   sink,
+		repo:   repo,
+		log:    log.NewHelper(logger),
+	}
+}
+
+// CommentCreated notifies the author of parent and the subject owner,
+// a member is notified once and never about their own comment.
+func (uc *ReplyUsecase) CommentCreated(ctx context.Context, e *v1.CommentCreated) error {
+	if e.State != 0 {
+		return nil
+	}
+	now := time.Now()
+	ns := make([]*Notification, 0, 2)
+	if e.Parent != 0 && e.ReplyMemberId != 0 && e.ReplyMemberId != e.MemberId {
+		ns = append(ns, uc.notification(e, NotificationTypeReply, e.ReplyMemberId, now))
+	}
+	if e.SubjectMemberId != 0 && e.SubjectMemberId != e.MemberId &&
+		(len(ns) == 0 || e.SubjectMemberId != e.ReplyMemberId) {
+		ns = append(ns, uc.notification(e, NotificationTypeComment, e.SubjectMemberId, now))
+	}
+	if len(ns) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(ns))
+	for _, n := range ns {
+		ids = append(ids, n.MemberID)
+	}
+	blocked, err := blockedBy(ctx, uc.block, e.MemberId, ids)
+	if err != nil {
+		return err
+	}
+	if blocked[0] {
+		return nil
+	}
+	sends := ns[:0]
+	for _, n := range ns {
+		if !blocked[n.MemberID] {
+			sends = append(sends, n)
+		}
+	}
+	if len(sends) == 0 {
+		return nil
+	}
+	if uc.window <= 0 {
+		return uc.sink.Send(ctx, sends...)
+	}
+	for _, n := range sends {
+		p := &PendingReply{Target: n.Parent, FirstTime: n.CreateTime, Notification: n}
+		if n.Type == NotificationTypeComment {
+			p.Target, p.TargetType = n.ObjID, n.ObjType
+		}
+		if err = uc.repo.AddReply(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *ReplyUsecase) notification(e *v1.CommentCreated, typ string, memberID int64, now time.Time) *Notification {
+	return &Notification{
+		Type:         typ,
+		MemberID:     memberID,
+		FromMemberID: e.MemberId,
+		CommentID:    e.CommentId,
+		ObjID:        e.ObjId,
+		ObjType:      e.ObjType,
+		Root:         e.Root,
+		Parent:       e.Parent,
+		Count:        1,
+		CreateTime:   now,
+	}
+}
+
+// Flush sends the pending notifications whose window ended before the
+// time, all of them for the zero time. The notifications failed to send
+// stay pending.
+func (uc *ReplyUsecase) Flush(ctx context.Context, before time.Time) error {
+	if uc.window <= 0 {
+		return nil
+	}
+	started := before
+	if !before.IsZero() {
+		started = before.Add(-uc.window)
+	}
+	for {
+		ps, err := uc.repo.ListReply(ctx, started, replyFlushBatch)
+		if err != nil || len(ps) == 0 {
+			return err
+		}
+		ns := make([]*Notification, 0, len(ps))
+		for _, p := range ps {
+			ns = append(ns, p.Notification)
+		}
+		if err = uc.sink.Send(ctx, ns...); err != nil {
+			return err
+		}
+		if err = uc.repo.DeleteReply(ctx, ps); err != nil {
+			return err
+		}
+		if len(ps) < replyFlushBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+type blockRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewBlockRepo .
+func NewBlockRepo(data *Data, logger log.Logger) biz.BlockRepo {
+	return &blockRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *blockRepo) ListBlocker(ctx context.Context, memberID int64, owners []int64, now time.Time) ([]int64, error) {
+	if len(owners) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{memberID, now}
+	for _, id := range owners {
+		args = append(args, id)
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT owner_id FROM comment_block
+		WHERE member_id = ? AND (expire_time IS NULL OR expire_time > ?) AND owner_id IN (`+placeholders(len(owners))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+// invalidateChannel is the redis channel the comment service instances
+// subscribe to, the message is "{obj_type}:{obj_id}:{root}".
+const invalidateChannel = "comment:invalidate"
+
+type cacheRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewCacheRepo .
+func NewCacheRepo(data *Data, logger log.Logger) biz.CacheRepo {
+	return &cacheRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *cacheRepo) Invalidate(ctx context.Context, objID int64, objType int32, root int64) error {
+	if r.data.rdb == nil {
+		return nil
+	}
+	return r.data.rdb.Publish(ctx, invalidateChannel, fmt.Sprintf("%d:%d:%d", objType, objID, root)).Err()
+}
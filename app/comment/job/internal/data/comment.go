@@ -0,0 +1,58 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+// 审核状态, 同 comment service
+const moderationStatePending = 0
+
+type commentRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewCommentRepo .
+func NewCommentRepo(data *Data, logger log.Logger) biz.CommentRepo {
+	return &commentRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *commentRepo) BindAttachments(ctx context.Context, commentID int64, ids []int64) error {
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, commentID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := r.data.db.ExecContext(ctx, `UPDATE comment_attachment SET comment_id = ?
+		WHERE id IN (`+placeholders(len(ids))+`) AND comment_id = 0`, args...)
+	return err
+}
+
+func (r *commentRepo) EnqueueModeration(ctx context.Context, m *biz.Moderation) error {
+	now := time.Now()
+	return runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		var n int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_moderation WHERE comment_id = ?`,
+			m.CommentID).Scan(&n)
+		if err != nil || n != 0 {
+			return err
+		}
+		r.log.WithContext(ctx).Warnf("queue comment %d missing from the moderation queue", m.CommentID)
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_moderation
+			(comment_id, obj_id, obj_type, source, state, create_time, update_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, m.CommentID, m.ObjID, m.ObjType, m.Source, moderationStatePending, now, now)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE comment_report_stat SET hidden = 1 WHERE comment_id = ?`, m.CommentID)
+		return err
+	})
+}
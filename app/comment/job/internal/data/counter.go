@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+// errDeltaTaken is returned when another merge removed the changes first.
+var errDeltaTaken = errors.New("counter changes taken by another merge")
+
+type counterRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewCounterRepo .
+func NewCounterRepo(data *Data, logger log.Logger) biz.CounterRepo {
+	return &counterRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *counterRepo) MergeCounters(ctx context.Context, limit int, merge func([]*biz.CounterDelta) []*biz.CounterDelta) (int, error) {
+	var most int
+	for _, db := range r.data.shardDBs {
+		n, err := r.mergeCounters(ctx, db, limit, merge)
+		if err != nil {
+			return most, err
+		}
+		if n > most {
+			most = n
+		}
+	}
+	return most, nil
+}
+
+func (r *counterRepo) mergeCounters(ctx context.Context, db *sql.DB, limit int, merge func([]*biz.CounterDelta) []*biz.CounterDelta) (int, error) {
+	var n int
+	err := runTx(ctx, db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `SELECT id, comment_id, obj_id, obj_type, like_count, hate_count, root_count, all_count
+			FROM comment_counter_delta ORDER BY id LIMIT ?`, limit)
+		if err != nil {
+			return err
+		}
+		var (
+			ids []interface{}
+			ds  []*biz.CounterDelta
+		)
+		for rows.Next() {
+			var (
+				id int64
+				d  = new(biz.CounterDelta)
+			)
+			if err = rows.Scan(&id, &d.CommentID, &d.ObjID, &d.ObjType, &d.Like, &d.Hate, &d.RootCount, &d.AllCount); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			ds = append(ds, d)
+		}
+		_ = rows.Close()
+		if err = rows.Err(); err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// removing the changes first locks them, a concurrent merge of the
+		// same changes removes none of them and is rolled back
+		res, err := tx.ExecContext(ctx, `DELETE FROM comment_counter_delta WHERE id IN (`+placeholders(len(ids))+`)`, ids...)
+		if err != nil {
+			return err
+		}
+		if removed, err := res.RowsAffected(); err != nil || removed != int64(len(ids)) {
+			if err == nil {
+				err = errDeltaTaken
+			}
+			return err
+		}
+		now := time.Now()
+		for _, d := range merge(ds) {
+			if d.CommentID != 0 {
+				_, err = tx.ExecContext(ctx, `UPDATE comment_index SET like_count = like_count + ?,
+					hate_count = hate_count + ?, root_count = root_count + ?, update_time = ? WHERE id = ?`,
+					d.Like, d.Hate, d.RootCount, now, d.CommentID)
+			} else {
+				_, err = tx.ExecContext(ctx, `UPDATE comment_subject SET root_count = root_count + ?,
+					all_count = all_count + ?, update_time = ? WHERE obj_id = ? AND obj_type = ?`,
+					d.RootCount, d.AllCount, now, d.ObjID, d.ObjType)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		n = len(ids)
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,160 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// counters are the counters of comment 1 and of its subject 1 1.
+type counters struct {
+	like, hate, rootCount int32 // comment_index
+	subjectRoot, allCount int32 // comment_subject
+}
+
+func seedCounters(t *testing.T, db *sql.DB) {
+	t.Helper()
+	now := time.Now()
+	_, err := db.Exec(`INSERT INTO comment_subject
+		(obj_id, obj_type, member_id, count, root_count, all_count, state, create_time, update_time)
+		VALUES (1, 1, 9, 1, 1, 1, 0, ?, ?)`, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO comment_index (id, obj_id, obj_type, member_id, floor, like_count, create_time, update_time)
+		VALUES (1, 1, 1, 10, 1, 5, ?, ?)`, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addDelta(t *testing.T, db *sql.DB, commentID int64, like, hate, rootCount, allCount int32) {
+	t.Helper()
+	_, err := db.Exec(`INSERT INTO comment_counter_delta
+		(comment_id, obj_id, obj_type, like_count, hate_count, root_count, all_count, create_time)
+		VALUES (?, 1, 1, ?, ?, ?, ?, ?)`, commentID, like, hate, rootCount, allCount, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readCounters(t *testing.T, db *sql.DB) (c counters, pending int) {
+	t.Helper()
+	err := db.QueryRow(`SELECT like_count, hate_count, root_count FROM comment_index WHERE id = 1`).
+		Scan(&c.like, &c.hate, &c.rootCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.QueryRow(`SELECT root_count, all_count FROM comment_subject WHERE obj_id = 1 AND obj_type = 1`).
+		Scan(&c.subjectRoot, &c.allCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.QueryRow(`SELECT COUNT(*) FROM comment_counter_delta`).Scan(&pending); err != nil {
+		t.Fatal(err)
+	}
+	return c, pending
+}
+
+func TestMergeCounters(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		d    = newTestData(t)
+		repo = NewCounterRepo(d, log.DefaultLogger)
+	)
+	seedCounters(t, d.db)
+	addDelta(t, d.db, 1, 1, 0, 0, 0)
+	addDelta(t, d.db, 1, 1, 1, 0, 0)
+	addDelta(t, d.db, 1, -1, 0, 1, 0)
+	addDelta(t, d.db, 0, 0, 0, 1, 2)
+
+	var taken []*biz.CounterDelta
+	n, err := repo.MergeCounters(ctx, 10, func(ds []*biz.CounterDelta) []*biz.CounterDelta {
+		taken = ds
+		return ds
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 || len(taken) != 4 {
+		t.Fatalf("merged %d changes, passed %d", n, len(taken))
+	}
+	c, pending := readCounters(t, d.db)
+	if want := (counters{like: 6, hate: 1, rootCount: 1, subjectRoot: 2, allCount: 3}); c != want || pending != 0 {
+		t.Fatalf("got counters %+v with %d pending, want %+v", c, pending, want)
+	}
+
+	// nothing is left to merge
+	if n, err = repo.MergeCounters(ctx, 10, func(ds []*biz.CounterDelta) []*biz.CounterDelta { return ds }); err != nil || n != 0 {
+		t.Fatalf("merged %d changes, error %v", n, err)
+	}
+}
+
+func TestMergeCountersTaken(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		d    = newTestData(t)
+		repo = NewCounterRepo(d, log.DefaultLogger)
+	)
+	seedCounters(t, d.db)
+	addDelta(t, d.db, 1, 1, 0, 0, 0)
+	addDelta(t, d.db, 1, 1, 0, 0, 0)
+
+	// the trigger keeps the second change, as if another merge removed it
+	// between the read and the delete
+	_, err := d.db.Exec(`CREATE TRIGGER counter_delta_taken BEFORE DELETE ON comment_counter_delta
+		WHEN OLD.id = 2 BEGIN SELECT RAISE(IGNORE); END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := false
+	_, err = repo.MergeCounters(ctx, 10, func(ds []*biz.CounterDelta) []*biz.CounterDelta {
+		merged = true
+		return ds
+	})
+	if err != errDeltaTaken || merged {
+		t.Fatalf("got error %v, merged %v", err, merged)
+	}
+	c, pending := readCounters(t, d.db)
+	if c.like != 5 || pending != 2 {
+		t.Fatalf("got like %d with %d pending after a taken merge", c.like, pending)
+	}
+
+	if _, err = d.db.Exec(`DROP TRIGGER counter_delta_taken`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.MergeCounters(ctx, 10, func(ds []*biz.CounterDelta) []*biz.CounterDelta { return ds }); err != nil {
+		t.Fatal(err)
+	}
+	if c, pending = readCounters(t, d.db); c.like != 7 || pending != 0 {
+		t.Fatalf("got like %d with %d pending after a retry", c.like, pending)
+	}
+}
+
+func TestFlushCounters(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = newTestData(t)
+		uc  = biz.NewCounterUsecase(&conf.Job{Counter: &conf.Job_Counter{BatchSize: 2}},
+			NewCounterRepo(d, log.DefaultLogger), log.DefaultLogger)
+	)
+	seedCounters(t, d.db)
+	for i := 0; i < 5; i++ {
+		addDelta(t, d.db, 1, 1, 0, 0, 0)
+	}
+	addDelta(t, d.db, 0, 0, 0, 1, 1)
+
+	// the batches are merged until one is not full
+	if err := uc.Flush(ctx, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	c, pending := readCounters(t, d.db)
+	if want := (counters{like: 10, subjectRoot: 2, allCount: 2}); c != want || pending != 0 {
+		t.Fatalf("got counters %+v with %d pending, want %+v", c, pending, want)
+	}
+}
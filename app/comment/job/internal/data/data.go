@@ -0,0 +1,154 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/wire"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// ProviderSet is data providers.
+var ProviderSet = wire.NewSet(NewData, NewEventRepo, NewBlockRepo, NewNotificationSink, NewErasureRepo, NewCacheRepo, NewCounterRepo, NewReconcileRepo, NewReplyRepo, NewCommentRepo)
+
+// 缓存的内容在删除后再延迟删除一次
+const (
+	contentRedeleteDelay   = 2 * time.Second
+	contentRedeleteTimeout = time.Second
+)
+
+// Data .
+type Data struct {
+	db       *sql.DB
+	shardDBs []*sql.DB     // comment service 的分片库
+	rdb      *redis.Client // comment service 的缓存, 为空时没有缓存
+	log      *log.Helper
+}
+
+// NewData .
+func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
+	d := &Data{log: log.NewHelper(logger)}
+	cleanup := func() {
+		log.NewHelper(logger).Info("closing the data resources")
+		for _, db := range d.databases() {
+			if err := db.Close(); err != nil {
+				log.NewHelper(logger).Error(err)
+			}
+		}
+		if d.rdb != nil {
+			if err := d.rdb.Close(); err != nil {
+				log.NewHelper(logger).Error(err)
+			}
+		}
+	}
+	db, err := sql.Open(c.Database.Driver, c.Database.Source)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.db = db
+	for _, dc := range c.GetSharding().GetDatabases() {
+		if db, err = sql.Open(dc.Driver, dc.Source); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		d.shardDBs = append(d.shardDBs, db)
+	}
+	if len(d.shardDBs) == 0 {
+		d.shardDBs = []*sql.DB{d.db}
+	}
+	if err = checkShards(c.GetSharding(), len(d.shardDBs)); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if c.Redis != nil {
+		d.rdb = redis.NewClient(&redis.Options{
+			Network:      c.Redis.Network,
+			Addr:         c.Redis.Addr,
+			ReadTimeout:  c.Redis.ReadTimeout.AsDuration(),
+			WriteTimeout: c.Redis.WriteTimeout.AsDuration(),
+		})
+	}
+	return d, cleanup, nil
+}
+
+// checkShards checks the shards are mapped to the n shard databases as the
+// comment service maps them. The job visits every shard database, so a
+// comment is not located by its shard.
+func checkShards(c *conf.Data_Sharding, n int) error {
+	shards := int(c.GetShards())
+	if shards <= 0 {
+		shards = 1
+	}
+	m := c.GetShardMap()
+	if len(m) != 0 && len(m) != shards {
+		return fmt.Errorf("shard map has %d shards, want %d", len(m), shards)
+	}
+	for i, db := range m {
+		if db < 0 || int(db) >= n {
+			return fmt.Errorf("shard %d is mapped to unknown database %d", i, db)
+		}
+	}
+	return nil
+}
+
+// databases returns the main database and the shard databases.
+func (d *Data) databases() []*sql.DB {
+	dbs := []*sql.DB{d.db}
+	for _, db := range d.shardDBs {
+		if db != d.db {
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs
+}
+
+// runTx runs fn in a transaction on db, it is committed when fn returns nil.
+func runTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// placeholders returns "?,?,?" for n arguments.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?,", n-1) + "?"
+}
+
+// uncacheContent removes the contents of the comments cached by comment
+// service, and again after contentRedeleteDelay to drop a content read
+// before the change and cached after the first delete.
+func (d *Data) uncacheContent(ctx context.Context, ids []int64) {
+	if d.rdb == nil {
+		return
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("comment:content:%d", id))
+	}
+	if err := d.rdb.Del(ctx, keys...).Err(); err != nil {
+		d.log.WithContext(ctx).Errorf("uncache contents %v: %v", ids, err)
+	}
+	time.AfterFunc(contentRedeleteDelay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), contentRedeleteTimeout)
+		defer cancel()
+		if err := d.rdb.Del(ctx, keys...).Err(); err != nil {
+			d.log.Errorf("uncache contents %v again: %v", ids, err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are the SQLite migrations of the comment service, the job
+// works on its databases.
+const migrations = "../../../service/internal/data/migrations/sqlite3/*.sql"
+
+// newTestData opens a SQLite database in a temporary directory migrated to
+// the schema of the comment service, it is the main and the only shard database.
+func newTestData(t *testing.T) *Data {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "comment.db")+"?_busy_timeout=5000&_txlock=immediate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	files, err := filepath.Glob(migrations)
+	if err != nil || len(files) == 0 {
+		t.Fatalf("found %d migrations: %v", len(files), err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		script, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = db.Exec(string(script)); err != nil {
+			t.Fatalf("migrate %s: %v", filepath.Base(file), err)
+		}
+	}
+	return &Data{db: db, shardDBs: []*sql.DB{db}, log: log.NewHelper(log.DefaultLogger)}
+}
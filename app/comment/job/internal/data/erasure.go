@@ -0,0 +1,211 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+// 抹除任务状态, 同 comment service
+const (
+	erasureStateRunning = 1
+	erasureStateDone    = 2
+)
+
+type erasureRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewErasureRepo .
+func NewErasureRepo(data *Data, logger log.Logger) biz.ErasureRepo {
+	return &erasureRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// addAudit writes an entry of the comment job to comment_audit_log.
+func addAudit(ctx context.Context, tx *sql.Tx, action string, memberID int64, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO comment_audit_log (action, operator_id, member_id, detail, create_time)
+		VALUES (?, 0, ?, '', ?)`, action, memberID, now)
+	return err
+}
+
+func (r *erasureRepo) StartErasure(ctx context.Context, taskID, memberID int64) (bool, error) {
+	var started bool
+	now := time.Now()
+	err := runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE comment_erasure SET state = ?, update_time = ? WHERE id = ? AND state = 0`,
+			erasureStateRunning, now, taskID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n > 0 {
+			if err = addAudit(ctx, tx, "erase_start", memberID, now); err != nil {
+				return err
+			}
+		}
+		var state int8
+		err = tx.QueryRowContext(ctx, `SELECT state FROM comment_erasure WHERE id = ?`, taskID).Scan(&state)
+		if err == sql.ErrNoRows {
+			r.log.WithContext(ctx).Warnf("erasure %d not found", taskID)
+			return nil
+		}
+		started = state == erasureStateRunning
+		return err
+	})
+	return started, err
+}
+
+func (r *erasureRepo) EraseComments(ctx context.Context, taskID, memberID int64, cursor *biz.ErasureCursor, limit int) (*biz.ErasureCursor, error) {
+	now := time.Now()
+	for db := cursor.DB; db < len(r.data.shardDBs); db++ {
+		after := cursor.ID
+		if db != cursor.DB {
+			after = 0
+		}
+		cs, err := eraseComments(ctx, r.data.shardDBs[db], memberID, after, limit, now)
+		if err != nil {
+			return nil, err
+		}
+		if len(cs) == 0 {
+			continue
+		}
+		ids := make([]int64, 0, len(cs))
+		args := make([]interface{}, 0, len(cs))
+		for _, c := range cs {
+			ids = append(ids, c.CommentId)
+			args = append(args, c.CommentId)
+		}
+		r.data.uncacheContent(ctx, ids)
+		err = runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `UPDATE comment_report_stat SET member_id = 0
+				WHERE comment_id IN (`+placeholders(len(ids))+`)`, args...)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `UPDATE comment_erasure SET erased = erased + ?, update_time = ? WHERE id = ?`,
+				len(ids), now, taskID)
+			if err != nil {
+				return err
+			}
+			// 每个主题和楼层一个事件, 通知 comment service 丢弃本地缓存的列表
+			changed := make(map[string]bool)
+			for _, c := range cs {
+				key := fmt.Sprintf("%d:%d:%d", c.ObjType, c.ObjId, c.Root)
+				if changed[key] {
+					continue
+				}
+				changed[key] = true
+				if err = addEvent(ctx, tx, &v1.Event{Event: &v1.Event_CommentChanged{CommentChanged: c}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		next := &biz.ErasureCursor{DB: db, ID: ids[len(ids)-1]}
+		if len(ids) < limit {
+			next = &biz.ErasureCursor{DB: db + 1}
+		}
+		if next.DB >= len(r.data.shardDBs) {
+			next = nil
+		}
+		return next, nil
+	}
+	return nil, nil
+}
+
+// eraseComments clears the content of at most limit comments of the member
+// after the id on the shard database, the comments stay under the subject
+// with member id 0.
+func eraseComments(ctx context.Context, db *sql.DB, memberID, after int64, limit int, now time.Time) ([]*v1.CommentChanged, error) {
+	var cs []*v1.CommentChanged
+	err := runTx(ctx, db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `SELECT id, obj_id, obj_type, root FROM comment_index
+			WHERE member_id = ? AND id > ? ORDER BY id LIMIT ?`, memberID, after, limit)
+		if err != nil {
+			return err
+		}
+		var args []interface{}
+		for rows.Next() {
+			c := new(v1.CommentChanged)
+			if err = rows.Scan(&c.CommentId, &c.ObjId, &c.ObjType, &c.Root); err != nil {
+				rows.Close()
+				return err
+			}
+			cs = append(cs, c)
+			args = append(args, c.CommentId)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil || len(cs) == 0 {
+			return err
+		}
+
+		in := `(` + placeholders(len(cs)) + `)`
+		_, err = tx.ExecContext(ctx, `UPDATE comment_content SET at_member_ids = '', mentions = '', message = '',
+			meta = '', content = NULL, ip = 0, platform = '', device = '', update_time = ?
+			WHERE comment_id IN `+in, append([]interface{}{now}, args...)...)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM comment_content_history WHERE comment_id IN `+in, args...); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE comment_index SET member_id = 0, update_time = ? WHERE id IN `+in,
+			append([]interface{}{now}, args...)...)
+		return err
+	})
+	return cs, err
+}
+
+func (r *erasureRepo) EraseRelations(ctx context.Context, memberID int64) error {
+	for _, db := range r.data.shardDBs {
+		_, err := db.ExecContext(ctx, `UPDATE comment_index SET reply_member_id = 0 WHERE reply_member_id = ?`, memberID)
+		if err != nil {
+			return err
+		}
+	}
+	return runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		for _, q := range []string{
+			// 点赞和举报只删除记录, 保留评论上的计数
+			`DELETE FROM comment_action WHERE member_id = ?`,
+			`DELETE FROM comment_report WHERE member_id = ?`,
+			`UPDATE comment_attachment SET member_id = 0 WHERE member_id = ?`,
+			`DELETE FROM comment_block WHERE member_id = ?`,
+			`DELETE FROM comment_block WHERE owner_id = ?`,
+			`DELETE FROM comment_mute WHERE member_id = ?`,
+			`DELETE FROM comment_mute WHERE mute_member_id = ?`,
+		} {
+			if _, err := tx.ExecContext(ctx, q, memberID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *erasureRepo) FinishErasure(ctx context.Context, taskID, memberID int64) error {
+	now := time.Now()
+	return runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE comment_erasure SET state = ?, update_time = ?, finish_time = ?
+			WHERE id = ? AND state = ?`, erasureStateDone, now, now, taskID, erasureStateRunning)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		return addAudit(ctx, tx, "erase_done", memberID, now)
+	})
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+func TestEraseRelations(t *testing.T) {
+	var (
+		d   = newTestData(t)
+		now = time.Now()
+	)
+	for id, reply := range map[int64]int64{1: 7, 2: 8, 3: 7} {
+		_, err := d.db.Exec(`INSERT INTO comment_index
+			(id, obj_id, obj_type, member_id, root, reply_member_id, floor, state, create_time, update_time)
+			VALUES (?, 1, 1, 10, 1, ?, ?, 0, ?, ?)`, id, reply, id, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := NewErasureRepo(d, log.DefaultLogger).EraseRelations(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM comment_index WHERE reply_member_id = 7`).Scan(&n); err != nil || n != 0 {
+		t.Fatalf("kept %d replies to the erased member, error %v", n, err)
+	}
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM comment_index WHERE reply_member_id = 8`).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("kept %d replies to another member, error %v", n, err)
+	}
+
+	rows, err := d.db.Query(`EXPLAIN QUERY PLAN UPDATE comment_index SET reply_member_id = 0 WHERE reply_member_id = ?`, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var (
+			id, parent, notused int
+			detail              string
+		)
+		if err = rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatal(err)
+		}
+		plan = append(plan, detail)
+	}
+	if p := strings.Join(plan, "; "); !strings.Contains(p, "comment_index_idx_reply_member") {
+		t.Fatalf("clearing the replies does not use the index: %s", p)
+	}
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"google.golang.org/protobuf/proto"
+)
+
+type eventRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewEventRepo .
+func NewEventRepo(data *Data, logger log.Logger) biz.EventRepo {
+	return &eventRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// addEvent writes the event into the outbox of the comment service in tx,
+// the comment job consumes it like the events of the service.
+func addEvent(ctx context.Context, tx *sql.Tx, e *v1.Event) error {
+	now := time.Now()
+	e.CreateTime = now.Unix()
+	b, err := proto.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO comment_event (payload, create_time) VALUES (?, ?)`, b, now)
+	return err
+}
+
+func (r *eventRepo) Outboxes() int {
+	return len(r.data.databases())
+}
+
+func (r *eventRepo) ListEvent(ctx context.Context, outbox int, after int64, limit int) ([]*v1.Event, error) {
+	rows, err := r.data.databases()[outbox].QueryContext(ctx, `SELECT id, payload, create_time FROM comment_event WHERE id > ? ORDER BY id LIMIT ?`,
+		after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var es []*v1.Event
+	for rows.Next() {
+		var (
+			id         int64
+			payload    []byte
+			createTime time.Time
+		)
+		if err = rows.Scan(&id, &payload, &createTime); err != nil {
+			return nil, err
+		}
+		e := new(v1.Event)
+		if err = proto.Unmarshal(payload, e); err != nil {
+			r.log.WithContext(ctx).Errorf("unmarshal event %d: %v", id, err)
+		}
+		e.Id, e.CreateTime = id, createTime.Unix()
+		es = append(es, e)
+	}
+	return es, rows.Err()
+}
+
+func (r *eventRepo) GetCursor(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := r.data.db.QueryRowContext(ctx, `SELECT event_id FROM comment_job_cursor WHERE name = ?`, name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *eventRepo) SaveCursor(ctx context.Context, name string, id int64) error {
+	now := time.Now()
+	res, err := r.data.db.ExecContext(ctx, `UPDATE comment_job_cursor SET event_id = ?, update_time = ? WHERE name = ?`,
+		id, now, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	_, err = r.data.db.ExecContext(ctx, `INSERT INTO comment_job_cursor (name, event_id, update_time) VALUES (?, ?, ?)`,
+		name, id, now)
+	return err
+}
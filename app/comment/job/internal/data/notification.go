@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// notification is the json line written by the file sink.
+type notification struct {
+	Type         string `json:"type"`
+	MemberID     int64  `json:"member_id"`
+	FromMemberID int64  `json:"from_member_id"`
+	CommentID    int64  `json:"comment_id"`
+	ObjID        int64  `json:"obj_id"`
+	ObjType      int32  `json:"obj_type"`
+	Root         int64  `json:"root"`
+	Parent       int64  `json:"parent"`
+	Count        int32  `json:"count"`
+	CreateTime   int64  `json:"create_time"`
+}
+
+// NewNotificationSink returns the sink configured by notification.sink,
+// both of them are for local development.
+func NewNotificationSink(c *conf.Job, logger log.Logger) (biz.NotificationSink, func(), error) {
+	n := c.GetNotification()
+	if n.GetSink() == "chan" {
+		s := newChanSink(logger)
+		return s, s.close, nil
+	}
+	w := io.WriteCloser(os.Stdout)
+	if n.GetPath() != "" {
+		f, err := os.OpenFile(n.GetPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+		w = f
+	}
+	cleanup := func() {
+		if w != os.Stdout {
+			_ = w.Close()
+		}
+	}
+	return &fileSink{w: w}, cleanup, nil
+}
+
+// fileSink writes the notifications as json lines.
+type fileSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *fileSink) Send(ctx context.Context, ns ...*biz.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(s.w)
+	for _, n := range ns {
+		if err := enc.Encode(&notification{
+			Type:         n.Type,
+			MemberID:     n.MemberID,
+			FromMemberID: n.FromMemberID,
+			CommentID:    n.CommentID,
+			ObjID:        n.ObjID,
+			ObjType:      n.ObjType,
+			Root:         n.Root,
+			Parent:       n.Parent,
+			Count:        n.Count,
+			CreateTime:   n.CreateTime.Unix(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chanSink sends the notifications to a channel drained into the log.
+type chanSink struct {
+	ch   chan *biz.Notification
+	done chan struct{}
+	log  *log.Helper
+}
+
+func newChanSink(logger log.Logger) *chanSink {
+	s := &chanSink{
+		ch:   make(chan *biz.Notification, 1024),
+		done: make(chan struct{}),
+		log:  log.NewHelper(logger),
+	}
+	go func() {
+		defer close(s.done)
+		for n := range s.ch {
+			s.log.Infof("notification %s to %d: comment %d from %d and %d others",
+				n.Type, n.MemberID, n.CommentID, n.FromMemberID, n.Count-1)
+		}
+	}()
+	return s
+}
+
+func (s *chanSink) Send(ctx context.Context, ns ...*biz.Notification) error {
+	for _, n := range ns {
+		select {
+		case s.ch <- n:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *chanSink) close() {
+	close(s.ch)
+	<-s.done
+}
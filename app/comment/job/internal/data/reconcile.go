@@ -0,0 +1,164 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+// commentStateNormal is the state of the counted comments, 同 comment service.
+const commentStateNormal = 0
+
+type reconcileRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewReconcileRepo .
+func NewReconcileRepo(data *Data, logger log.Logger) biz.ReconcileRepo {
+	return &reconcileRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *reconcileRepo) ReconcileSubjects(ctx context.Context, cursor *biz.ReconcileCursor, limit int) ([]*biz.CounterDrift, *biz.ReconcileCursor, error) {
+	for db := cursor.DB; db < len(r.data.shardDBs); db++ {
+		after := cursor.ID
+		if db != cursor.DB {
+			after = 0
+		}
+		ids, err := listSubjectID(ctx, r.data.shardDBs[db], after, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		var ds []*biz.CounterDrift
+		for _, id := range ids {
+			d, err := reconcileSubject(ctx, r.data.shardDBs[db], id)
+			if err != nil {
+				return nil, nil, err
+			}
+			ds = append(ds, d...)
+		}
+		next := &biz.ReconcileCursor{DB: db, ID: ids[len(ids)-1]}
+		if len(ids) < limit {
+			next = &biz.ReconcileCursor{DB: db + 1}
+		}
+		if next.DB >= len(r.data.shardDBs) {
+			next = nil
+		}
+		return ds, next, nil
+	}
+	return nil, nil, nil
+}
+
+func listSubjectID(ctx context.Context, db *sql.DB, after int64, limit int) ([]int64, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id FROM comment_subject WHERE id > ? ORDER BY id LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// reconcileSubject recounts the subject and the replies of its root
+// comments. Each count is read with its counter and pending changes in one
+// statement and the counter is corrected by the difference, so the writes
+// committed meanwhile are kept. The subject row is locked before the reads,
+// like SELECT ... FOR UPDATE, so a reconciliation of the same subject by
+// another instance waits and reads the counters corrected.
+func reconcileSubject(ctx context.Context, db *sql.DB, id int64) ([]*biz.CounterDrift, error) {
+	var ds []*biz.CounterDrift
+	err := runTx(ctx, db, func(tx *sql.Tx) error {
+		var (
+			now                   = time.Now()
+			objID                 int64
+			objType               int32
+			storedRoot, storedAll int32
+			actualRoot, actualAll int32
+		)
+		_, err := tx.ExecContext(ctx, `UPDATE comment_subject SET update_time = update_time WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRowContext(ctx, `SELECT s.obj_id, s.obj_type,
+			s.root_count + COALESCE((SELECT SUM(d.root_count) FROM comment_counter_delta d
+				WHERE d.comment_id = 0 AND d.obj_id = s.obj_id AND d.obj_type = s.obj_type), 0),
+			s.all_count + COALESCE((SELECT SUM(d.all_count) FROM comment_counter_delta d
+				WHERE d.comment_id = 0 AND d.obj_id = s.obj_id AND d.obj_type = s.obj_type), 0),
+			(SELECT COUNT(*) FROM comment_index i
+				WHERE i.obj_id = s.obj_id AND i.obj_type = s.obj_type AND i.root = 0 AND i.state = ?),
+			(SELECT COUNT(*) FROM comment_index i WHERE i.obj_id = s.obj_id AND i.obj_type = s.obj_type AND i.state = ?)
+			FROM comment_subject s WHERE s.id = ?`, commentStateNormal, commentStateNormal, id).
+			Scan(&objID, &objType, &storedRoot, &storedAll, &actualRoot, &actualAll)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, d := range []*biz.CounterDrift{
+			{Counter: biz.CounterSubjectRoot, ObjID: objID, ObjType: objType, Stored: storedRoot, Actual: actualRoot},
+			{Counter: biz.CounterSubjectAll, ObjID: objID, ObjType: objType, Stored: storedAll, Actual: actualAll},
+		} {
+			if d.Stored != d.Actual {
+				ds = append(ds, d)
+			}
+		}
+		if len(ds) > 0 {
+			_, err = tx.ExecContext(ctx, `UPDATE comment_subject SET root_count = root_count + ?, all_count = all_count + ?,
+				update_time = ? WHERE id = ?`, actualRoot-storedRoot, actualAll-storedAll, now, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.QueryContext(ctx, `SELECT i.id,
+			i.root_count + COALESCE((SELECT SUM(d.root_count) FROM comment_counter_delta d WHERE d.comment_id = i.id), 0),
+			(SELECT COUNT(*) FROM comment_index r WHERE r.root = i.id AND r.state = ?)
+			FROM comment_index i WHERE i.obj_id = ? AND i.obj_type = ? AND i.root = 0`,
+			commentStateNormal, objID, objType)
+		if err != nil {
+			return err
+		}
+		var replies []*biz.CounterDrift
+		for rows.Next() {
+			d := &biz.CounterDrift{Counter: biz.CounterReply, ObjID: objID, ObjType: objType}
+			if err = rows.Scan(&d.CommentID, &d.Stored, &d.Actual); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if d.Stored != d.Actual {
+				replies = append(replies, d)
+			}
+		}
+		_ = rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		for _, d := range replies {
+			_, err = tx.ExecContext(ctx, `UPDATE comment_index SET root_count = root_count + ?, update_time = ? WHERE id = ?`,
+				d.Actual-d.Stored, now, d.CommentID)
+			if err != nil {
+				return err
+			}
+		}
+		ds = append(ds, replies...)
+		return nil
+	})
+	return ds, err
+}
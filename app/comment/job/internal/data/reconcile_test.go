@@ -0,0 +1,174 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/metrics"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// testGauge records the last value set per label.
+type testGauge struct {
+	values map[string]float64
+	label  string
+}
+
+func (g *testGauge) With(lvs ...string) metrics.Gauge {
+	return &testGauge{values: g.values, label: lvs[0]}
+}
+
+func (g *testGauge) Set(value float64) { g.values[g.label] = value }
+func (g *testGauge) Add(delta float64) { g.values[g.label] += delta }
+func (g *testGauge) Sub(delta float64) { g.values[g.label] -= delta }
+
+// seedDrift writes subject 1 1 counting 5 root comments and 5 comments with
+// one more comment pending, for the normal roots 1 and 2, the reply 3 of
+// root 1 and the removed root 4. Root 1 counts no reply, root 2 counts 2
+// replies with 2 fewer pending.
+func seedDrift(t *testing.T, db *sql.DB) {
+	t.Helper()
+	now := time.Now()
+	_, err := db.Exec(`INSERT INTO comment_subject
+		(obj_id, obj_type, member_id, count, root_count, all_count, state, create_time, update_time)
+		VALUES (1, 1, 9, 4, 5, 5, 0, ?, ?)`, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		id, root         int64
+		state, rootCount int
+	}{{1, 0, 0, 0}, {2, 0, 0, 2}, {3, 1, 0, 0}, {4, 0, 1, 0}} {
+		_, err = db.Exec(`INSERT INTO comment_index
+			(id, obj_id, obj_type, member_id, root, floor, root_count, state, create_time, update_time)
+			VALUES (?, 1, 1, 10, ?, ?, ?, ?, ?, ?)`, c.id, c.root, c.id, c.rootCount, c.state, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	addDelta(t, db, 2, 0, 0, -2, 0)
+	addDelta(t, db, 0, 0, 0, 0, 1)
+}
+
+// readReconciled returns the stored root and all counts of subject 1 1 and
+// the stored reply counts of roots 1 and 2, without the pending changes.
+func readReconciled(t *testing.T, db *sql.DB) [4]int32 {
+	t.Helper()
+	var c [4]int32
+	err := db.QueryRow(`SELECT root_count, all_count FROM comment_subject WHERE obj_id = 1 AND obj_type = 1`).
+		Scan(&c[0], &c[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []int64{1, 2} {
+		if err = db.QueryRow(`SELECT root_count FROM comment_index WHERE id = ?`, id).Scan(&c[2+i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestReconcileSubjects(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		d    = newTestData(t)
+		repo = NewReconcileRepo(d, log.DefaultLogger)
+	)
+	seedDrift(t, d.db)
+
+	ds, next, err := repo.ReconcileSubjects(ctx, new(biz.ReconcileCursor), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != nil {
+		t.Fatalf("got cursor %+v after the last subject", next)
+	}
+	want := map[string]biz.CounterDrift{
+		biz.CounterSubjectRoot: {Counter: biz.CounterSubjectRoot, ObjID: 1, ObjType: 1, Stored: 5, Actual: 2},
+		biz.CounterSubjectAll:  {Counter: biz.CounterSubjectAll, ObjID: 1, ObjType: 1, Stored: 6, Actual: 3},
+		biz.CounterReply:       {Counter: biz.CounterReply, ObjID: 1, ObjType: 1, CommentID: 1, Stored: 0, Actual: 1},
+	}
+	if len(ds) != len(want) {
+		t.Fatalf("got %d drifts, want %d", len(ds), len(want))
+	}
+	for _, d := range ds {
+		if *d != want[d.Counter] {
+			t.Fatalf("got drift %+v, want %+v", *d, want[d.Counter])
+		}
+	}
+	// the counters are corrected by the difference, the pending changes are kept
+	if got := readReconciled(t, d.db); got != [4]int32{2, 2, 1, 2} {
+		t.Fatalf("got counters %v after the reconciliation", got)
+	}
+
+	// the counters corrected are not corrected again
+	if ds, _, err = repo.ReconcileSubjects(ctx, new(biz.ReconcileCursor), 10); err != nil || len(ds) != 0 {
+		t.Fatalf("got %d drifts after the reconciliation, error %v", len(ds), err)
+	}
+}
+
+func TestReconcileConcurrent(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = newTestData(t)
+		wg  sync.WaitGroup
+	)
+	seedDrift(t, d.db)
+
+	// two instances reconciling at once correct the counters once
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := NewReconcileRepo(d, log.DefaultLogger).ReconcileSubjects(ctx, new(biz.ReconcileCursor), 10)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readReconciled(t, d.db); got != [4]int32{2, 2, 1, 2} {
+		t.Fatalf("got counters %v after concurrent reconciliations", got)
+	}
+}
+
+func TestReconcileDrift(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		d     = newTestData(t)
+		gauge = &testGauge{values: make(map[string]float64)}
+		uc    = biz.NewReconcileUsecase(&conf.Job{Reconcile: &conf.Job_Reconcile{BatchSize: 1}},
+			NewReconcileRepo(d, log.DefaultLogger), gauge, log.DefaultLogger)
+	)
+	seedDrift(t, d.db)
+
+	if err := uc.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{biz.CounterSubjectRoot: 3, biz.CounterSubjectAll: 3, biz.CounterReply: 1}
+	for counter, v := range want {
+		if gauge.values[counter] != v {
+			t.Fatalf("got drift %v of %s, want %v", gauge.values[counter], counter, v)
+		}
+	}
+
+	// the next run finds no drift
+	if err := uc.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for counter := range want {
+		if gauge.values[counter] != 0 {
+			t.Fatalf("got drift %v of %s after the repair", gauge.values[counter], counter)
+		}
+	}
+}
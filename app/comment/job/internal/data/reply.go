@@ -0,0 +1,123 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+type replyRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewReplyRepo .
+func NewReplyRepo(data *Data, logger log.Logger) biz.ReplyRepo {
+	return &replyRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *replyRepo) AddReply(ctx context.Context, p *biz.PendingReply) error {
+	return runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		var id int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM comment_reply_pending
+			WHERE type = ? AND member_id = ? AND target = ? AND target_type = ?`,
+			p.Type, p.MemberID, p.Target, p.TargetType).Scan(&id)
+		switch {
+		case err == sql.ErrNoRows:
+			res, err := tx.ExecContext(ctx, `INSERT INTO comment_reply_pending
+				(type, member_id, target, target_type, from_member_id, comment_id, obj_id, obj_type, root, parent,
+				count, first_time, create_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)`,
+				p.Type, p.MemberID, p.Target, p.TargetType, p.FromMemberID, p.CommentID, p.ObjID, p.ObjType,
+				p.Root, p.Parent, p.FirstTime, p.CreateTime)
+			if err != nil {
+				return err
+			}
+			if id, err = res.LastInsertId(); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+		p.ID = id
+
+		// a replayed event counts its author once
+		var n int
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_reply_pending_member
+			WHERE pending_id = ? AND member_id = ?`, id, p.FromMemberID).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			_, err = tx.ExecContext(ctx, `INSERT INTO comment_reply_pending_member (pending_id, member_id) VALUES (?, ?)`,
+				id, p.FromMemberID)
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_reply_pending_member WHERE pending_id = ?`,
+			id).Scan(&p.Count)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE comment_reply_pending SET from_member_id = ?, comment_id = ?,
+			obj_id = ?, obj_type = ?, root = ?, parent = ?, count = ?, create_time = ? WHERE id = ?`,
+			p.FromMemberID, p.CommentID, p.ObjID, p.ObjType, p.Root, p.Parent, p.Count, p.CreateTime, id)
+		return err
+	})
+}
+
+func (r *replyRepo) ListReply(ctx context.Context, before time.Time, limit int) ([]*biz.PendingReply, error) {
+	query := `SELECT id, type, member_id, target, target_type, from_member_id, comment_id, obj_id, obj_type,
+		root, parent, count, first_time, create_time FROM comment_reply_pending`
+	args := []interface{}{limit}
+	if !before.IsZero() {
+		query += ` WHERE first_time <= ?`
+		args = []interface{}{before, limit}
+	}
+	rows, err := r.data.db.QueryContext(ctx, query+` ORDER BY first_time LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ps []*biz.PendingReply
+	for rows.Next() {
+		p := &biz.PendingReply{Notification: new(biz.Notification)}
+		err = rows.Scan(&p.ID, &p.Type, &p.MemberID, &p.Target, &p.TargetType, &p.FromMemberID, &p.CommentID,
+			&p.ObjID, &p.ObjType, &p.Root, &p.Parent, &p.Count, &p.FirstTime, &p.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	return ps, rows.Err()
+}
+
+func (r *replyRepo) DeleteReply(ctx context.Context, ps []*biz.PendingReply) error {
+	return runTx(ctx, r.data.db, func(tx *sql.Tx) error {
+		for _, p := range ps {
+			res, err := tx.ExecContext(ctx, `DELETE FROM comment_reply_pending WHERE id = ? AND comment_id = ?`,
+				p.ID, p.CommentID)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				continue
+			}
+			_, err = tx.ExecContext(ctx, `DELETE FROM comment_reply_pending_member WHERE pending_id = ?`, p.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
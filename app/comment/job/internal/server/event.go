@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+	"github.com/zldongly/comment/app/comment/job/internal/service"
+)
+
+const (
+	eventConsumer     = "comment-job"
+	defaultPollPeriod = time.Second
+	defaultBatchSize  = 100
+	defaultEventGrace = 10 * time.Second
+)
+
+var _ transport.Server = (*EventServer)(nil)
+
+// EventServer polls the comment events and hands them to the job service.
+type EventServer struct {
+	uc       *biz.EventUsecase
+	job      *service.JobService
+	interval time.Duration
+	batch    int
+	grace    time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	log      *log.Helper
+}
+
+// NewEventServer new an event server.
+func NewEventServer(c *conf.Server, uc *biz.EventUsecase, job *service.JobService, logger log.Logger) *EventServer {
+	s := &EventServer{
+		uc:       uc,
+		job:      job,
+		interval: defaultPollPeriod,
+		batch:    defaultBatchSize,
+		grace:    defaultEventGrace,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		log:      log.NewHelper(logger),
+	}
+	if c.GetEvent().GetPollInterval() != nil {
+		s.interval = c.Event.PollInterval.AsDuration()
+	}
+	if c.GetEvent().GetBatchSize() > 0 {
+		s.batch = int(c.Event.BatchSize)
+	}
+	if c.GetEvent().GetGrace() != nil {
+		s.grace = c.Event.Grace.AsDuration()
+	}
+	return s
+}
+
+// Start polls until ctx is done or Stop is called, the aggregated
+// notifications and the counter changes are flushed between the polls and
+// on exit.
+func (s *EventServer) Start(ctx context.Context) error {
+	defer close(s.done)
+	defer func() {
+		if err := s.job.Close(context.Background()); err != nil {
+			s.log.Errorf("flush: %v", err)
+		}
+	}()
+	s.log.Infof("[event] server polling every %s", s.interval)
+	for {
+		n, err := s.uc.Consume(ctx, eventConsumer, s.batch, s.grace, s.job.HandleEvent)
+		if err != nil && ctx.Err() == nil {
+			s.log.Errorf("consume events: %v", err)
+		}
+		if err := s.job.Flush(ctx); err != nil && ctx.Err() == nil {
+			s.log.Errorf("flush: %v", err)
+		}
+		// keep going while the batches are full
+		if err == nil && n == s.batch {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stop:
+			return nil
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// Stop stops polling and waits for the batch in progress.
+func (s *EventServer) Stop(ctx context.Context) error {
+	s.log.Info("[event] server stopping")
+	close(s.stop)
+	<-s.done
+	return nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"expvar"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/metrics"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+)
+
+// NewHTTPServer new a HTTP server serving the expvar metrics on /debug/vars.
+func NewHTTPServer(c *conf.Server, logger log.Logger) *http.Server {
+	opts := []http.ServerOption{http.Logger(logger)}
+	if c.GetHttp().GetNetwork() != "" {
+		opts = append(opts, http.Network(c.Http.Network))
+	}
+	if c.GetHttp().GetAddr() != "" {
+		opts = append(opts, http.Address(c.Http.Addr))
+	}
+	srv := http.NewServer(opts...)
+	srv.Handle("/debug/vars", expvar.Handler())
+	return srv
+}
+
+// NewDriftGauge returns the gauge of the counter drift found by the last
+// reconciliation, published as the expvar map comment_counter_drift.
+func NewDriftGauge() metrics.Gauge {
+	return &expvarGauge{m: expvar.NewMap("comment_counter_drift")}
+}
+
+// expvarGauge is a gauge in an expvar map keyed by the label values joined by ":".
+type expvarGauge struct {
+	m   *expvar.Map
+	key string
+}
+
+func (g *expvarGauge) With(lvs ...string) metrics.Gauge {
+	return &expvarGauge{m: g.m, key: strings.Join(lvs, ":")}
+}
+
+func (g *expvarGauge) Set(value float64) {
+	v := new(expvar.Float)
+	v.Set(value)
+	g.m.Set(g.key, v)
+}
+
+func (g *expvarGauge) Add(delta float64) {
+	g.m.AddFloat(g.key, delta)
+}
+
+func (g *expvarGauge) Sub(delta float64) {
+	g.m.AddFloat(g.key, -delta)
+}
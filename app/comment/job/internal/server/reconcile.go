@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/zldongly/comment/app/comment/job/internal/conf"
+	"github.com/zldongly/comment/app/comment/job/internal/service"
+)
+
+var _ transport.Server = (*ReconcileServer)(nil)
+
+// ReconcileServer reconciles the counters at intervals.
+type ReconcileServer struct {
+	job      *service.JobService
+	interval time.Duration // 为 0 不对账
+	stop     chan struct{}
+	done     chan struct{}
+	log      *log.Helper
+}
+
+// NewReconcileServer new a reconcile server.
+func NewReconcileServer(c *conf.Job, job *service.JobService, logger log.Logger) *ReconcileServer {
+	return &ReconcileServer{
+		job:      job,
+		interval: c.GetReconcile().GetInterval().AsDuration(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		log:      log.NewHelper(logger),
+	}
+}
+
+// Start reconciles every interval until ctx is done or Stop is called, a
+// reconciliation in progress is cancelled by Stop.
+func (s *ReconcileServer) Start(ctx context.Context) error {
+	defer close(s.done)
+	if s.interval <= 0 {
+		s.log.Info("[reconcile] server disabled")
+		select {
+		case <-ctx.Done():
+		case <-s.stop:
+		}
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	s.log.Infof("[reconcile] server reconciling every %s", s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.interval):
+		}
+		if err := s.job.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			s.log.Errorf("reconcile counters: %v", err)
+		}
+	}
+}
+
+// Stop stops reconciling and waits for the reconciliation to return.
+func (s *ReconcileServer) Stop(ctx context.Context) error {
+	s.log.Info("[reconcile] server stopping")
+	close(s.stop)
+	<-s.done
+	return nil
+}
@@ -0,0 +1,6 @@
+package server
+
+import "github.com/google/wire"
+
+// ProviderSet is server providers.
+var ProviderSet = wire.NewSet(NewEventServer, NewReconcileServer, NewHTTPServer, NewDriftGauge)
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/job/internal/biz"
+)
+
+type JobService struct {
+	comment   *biz.CommentUsecase
+	mention   *biz.MentionUsecase
+	reply     *biz.ReplyUsecase
+	erasure   *biz.ErasureUsecase
+	cache     *biz.CacheUsecase
+	counter   *biz.CounterUsecase
+	reconcile *biz.ReconcileUsecase
+	log       *log.Helper
+}
+
+func NewJobService(comment *biz.CommentUsecase, mention *biz.MentionUsecase, reply *biz.ReplyUsecase, erasure *biz.ErasureUsecase,
+	cache *biz.CacheUsecase, counter *biz.CounterUsecase, reconcile *biz.ReconcileUsecase, logger log.Logger) *JobService {
+	return &JobService{
+		comment:   comment,
+		mention:   mention,
+		reply:     reply,
+		erasure:   erasure,
+		cache:     cache,
+		counter:   counter,
+		reconcile: reconcile,
+		log:       log.NewHelper(logger),
+	}
+}
+
+// HandleEvent dispatches the event written by the comment service.
+func (s *JobService) HandleEvent(ctx context.Context, e *v1.Event) error {
+	switch ev := e.Event.(type) {
+	case *v1.Event_CommentCreated:
+		if err := s.comment.CommentCreated(ctx, ev.CommentCreated); err != nil {
+			return err
+		}
+		if err := s.cache.CommentCreated(ctx, ev.CommentCreated); err != nil {
+			return err
+		}
+		if err := s.mention.CommentCreated(ctx, ev.CommentCreated); err != nil {
+			return err
+		}
+		return s.reply.CommentCreated(ctx, ev.CommentCreated)
+	case *v1.Event_CommentChanged:
+		if err := s.comment.CommentChanged(ctx, ev.CommentChanged); err != nil {
+			return err
+		}
+		return s.cache.CommentChanged(ctx, ev.CommentChanged)
+	case *v1.Event_MemberErasureRequested:
+		return s.erasure.MemberErasureRequested(ctx, ev.MemberErasureRequested)
+	default:
+		s.log.WithContext(ctx).Warnf("skip unknown event %d", e.Id)
+	}
+	return nil
+}
+
+// Flush sends the aggregated notifications whose window ended and merges
+// the pending counter changes at their interval.
+func (s *JobService) Flush(ctx context.Context) error {
+	return s.flush(ctx, time.Now())
+}
+
+// Close sends all the aggregated notifications and merges all the pending
+// counter changes.
+func (s *JobService) Close(ctx context.Context) error {
+	return s.flush(ctx, time.Time{})
+}
+
+func (s *JobService) flush(ctx context.Context, now time.Time) error {
+	err := s.reply.Flush(ctx, now)
+	if cerr := s.counter.Flush(ctx, now); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Reconcile repairs the counters drifted from the index rows.
+func (s *JobService) Reconcile(ctx context.Context) error {
+	return s.reconcile.Reconcile(ctx)
+}
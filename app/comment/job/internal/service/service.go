@@ -0,0 +1,6 @@
+package service
+
+import "github.com/google/wire"
+
+// ProviderSet is service providers.
+var ProviderSet = wire.NewSet(NewJobService)
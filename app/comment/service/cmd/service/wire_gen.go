@@ -0,0 +1,60 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//+build !wireinject
+
+package main
+
+import (
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"github.com/zldongly/comment/app/comment/service/internal/data"
+	"github.com/zldongly/comment/app/comment/service/internal/server"
+	"github.com/zldongly/comment/app/comment/service/internal/service"
+)
+
+// Injectors from wire.go:
+
+// initApp init kratos application.
+func initApp(confServer *conf.Server, confData *conf.Data, comment *conf.Comment, logger log.Logger) (*kratos.App, func(), error) {
+	dataData, cleanup, err := data.NewData(confData, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	subjectRepo := data.NewSubjectRepo(dataData, logger)
+	commentRepo := data.NewCommentRepo(dataData, logger)
+	memberRepo, err := data.NewMemberRepo(confData, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	attachmentRepo := data.NewAttachmentRepo(dataData, logger)
+	attachmentStorage := data.NewAttachmentStorage(confData, logger)
+	attachmentUsecase := biz.NewAttachmentUsecase(comment, attachmentRepo, attachmentStorage, logger)
+	spamRepo := data.NewSpamRepo(dataData, logger)
+	spamUsecase := biz.NewSpamUsecase(comment, spamRepo, logger)
+	blockRepo := data.NewBlockRepo(dataData, logger)
+	blockUsecase := biz.NewBlockUsecase(blockRepo, logger)
+	muteRepo := data.NewMuteRepo(dataData, logger)
+	muteUsecase := biz.NewMuteUsecase(muteRepo, logger)
+	commentUsecase := biz.NewCommentUsecase(comment, subjectRepo, commentRepo, memberRepo, attachmentUsecase, spamUsecase, blockUsecase, muteUsecase, logger)
+	reportRepo := data.NewReportRepo(dataData, logger)
+	moderationRepo := data.NewModerationRepo(dataData, logger)
+	reportUsecase := biz.NewReportUsecase(comment, commentRepo, reportRepo, moderationRepo, logger)
+	likeRepo := data.NewLikeRepo(dataData, logger)
+	auditRepo := data.NewAuditRepo(dataData, logger)
+	exportUsecase := biz.NewExportUsecase(commentRepo, likeRepo, reportRepo, auditRepo, logger)
+	erasureRepo := data.NewErasureRepo(dataData, logger)
+	erasureUsecase := biz.NewErasureUsecase(erasureRepo, logger)
+	commentService := service.NewCommentService(commentUsecase, reportUsecase, blockUsecase, muteUsecase, attachmentUsecase, exportUsecase, erasureUsecase, logger)
+	rateLimitRepo := data.NewRateLimitRepo(dataData, logger)
+	rateLimitUsecase := biz.NewRateLimitUsecase(comment, rateLimitRepo, logger)
+	httpServer := server.NewHTTPServer(confServer, commentService, rateLimitUsecase, logger)
+	grpcServer := server.NewGRPCServer(confServer, commentService, rateLimitUsecase, logger)
+	app := newApp(logger, httpServer, grpcServer)
+	return app, func() {
+		cleanup()
+	}, nil
+}
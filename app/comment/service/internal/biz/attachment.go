@@ -0,0 +1,204 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+
+	// image formats accepted as attachments
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// 图片的默认限制
+const (
+	defaultAttachmentSize  = 2 << 20 // grpc 默认最大消息为 4MB
+	defaultAttachmentPixel = 8192
+	defaultAttachmentCount = 9
+)
+
+var defaultAttachmentFormats = []string{"png", "jpeg", "gif"}
+
+// ErrAttachmentUsed is attachment attached to another comment.
+var ErrAttachmentUsed = v1.ErrorAttachmentInvalid("attachment is used")
+
+// Attachment is an image uploaded for a comment.
+type Attachment struct {
+	ID         int64
+	Token      string
+	MemberID   int64 // 上传者
+	CommentID  int64 // 0 为还未使用
+	Key        string
+	URL        string
+	Format     string
+	Size       int64
+	Width      int32
+	Height     int32
+	CreateTime time.Time
+}
+
+// AttachmentStorage stores the attachment files.
+type AttachmentStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL returns the url the file of key is served at.
+	URL(key string) string
+}
+
+// AttachmentRepo is attachment storage, attachments are bound to the
+// comment by CommentRepo.CreateComment.
+type AttachmentRepo interface {
+	CreateAttachment(ctx context.Context, a *Attachment) error
+	// ListAttachmentByToken returns the attachments of tokens, unknown tokens are left out.
+	ListAttachmentByToken(ctx context.Context, tokens []string) ([]*Attachment, error)
+}
+
+// AttachmentUsecase is attachment usecase.
+type AttachmentUsecase struct {
+	maxSize   int64
+	maxWidth  int32
+	maxHeight int32
+	maxCount  int
+	formats   map[string]bool
+	repo      AttachmentRepo
+	storage   AttachmentStorage
+	log       *log.Helper
+}
+
+// NewAttachmentUsecase new an attachment usecase.
+func NewAttachmentUsecase(c *conf.Comment, repo AttachmentRepo, storage AttachmentStorage, logger log.Logger) *AttachmentUsecase {
+	ac := c.GetAttachment()
+	uc := &AttachmentUsecase{
+		maxSize:   ac.GetMaxSize(),
+		maxWidth:  ac.GetMaxWidth(),
+		maxHeight: ac.GetMaxHeight(),
+		maxCount:  int(ac.GetMaxCount()),
+		formats:   make(map[string]bool),
+		repo:      repo,
+		storage:   storage,
+		log:       log.NewHelper(logger),
+	}
+	if uc.maxSize <= 0 {
+		uc.maxSize = defaultAttachmentSize
+	}
+	if uc.maxWidth <= 0 {
+		uc.maxWidth = defaultAttachmentPixel
+	}
+	if uc.maxHeight <= 0 {
+		uc.maxHeight = defaultAttachmentPixel
+	}
+	if uc.maxCount <= 0 {
+		uc.maxCount = defaultAttachmentCount
+	}
+	formats := ac.GetFormats()
+	if len(formats) == 0 {
+		formats = defaultAttachmentFormats
+	}
+	for _, f := range formats {
+		uc.formats[f] = true
+	}
+	return uc
+}
+
+// Upload validates the size, format and dimension of the image and stores
+// it, the token of the returned attachment is used to attach it to a comment.
+func (uc *AttachmentUsecase) Upload(ctx context.Context, memberID int64, data []byte) (*Attachment, error) {
+	if len(data) == 0 {
+		return nil, v1.ErrorAttachmentInvalid("image is empty")
+	}
+	if int64(len(data)) > uc.maxSize {
+		return nil, v1.ErrorAttachmentInvalid("image is larger than %d bytes", uc.maxSize)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !uc.formats[format] {
+		return nil, v1.ErrorAttachmentInvalid("unsupported image format")
+	}
+	if int32(cfg.Width) > uc.maxWidth || int32(cfg.Height) > uc.maxHeight {
+		return nil, v1.ErrorAttachmentInvalid("image is larger than %dx%d", uc.maxWidth, uc.maxHeight)
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	name, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	a := &Attachment{
+		Token:    token,
+		MemberID: memberID,
+		Key:      fmt.Sprintf("%s/%d_%s.%s", now.Format("2006/01/02"), memberID, name, format),
+		Format:   format,
+		Size:     int64(len(data)),
+		Width:    int32(cfg.Width),
+		Height:   int32(cfg.Height),
+	}
+	a.URL = uc.storage.URL(a.Key)
+	if err = uc.storage.Put(ctx, a.Key, data); err != nil {
+		return nil, err
+	}
+	if err = uc.repo.CreateAttachment(ctx, a); err != nil {
+		if derr := uc.storage.Delete(ctx, a.Key); derr != nil {
+			uc.log.WithContext(ctx).Errorf("delete attachment %s: %v", a.Key, derr)
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// resolve returns the attachments of the tokens in as, they must be
+// uploaded by the member and not used by another comment.
+func (uc *AttachmentUsecase) resolve(ctx context.Context, memberID int64, as []*Attachment) ([]*Attachment, error) {
+	if len(as) == 0 {
+		return nil, nil
+	}
+	var (
+		tokens = make([]string, 0, len(as))
+		seen   = make(map[string]bool, len(as))
+	)
+	for _, a := range as {
+		if !seen[a.Token] {
+			seen[a.Token] = true
+			tokens = append(tokens, a.Token)
+		}
+	}
+	if len(tokens) > uc.maxCount {
+		return nil, v1.ErrorAttachmentInvalid("attach at most %d images", uc.maxCount)
+	}
+	found, err := uc.repo.ListAttachmentByToken(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	byToken := make(map[string]*Attachment, len(found))
+	for _, a := range found {
+		byToken[a.Token] = a
+	}
+	res := make([]*Attachment, 0, len(tokens))
+	for _, t := range tokens {
+		a, ok := byToken[t]
+		if !ok || a.MemberID != memberID || a.CommentID != 0 {
+			return nil, v1.ErrorAttachmentInvalid("attachment token %q is invalid", t)
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,200 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// attachmentRepo keeps the attachments by token.
+type attachmentRepo struct {
+	tokens map[string]*Attachment
+	err    error
+}
+
+func (r *attachmentRepo) CreateAttachment(_ context.Context, a *Attachment) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.tokens[a.Token] = a
+	return nil
+}
+
+func (r *attachmentRepo) ListAttachmentByToken(_ context.Context, tokens []string) ([]*Attachment, error) {
+	var as []*Attachment
+	for _, t := range tokens {
+		if a, ok := r.tokens[t]; ok {
+			as = append(as, a)
+		}
+	}
+	return as, nil
+}
+
+// attachmentStorage keeps the files in memory.
+type attachmentStorage map[string][]byte
+
+func (s attachmentStorage) Put(_ context.Context, key string, data []byte) error {
+	s[key] = data
+	return nil
+}
+
+func (s attachmentStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s attachmentStorage) URL(key string) string {
+	return "https://example.com/" + key
+}
+
+func encodeImage(t *testing.T, format string, w, h int) []byte {
+	var (
+		buf bytes.Buffer
+		img = image.NewGray(image.Rect(0, 0, w, h))
+		err error
+	)
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAttachmentUpload(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		small    = encodeImage(t, "png", 1, 1)
+		withSize = func(maxSize int64) *conf.Comment {
+			return &conf.Comment{Attachment: &conf.Comment_Attachment{
+				MaxSize:   maxSize,
+				MaxWidth:  16,
+				MaxHeight: 8,
+				Formats:   []string{"png", "gif"},
+			}}
+		}
+		c = withSize(0)
+	)
+	for _, tc := range []struct {
+		name    string
+		maxSize int64 // 0 为默认
+		data    []byte
+		ok      bool
+	}{
+		{"at max size", int64(len(small)), small, true},
+		{"over max size", int64(len(small)) - 1, small, false},
+		{"empty", 0, nil, false},
+		{"not an image", 0, []byte("GIF87a"), false},
+		{"format not allowed", 0, encodeImage(t, "jpeg", 1, 1), false},
+		{"at max dimension", 0, encodeImage(t, "gif", 16, 8), true},
+		{"over max width", 0, encodeImage(t, "gif", 17, 8), false},
+		{"over max height", 0, encodeImage(t, "gif", 16, 9), false},
+	} {
+		var (
+			repo    = &attachmentRepo{tokens: make(map[string]*Attachment)}
+			storage = make(attachmentStorage)
+			uc      = NewAttachmentUsecase(withSize(tc.maxSize), repo, storage, log.DefaultLogger)
+		)
+		a, err := uc.Upload(ctx, 1, tc.data)
+		if !tc.ok {
+			if !v1.IsAttachmentInvalid(err) {
+				t.Errorf("%s: got error %v, want attachment invalid", tc.name, err)
+			}
+			if len(storage) != 0 || len(repo.tokens) != 0 {
+				t.Errorf("%s: stored a rejected image", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: got error %v", tc.name, err)
+		}
+		if a.Token == "" || a.MemberID != 1 || a.URL != storage.URL(a.Key) || storage[a.Key] == nil ||
+			repo.tokens[a.Token] != a {
+			t.Errorf("%s: got attachment %+v", tc.name, a)
+		}
+	}
+
+	var (
+		repo    = &attachmentRepo{err: errors.New("insert failed")}
+		storage = make(attachmentStorage)
+	)
+	if _, err := NewAttachmentUsecase(c, repo, storage, log.DefaultLogger).Upload(ctx, 1, small); err != repo.err {
+		t.Fatalf("got error %v", err)
+	}
+	if len(storage) != 0 {
+		t.Fatal("kept the file of a failed upload")
+	}
+}
+
+func TestAttachmentResolve(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		repo = &attachmentRepo{tokens: map[string]*Attachment{
+			"a": {ID: 1, Token: "a", MemberID: 1},
+			"b": {ID: 2, Token: "b", MemberID: 1},
+			"c": {ID: 3, Token: "c", MemberID: 1},
+			"d": {ID: 4, Token: "d", MemberID: 2},
+			"e": {ID: 5, Token: "e", MemberID: 1, CommentID: 9},
+		}}
+		uc = NewAttachmentUsecase(&conf.Comment{Attachment: &conf.Comment_Attachment{MaxCount: 2}}, repo,
+			make(attachmentStorage), log.DefaultLogger)
+	)
+	for _, tc := range []struct {
+		name   string
+		tokens []string
+		want   []int64
+		ok     bool
+	}{
+		{"none", nil, nil, true},
+		{"at max count", []string{"a", "b"}, []int64{1, 2}, true},
+		{"duplicates counted once", []string{"b", "a", "b"}, []int64{2, 1}, true},
+		{"over max count", []string{"a", "b", "c"}, nil, false},
+		{"unknown token", []string{"a", "x"}, nil, false},
+		{"uploaded by another member", []string{"d"}, nil, false},
+		{"used by another comment", []string{"e"}, nil, false},
+	} {
+		as := make([]*Attachment, 0, len(tc.tokens))
+		for _, token := range tc.tokens {
+			as = append(as, &Attachment{Token: token})
+		}
+		res, err := uc.resolve(ctx, 1, as)
+		if !tc.ok {
+			if !v1.IsAttachmentInvalid(err) {
+				t.Errorf("%s: got error %v, want attachment invalid", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: got error %v", tc.name, err)
+		}
+		var ids []int64
+		for _, a := range res {
+			ids = append(ids, a.ID)
+		}
+		if len(ids) != len(tc.want) {
+			t.Errorf("%s: got attachments %v, want %v", tc.name, ids, tc.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tc.want[i] {
+				t.Errorf("%s: got attachments %v, want %v", tc.name, ids, tc.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// 审计日志的操作
+const (
+	AuditActionExport     = "export"      // 导出用户数据
+	AuditActionErase      = "erase"       // 要求抹除用户数据
+	AuditActionEraseStart = "erase_start" // comment job 开始抹除
+	AuditActionEraseDone  = "erase_done"  // comment job 完成抹除
+)
+
+// Audit is an entry of the audit trail of admin operations on member data.
+type Audit struct {
+	ID         int64
+	Action     string
+	OperatorID int64 // 管理员, comment job 为 0
+	MemberID   int64
+	Detail     string
+	CreateTime time.Time
+}
+
+// AuditRepo is audit trail storage.
+type AuditRepo interface {
+	AddAudit(ctx context.Context, a *Audit) error
+}
@@ -0,0 +1,7 @@
+package biz
+
+import "github.com/google/wire"
+
+// ProviderSet is biz providers.
+var ProviderSet = wire.NewSet(NewCommentUsecase, NewReportUsecase, NewRateLimitUsecase, NewSpamUsecase, NewBlockUsecase, NewMuteUsecase, NewAttachmentUsecase,
+	NewExportUsecase, NewErasureUsecase)
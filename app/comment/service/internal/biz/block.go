@@ -0,0 +1,137 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// ErrMemberBlocked is member blocked by the subject owner or banned.
+var ErrMemberBlocked = v1.ErrorMemberBlocked("member blocked")
+
+// Block is a member blocked by an owner, OwnerID 0 is a global ban.
+type Block struct {
+	ID         int64
+	OwnerID    int64
+	MemberID   int64
+	Reason     string
+	ExpireTime time.Time // 零值为永久
+	CreateTime time.Time
+}
+
+// Active reports whether the block is not expired at now.
+func (b *Block) Active(now time.Time) bool {
+	return b.ExpireTime.IsZero() || b.ExpireTime.After(now)
+}
+
+// BlockRepo is block storage.
+type BlockRepo interface {
+	// SaveBlock creates the block or updates its reason and expire time.
+	SaveBlock(ctx context.Context, b *Block) error
+	DeleteBlock(ctx context.Context, ownerID, memberID int64) error
+	// ListMemberBlock returns the blocks of member by the owners, expired ones included.
+	ListMemberBlock(ctx context.Context, memberID int64, ownerIDs []int64) ([]*Block, error)
+	// ListBlock returns the active blocks of owner at now ordered by create time desc.
+	ListBlock(ctx context.Context, ownerID int64, now time.Time, offset, limit int) ([]*Block, int32, error)
+}
+
+// BlockUsecase is block usecase.
+type BlockUsecase struct {
+	repo BlockRepo
+	log  *log.Helper
+}
+
+// NewBlockUsecase new a block usecase.
+func NewBlockUsecase(repo BlockRepo, logger log.Logger) *BlockUsecase {
+	return &BlockUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// Block blocks a member under the contents of the owner, a global ban is
+// only created by Ban.
+func (uc *BlockUsecase) Block(ctx context.Context, b *Block) error {
+	if b.OwnerID <= 0 {
+		return v1.ErrorArgumentInvalid("owner is missing")
+	}
+	if b.OwnerID == b.MemberID {
+		return v1.ErrorArgumentInvalid("owner can not block itself")
+	}
+	return uc.save(ctx, b)
+}
+
+// Ban bans a member from commenting anywhere.
+func (uc *BlockUsecase) Ban(ctx context.Context, b *Block) error {
+	b.OwnerID = 0
+	return uc.save(ctx, b)
+}
+
+func (uc *BlockUsecase) save(ctx context.Context, b *Block) error {
+	if b.MemberID <= 0 {
+		return v1.ErrorArgumentInvalid("member is missing")
+	}
+	if !b.ExpireTime.IsZero() && !b.Active(time.Now()) {
+		return v1.ErrorArgumentInvalid("expire time %v is in the past", b.ExpireTime)
+	}
+	return uc.repo.SaveBlock(ctx, b)
+}
+
+// Unblock unblocks a member.
+func (uc *BlockUsecase) Unblock(ctx context.Context, ownerID, memberID int64) error {
+	if ownerID <= 0 {
+		return v1.ErrorArgumentInvalid("owner is missing")
+	}
+	return uc.repo.DeleteBlock(ctx, ownerID, memberID)
+}
+
+// Unban lifts the global ban of a member.
+func (uc *BlockUsecase) Unban(ctx context.Context, memberID int64) error {
+	return uc.repo.DeleteBlock(ctx, 0, memberID)
+}
+
+// ListBlocked lists the active blocks of owner.
+func (uc *BlockUsecase) ListBlocked(ctx context.Context, ownerID int64, offset, limit int) ([]*Block, int32, error) {
+	if ownerID <= 0 {
+		return nil, 0, v1.ErrorArgumentInvalid("owner is missing")
+	}
+	return uc.repo.ListBlock(ctx, ownerID, time.Now(), offset, limit)
+}
+
+// ListBanned lists the active global bans.
+func (uc *BlockUsecase) ListBanned(ctx context.Context, offset, limit int) ([]*Block, int32, error) {
+	return uc.repo.ListBlock(ctx, 0, time.Now(), offset, limit)
+}
+
+// Check returns ErrMemberBlocked when the member is banned or blocked by owner.
+func (uc *BlockUsecase) Check(ctx context.Context, ownerID, memberID int64) error {
+	blocked, err := uc.IsBlocked(ctx, ownerID, memberID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrMemberBlocked
+	}
+	return nil
+}
+
+// IsBlocked reports whether the member is banned or blocked by owner.
+func (uc *BlockUsecase) IsBlocked(ctx context.Context, ownerID, memberID int64) (bool, error) {
+	if memberID == 0 {
+		return false, nil
+	}
+	owners := []int64{0}
+	if ownerID != 0 && ownerID != memberID {
+		owners = append(owners, ownerID)
+	}
+	bs, err := uc.repo.ListMemberBlock(ctx, memberID, owners)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, b := range bs {
+		if b.Active(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
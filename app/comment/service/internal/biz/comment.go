@@ -0,0 +1,364 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// 评论状态
+const (
+	CommentStateNormal  int8 = 0 // 正常
+	CommentStateDeleted int8 = 1 // 作者删除
+	CommentStatePending int8 = 2 // 审核中, 对外隐藏
+	CommentStateRemoved int8 = 3 // 管理员删除
+)
+
+// 评论列表的排序
+const (
+	SortFloor int8 = 0 // 按楼层, 根评论从新到旧, 回复从旧到新
+	SortHot   int8 = 1 // 按热度从高到低, 热度为点赞数加回复数, 相同时按楼层从新到旧
+)
+
+// ListReplyPreview is the number of replies returned with each root comment.
+const ListReplyPreview = 3
+
+var (
+	// ErrSubjectNotFound is subject not found.
+	ErrSubjectNotFound = v1.ErrorSubjectNotFound("subject not found")
+	// ErrCommentNotFound is comment not found.
+	ErrCommentNotFound = v1.ErrorCommentNotFound("comment not found")
+	// ErrSubjectExisted is subject already created.
+	ErrSubjectExisted = v1.ErrorSubjectExisted("subject existed")
+	// ErrContentDuplicated is duplicate or flood comment.
+	ErrContentDuplicated = v1.ErrorContentDuplicated("duplicate comment")
+)
+
+// Subject is the object comments are attached to, eg. a video or an article.
+type Subject struct {
+	ID         int64
+	ObjID      int64
+	ObjType    int32
+	MemberID   int64 // 主题作者
+	Count      int32 // 根评论楼层计数
+	RootCount  int32 // 现存根评论数量
+	AllCount   int32 // 现存评论总数, 包含回复
+	State      int8
+	CreateTime time.Time
+}
+
+// Comment is a root comment or a reply.
+type Comment struct {
+	ID            int64
+	ObjID         int64
+	ObjType       int32
+	MemberID      int64
+	Root          int64 // 根评论ID, 0 为根评论
+	Parent        int64 // 回复的评论ID
+	ReplyMemberID int64 // 回复的人
+	Floor         int64
+	Count         int32 // 回复楼层计数
+	RootCount     int32 // 现存回复数量
+	Like          int32
+	Hate          int32
+	State         int8
+	CreateTime    time.Time
+	EditTime      time.Time // 最后编辑时间, 零值为未编辑
+
+	AtMemberIDs []int64
+	Mentions    []*Mention
+	Message     string
+	Meta        string // 旧版客户端的自由格式内容
+	Content     *v1.RichContent
+	Attachments []*Attachment
+	IP          int64
+	Platform    string
+	Device      string
+
+	Replies []*Comment
+}
+
+// SubjectRepo is subject storage.
+type SubjectRepo interface {
+	CreateSubject(ctx context.Context, s *Subject) error
+	GetSubject(ctx context.Context, objID int64, objType int32) (*Subject, error)
+	// ListSubject returns the subjects of the obj ids of all the obj types.
+	ListSubject(ctx context.Context, objIDs []int64) ([]*Subject, error)
+}
+
+// CommentRepo is comment index and content storage.
+type CommentRepo interface {
+	// CreateComment allocates the floor and stores the comment, the counts
+	// of subject and root are updated only for a normal comment, the
+	// attachments are bound to the comment and a comment created event is
+	// emitted to the comment job, a pending comment is put into the
+	// moderation queue as spam in the same transaction. ErrAttachmentUsed is returned when an
+	// attachment is bound to another comment meanwhile.
+	CreateComment(ctx context.Context, c *Comment) error
+	GetComment(ctx context.Context, id int64) (*Comment, error)
+	// ListCommentByID returns the comments of ids in any state with their
+	// attachments, unknown ids are left out.
+	ListCommentByID(ctx context.Context, ids []int64) ([]*Comment, error)
+	// DeleteComment marks a normal comment deleted and updates the counts.
+	DeleteComment(ctx context.Context, c *Comment) error
+	// ListComment returns normal root comments in the sort with their
+	// attachments, excluding the comments of the members in excludes.
+	ListComment(ctx context.Context, objID int64, objType int32, sort int8, excludes []int64, offset, limit int) ([]*Comment, error)
+	// ListReply returns normal replies of root in the sort with their
+	// attachments, excluding the replies of the members in excludes.
+	ListReply(ctx context.Context, root int64, sort int8, excludes []int64, offset, limit int) ([]*Comment, error)
+	// EditComment saves the current content of c as a version and replaces
+	// it with the content of c, a comment set pending is hidden and put
+	// into the moderation queue as spam in the same transaction.
+	EditComment(ctx context.Context, c *Comment) error
+	// ListCommentVersion returns the prior versions of the comment, the
+	// newest first.
+	ListCommentVersion(ctx context.Context, id int64) ([]*CommentVersion, error)
+	// ListMemberComment returns the comments of f.MemberID in states ordered
+	// by create time and id desc with their attachments.
+	ListMemberComment(ctx context.Context, f *MemberCommentFilter, states []int8) ([]*Comment, error)
+	// RankComment returns the number of normal root comments listed before
+	// the floor by ListComment, excluding the comments of the members in excludes.
+	RankComment(ctx context.Context, objID int64, objType int32, floor int64, excludes []int64) (int32, error)
+	// RankReply returns the number of normal replies of root listed before
+	// the floor by ListReply, excluding the replies of the members in excludes.
+	RankReply(ctx context.Context, root, floor int64, excludes []int64) (int32, error)
+	// CountComment counts the normal root comments of the members.
+	CountComment(ctx context.Context, objID int64, objType int32, memberIDs []int64) (int32, error)
+	// CountReply counts the normal replies of the members for each root.
+	CountReply(ctx context.Context, roots []int64, memberIDs []int64) (map[int64]int32, error)
+}
+
+// CommentUsecase is comment usecase.
+type CommentUsecase struct {
+	editWindow time.Duration
+	subject    SubjectRepo
+	comment    CommentRepo
+	member     MemberRepo
+	attachment *AttachmentUsecase
+	spam       *SpamUsecase
+	block      *BlockUsecase
+	mute       *MuteUsecase
+	log        *log.Helper
+}
+
+// NewCommentUsecase new a comment usecase.
+func NewCommentUsecase(c *conf.Comment, subject SubjectRepo, comment CommentRepo, member MemberRepo,
+	attachment *AttachmentUsecase, spam *SpamUsecase, block *BlockUsecase, mute *MuteUsecase, logger log.Logger) *CommentUsecase {
+	return &CommentUsecase{
+		editWindow: c.GetEdit().GetWindow().AsDuration(),
+		subject:    subject,
+		comment:    comment,
+		member:     member,
+		attachment: attachment,
+		spam:       spam,
+		block:      block,
+		mute:       mute,
+		log:        log.NewHelper(logger),
+	}
+}
+
+// CreateSubject creates a subject.
+func (uc *CommentUsecase) CreateSubject(ctx context.Context, s *Subject) error {
+	if _, err := uc.subject.GetSubject(ctx, s.ObjID, s.ObjType); err == nil {
+		return ErrSubjectExisted
+	} else if !v1.IsSubjectNotFound(err) {
+		return err
+	}
+	return uc.subject.CreateSubject(ctx, s)
+}
+
+// CreateComment creates a root comment, or a reply when c.Root is set.
+func (uc *CommentUsecase) CreateComment(ctx context.Context, c *Comment) error {
+	if c.Message == "" {
+		return v1.ErrorContentMissing("message is empty")
+	}
+	if err := validateContent(c.Content, c.Message); err != nil {
+		return err
+	}
+	as, err := uc.attachment.resolve(ctx, c.MemberID, c.Attachments)
+	if err != nil {
+		return err
+	}
+	c.Attachments = as
+	s, err := uc.subject.GetSubject(ctx, c.ObjID, c.ObjType)
+	if err != nil {
+		return err
+	}
+	if err = uc.block.Check(ctx, s.MemberID, c.MemberID); err != nil {
+		return err
+	}
+	if c.Root == 0 {
+		c.Parent = 0
+		return uc.create(ctx, c)
+	}
+
+	root, err := uc.comment.GetComment(ctx, c.Root)
+	if err != nil {
+		return err
+	}
+	if root.Root != 0 || root.ObjID != c.ObjID || root.ObjType != c.ObjType {
+		return v1.ErrorParentInvalid("root %d is not a root comment of the subject", c.Root)
+	}
+	if root.State != CommentStateNormal {
+		return ErrCommentNotFound
+	}
+	parent := root
+	if c.Parent != 0 && c.Parent != c.Root {
+		if parent, err = uc.comment.GetComment(ctx, c.Parent); err != nil {
+			return err
+		}
+		if parent.Root != c.Root {
+			return v1.ErrorParentInvalid("parent %d is not under root %d", c.Parent, c.Root)
+		}
+		if parent.State != CommentStateNormal {
+			return ErrCommentNotFound
+		}
+	}
+	c.Parent = parent.ID
+	c.ReplyMemberID = parent.MemberID
+	return uc.create(ctx, c)
+}
+
+// create filters a validated comment and stores it.
+func (uc *CommentUsecase) create(ctx context.Context, c *Comment) error {
+	quarantine, fp, err := uc.filter(ctx, c, "")
+	if err != nil {
+		return err
+	}
+	if quarantine {
+		c.State = CommentStatePending
+	}
+	if err = uc.comment.CreateComment(ctx, c); err != nil {
+		uc.spam.Forget(ctx, c, fp)
+		return err
+	}
+	return nil
+}
+
+// filter resolves the mentions of the message and checks it for
+// duplicates, a duplicate comment is rejected or to be quarantined in the
+// moderation queue. previous is the message replaced by an edit, the
+// returned fingerprint is to be forgotten when the comment is not stored.
+func (uc *CommentUsecase) filter(ctx context.Context, c *Comment, previous string) (quarantine bool, fp *Fingerprint, err error) {
+	if err = uc.mentions(ctx, c); err != nil {
+		return false, nil, err
+	}
+	action, fp, err := uc.spam.Check(ctx, c, previous)
+	if err != nil {
+		return false, nil, err
+	}
+	switch action {
+	case SpamActionReject:
+		return false, nil, ErrContentDuplicated
+	case SpamActionQuarantine:
+		return true, fp, nil
+	}
+	return false, fp, nil
+}
+
+// DeleteComment deletes a comment.
+func (uc *CommentUsecase) DeleteComment(ctx context.Context, id int64) error {
+	c, err := uc.comment.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.State != CommentStateNormal {
+		return nil
+	}
+	return uc.comment.DeleteComment(ctx, c)
+}
+
+// ListComment lists root comments of a subject in the sort with a preview of
+// their replies in the same sort, the comments of the members muted by
+// viewer are left out of the list and the counts.
+func (uc *CommentUsecase) ListComment(ctx context.Context, viewer, objID int64, objType int32, sort int8, offset, limit int) ([]*Comment, int32, error) {
+	if err := checkSort(sort); err != nil {
+		return nil, 0, err
+	}
+	s, err := uc.subject.GetSubject(ctx, objID, objType)
+	if err != nil {
+		return nil, 0, err
+	}
+	muted, err := uc.mute.MutedID(ctx, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	cs, err := uc.comment.ListComment(ctx, objID, objType, sort, muted, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := s.RootCount
+	if len(muted) > 0 {
+		n, err := uc.comment.CountComment(ctx, objID, objType, muted)
+		if err != nil {
+			return nil, 0, err
+		}
+		total -= n
+		roots := make([]int64, 0, len(cs))
+		for _, c := range cs {
+			roots = append(roots, c.ID)
+		}
+		counts, err := uc.comment.CountReply(ctx, roots, muted)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, c := range cs {
+			c.RootCount -= counts[c.ID]
+		}
+	}
+	for _, c := range cs {
+		if c.RootCount <= 0 {
+			continue
+		}
+		if c.Replies, err = uc.comment.ListReply(ctx, c.ID, sort, muted, 0, ListReplyPreview); err != nil {
+			return nil, 0, err
+		}
+	}
+	return cs, total, nil
+}
+
+// ListReply lists replies of a root comment in the sort, the replies of the
+// members muted by viewer are left out of the list and the count.
+func (uc *CommentUsecase) ListReply(ctx context.Context, viewer, id int64, sort int8, offset, limit int) ([]*Comment, int32, error) {
+	if err := checkSort(sort); err != nil {
+		return nil, 0, err
+	}
+	root, err := uc.comment.GetComment(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if root.Root != 0 {
+		return nil, 0, v1.ErrorParentInvalid("comment %d is not a root comment", id)
+	}
+	if root.State != CommentStateNormal {
+		return nil, 0, ErrCommentNotFound
+	}
+	muted, err := uc.mute.MutedID(ctx, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	cs, err := uc.comment.ListReply(ctx, id, sort, muted, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := root.RootCount
+	if len(muted) > 0 {
+		counts, err := uc.comment.CountReply(ctx, []int64{id}, muted)
+		if err != nil {
+			return nil, 0, err
+		}
+		total -= counts[id]
+	}
+	return cs, total, nil
+}
+
+func checkSort(sort int8) error {
+	if sort != SortFloor && sort != SortHot {
+		return v1.ErrorArgumentInvalid("unknown sort %d", sort)
+	}
+	return nil
+}
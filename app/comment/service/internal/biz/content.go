@@ -0,0 +1,103 @@
+package biz
+
+import (
+	"net/url"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// RichContentVersion is the current version of v1.RichContent.
+const RichContentVersion = 1
+
+// 富文本内容的数量限制
+const (
+	maxEmojis      = 100
+	maxStickers    = 1
+	maxImages      = 9
+	maxTimestamps  = 20
+	maxLinks       = 3
+	maxURLLength   = 1024
+	maxTitleLength = 128
+	maxStyleLength = 32
+)
+
+var colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateContent checks the rich content against the message and sets
+// it to the current version, a nil content is valid.
+func validateContent(ct *v1.RichContent, message string) error {
+	if ct == nil {
+		return nil
+	}
+	if ct.Version > RichContentVersion || ct.Version < 0 {
+		return v1.ErrorContentInvalid("unsupported content version %d", ct.Version)
+	}
+	ct.Version = RichContentVersion
+	if len(ct.Emojis) > maxEmojis || len(ct.Stickers) > maxStickers || len(ct.Images) > maxImages ||
+		len(ct.Timestamps) > maxTimestamps || len(ct.Links) > maxLinks {
+		return v1.ErrorContentInvalid("too many content items")
+	}
+
+	rs := []rune(message)
+	span := func(offset, length int32) bool {
+		return offset >= 0 && length > 0 && int(offset)+int(length) <= len(rs)
+	}
+	for _, e := range ct.Emojis {
+		if !span(e.Offset, e.Length) || string(rs[e.Offset:e.Offset+e.Length]) != e.Code {
+			return v1.ErrorContentInvalid("emoji %q does not match message", e.Code)
+		}
+	}
+	for _, t := range ct.Timestamps {
+		if t.Seconds < 0 || !span(t.Offset, t.Length) {
+			return v1.ErrorContentInvalid("timestamp %d does not match message", t.Seconds)
+		}
+	}
+	for _, s := range ct.Stickers {
+		if s.Id <= 0 {
+			return v1.ErrorContentInvalid("sticker id is missing")
+		}
+	}
+	for _, i := range ct.Images {
+		if !validURL(i.Url) || i.Width < 0 || i.Height < 0 {
+			return v1.ErrorContentInvalid("image %q is invalid", i.Url)
+		}
+	}
+	for _, l := range ct.Links {
+		if !validURL(l.Url) || (l.ImageUrl != "" && !validURL(l.ImageUrl)) ||
+			!validText(l.Title, maxTitleLength) || !validText(l.Description, maxTitleLength) {
+			return v1.ErrorContentInvalid("link %q is invalid", l.Url)
+		}
+	}
+	if b := ct.Background; b != nil {
+		if !validText(b.Style, maxStyleLength) || (b.Color != "" && !colorRe.MatchString(b.Color)) {
+			return v1.ErrorContentInvalid("background is invalid")
+		}
+	}
+	return nil
+}
+
+// validURL reports whether s is an absolute http or https url.
+func validURL(s string) bool {
+	if s == "" || len(s) > maxURLLength {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validText reports whether s is valid utf-8 of at most max characters
+// without control characters.
+func validText(s string, max int) bool {
+	if !utf8.ValidString(s) || utf8.RuneCountInString(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
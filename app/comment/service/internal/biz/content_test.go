@@ -0,0 +1,74 @@
+package biz
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+func TestValidateContent(t *testing.T) {
+	const msg = "看 02:31 [doge]"
+	var (
+		longURL = "https://example.com/" + strings.Repeat("a", maxURLLength-len("https://example.com/"))
+		link    = func(l *v1.LinkCard) *v1.RichContent { return &v1.RichContent{Links: []*v1.LinkCard{l}} }
+		bg      = func(b *v1.Background) *v1.RichContent { return &v1.RichContent{Background: b} }
+	)
+	for _, tc := range []struct {
+		name string
+		ct   *v1.RichContent
+		ok   bool
+	}{
+		{"nil", nil, true},
+		{"legacy version", &v1.RichContent{}, true},
+		{"current version", &v1.RichContent{Version: RichContentVersion}, true},
+		{"future version", &v1.RichContent{Version: RichContentVersion + 1}, false},
+		{"negative version", &v1.RichContent{Version: -1}, false},
+
+		{"emoji", &v1.RichContent{Emojis: []*v1.Emoji{{Code: "[doge]", Offset: 8, Length: 6}}}, true},
+		{"emoji past the end", &v1.RichContent{Emojis: []*v1.Emoji{{Code: "[doge]", Offset: 9, Length: 6}}}, false},
+		{"emoji of other text", &v1.RichContent{Emojis: []*v1.Emoji{{Code: "[cat]", Offset: 8, Length: 6}}}, false},
+		{"emoji of no text", &v1.RichContent{Emojis: []*v1.Emoji{{Offset: 8}}}, false},
+		{"timestamp", &v1.RichContent{Timestamps: []*v1.VideoTimestamp{{Seconds: 151, Offset: 2, Length: 5}}}, true},
+		{"negative timestamp", &v1.RichContent{Timestamps: []*v1.VideoTimestamp{{Seconds: -1, Offset: 2, Length: 5}}}, false},
+		{"negative offset", &v1.RichContent{Timestamps: []*v1.VideoTimestamp{{Seconds: 1, Offset: -1, Length: 5}}}, false},
+
+		{"sticker", &v1.RichContent{Stickers: []*v1.Sticker{{Id: 1}}}, true},
+		{"sticker without id", &v1.RichContent{Stickers: []*v1.Sticker{{}}}, false},
+		{"too many stickers", &v1.RichContent{Stickers: make([]*v1.Sticker, maxStickers+1)}, false},
+		{"too many images", &v1.RichContent{Images: make([]*v1.Image, maxImages+1)}, false},
+		{"too many links", &v1.RichContent{Links: make([]*v1.LinkCard, maxLinks+1)}, false},
+		{"image", &v1.RichContent{Images: []*v1.Image{{Url: "https://example.com/a.png", Width: 1, Height: 1}}}, true},
+		{"image of other scheme", &v1.RichContent{Images: []*v1.Image{{Url: "javascript:alert(1)"}}}, false},
+		{"image of negative width", &v1.RichContent{Images: []*v1.Image{{Url: "https://example.com/a.png", Width: -1}}}, false},
+
+		{"url at max length", link(&v1.LinkCard{Url: longURL}), true},
+		{"url over max length", link(&v1.LinkCard{Url: longURL + "a"}), false},
+		{"relative url", link(&v1.LinkCard{Url: "/a"}), false},
+		{"invalid image url", link(&v1.LinkCard{Url: "https://example.com", ImageUrl: "ftp://example.com/a.png"}), false},
+		{"title at max length", link(&v1.LinkCard{Url: "https://example.com", Title: strings.Repeat("标", maxTitleLength)}), true},
+		{"title over max length", link(&v1.LinkCard{Url: "https://example.com", Title: strings.Repeat("标", maxTitleLength+1)}), false},
+		{"description over max length", link(&v1.LinkCard{Url: "https://example.com",
+			Description: strings.Repeat("a", maxTitleLength+1)}), false},
+		{"title with newline", link(&v1.LinkCard{Url: "https://example.com", Title: "a\nb"}), false},
+		{"title with nul", link(&v1.LinkCard{Url: "https://example.com", Title: "a\x00b"}), false},
+		{"description with c1 control", link(&v1.LinkCard{Url: "https://example.com", Description: "a\u0085b"}), false},
+		{"title of invalid utf-8", link(&v1.LinkCard{Url: "https://example.com", Title: "a\xffb"}), false},
+
+		{"background", bg(&v1.Background{Style: strings.Repeat("s", maxStyleLength), Color: "#00ffAA"}), true},
+		{"style over max length", bg(&v1.Background{Style: strings.Repeat("s", maxStyleLength+1)}), false},
+		{"style with tab", bg(&v1.Background{Style: "a\tb"}), false},
+		{"short color", bg(&v1.Background{Color: "#fff"}), false},
+		{"named color", bg(&v1.Background{Color: "red"}), false},
+	} {
+		err := validateContent(tc.ct, msg)
+		switch {
+		case tc.ok && err != nil:
+			t.Errorf("%s: got error %v", tc.name, err)
+		case !tc.ok && !v1.IsContentInvalid(err):
+			t.Errorf("%s: got error %v, want content invalid", tc.name, err)
+		case tc.ok && tc.ct != nil && tc.ct.Version != RichContentVersion:
+			t.Errorf("%s: got version %d", tc.name, tc.ct.Version)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package biz
+
+import (
+	"context"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// MaxBatchGet is the maximum number of comments got in a batch.
+const MaxBatchGet = 100
+
+// CommentDetail is a comment with its subject and thread context.
+type CommentDetail struct {
+	Comment       *Comment
+	Subject       *Subject
+	RootComment   *Comment // 回复所在的根评论
+	ParentComment *Comment // 回复的评论, 回复的是根评论时为空
+}
+
+// GetComment returns a comment in any state with its subject and thread,
+// only the state of a comment which is not normal.
+func (uc *CommentUsecase) GetComment(ctx context.Context, id int64) (*CommentDetail, error) {
+	ds, err := uc.BatchGetComments(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	d, ok := ds[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+	return d, nil
+}
+
+// BatchGetComments returns the comments of ids in any state with their
+// subjects and threads, unknown ids are left out. The content of a comment
+// which is not normal is dropped, the admin paths read it from the repo.
+func (uc *CommentUsecase) BatchGetComments(ctx context.Context, ids []int64) (map[int64]*CommentDetail, error) {
+	if len(ids) > MaxBatchGet {
+		return nil, v1.ErrorTooManyIds("get at most %d comments", MaxBatchGet)
+	}
+	cs, err := uc.comment.ListCommentByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*Comment, len(cs))
+	for _, c := range cs {
+		byID[c.ID] = c
+	}
+
+	var (
+		threadIDs []int64
+		objIDs    []int64
+		seen      = make(map[int64]bool)
+		seenObj   = make(map[int64]bool)
+	)
+	for _, c := range cs {
+		for _, id := range []int64{c.Root, c.Parent} {
+			if _, ok := byID[id]; id != 0 && !ok && !seen[id] {
+				seen[id] = true
+				threadIDs = append(threadIDs, id)
+			}
+		}
+		if !seenObj[c.ObjID] {
+			seenObj[c.ObjID] = true
+			objIDs = append(objIDs, c.ObjID)
+		}
+	}
+	threads, err := uc.comment.ListCommentByID(ctx, threadIDs)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[int64]*Comment, len(cs)+len(threads))
+	for _, c := range append(cs, threads...) {
+		all[c.ID] = c
+	}
+	ss, err := uc.subject.ListSubject(ctx, objIDs)
+	if err != nil {
+		return nil, err
+	}
+	type obj struct {
+		id  int64
+		typ int32
+	}
+	subjects := make(map[obj]*Subject, len(ss))
+	for _, s := range ss {
+		subjects[obj{s.ObjID, s.ObjType}] = s
+	}
+
+	for _, c := range all {
+		redact(c)
+	}
+	ds := make(map[int64]*CommentDetail, len(cs))
+	for _, c := range cs {
+		d := &CommentDetail{
+			Comment: c,
+			Subject: subjects[obj{c.ObjID, c.ObjType}],
+		}
+		if c.Root != 0 {
+			d.RootComment = all[c.Root]
+			if c.Parent != c.Root {
+				d.ParentComment = all[c.Parent]
+			}
+		}
+		ds[c.ID] = d
+	}
+	return ds, nil
+}
+
+// redact drops the content and the client of a comment which is not normal.
+func redact(c *Comment) {
+	if c.State == CommentStateNormal {
+		return
+	}
+	c.AtMemberIDs, c.Mentions, c.Message, c.Meta, c.Content, c.Attachments = nil, nil, "", "", nil, nil
+	c.IP, c.Platform, c.Device = 0, "", ""
+}
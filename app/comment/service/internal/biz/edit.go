@@ -0,0 +1,77 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// CommentVersion is the content of a comment before an edit.
+type CommentVersion struct {
+	CommentID   int64
+	AtMemberIDs []int64
+	Mentions    []*Mention
+	Message     string
+	Meta        string
+	Content     *v1.RichContent
+	CreateTime  time.Time // 该版本的发布时间
+}
+
+// EditComment replaces the content of a normal comment by its author
+// within the edit window, the author must not be blocked and the new
+// content goes through the same filtering as a new comment, compared with
+// the other messages of the author. The attachments are kept.
+func (uc *CommentUsecase) EditComment(ctx context.Context, e *Comment) error {
+	if e.Message == "" {
+		return v1.ErrorContentMissing("message is empty")
+	}
+	if err := validateContent(e.Content, e.Message); err != nil {
+		return err
+	}
+	c, err := uc.comment.GetComment(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	if c.State != CommentStateNormal {
+		return ErrCommentNotFound
+	}
+	if c.MemberID != e.MemberID {
+		return v1.ErrorEditForbidden("only the author can edit the comment")
+	}
+	if uc.editWindow <= 0 || time.Since(c.CreateTime) > uc.editWindow {
+		return v1.ErrorEditForbidden("the comment can not be edited any more")
+	}
+
+	s, err := uc.subject.GetSubject(ctx, c.ObjID, c.ObjType)
+	if err != nil {
+		return err
+	}
+	if err = uc.block.Check(ctx, s.MemberID, c.MemberID); err != nil {
+		return err
+	}
+
+	previous := c.Message
+	c.Message, c.Meta, c.Content = e.Message, e.Meta, e.Content
+	c.AtMemberIDs, c.Mentions = nil, nil
+	quarantine, fp, err := uc.filter(ctx, c, previous)
+	if err != nil {
+		return err
+	}
+	if quarantine {
+		c.State = CommentStatePending
+	}
+	if err = uc.comment.EditComment(ctx, c); err != nil {
+		uc.spam.Forget(ctx, c, fp)
+		return err
+	}
+	return nil
+}
+
+// GetCommentHistory returns the prior versions of a comment for moderators.
+func (uc *CommentUsecase) GetCommentHistory(ctx context.Context, id int64) ([]*CommentVersion, error) {
+	if _, err := uc.comment.GetComment(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.comment.ListCommentVersion(ctx, id)
+}
@@ -0,0 +1,68 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// 抹除任务状态
+const (
+	ErasureStatePending int8 = 0 // 等待 comment job 执行
+	ErasureStateRunning int8 = 1 // 执行中
+	ErasureStateDone    int8 = 2 // 完成
+)
+
+// ErrErasureNotFound is erasure task not found.
+var ErrErasureNotFound = v1.ErrorErasureNotFound("erasure not found")
+
+// Erasure is a task erasing the content and identity of a member, it is
+// carried out by the comment job.
+type Erasure struct {
+	ID         int64
+	MemberID   int64
+	OperatorID int64
+	Reason     string
+	State      int8
+	Erased     int32 // 已抹除的评论数
+	CreateTime time.Time
+	FinishTime time.Time
+}
+
+// ErasureRepo is erasure task storage.
+type ErasureRepo interface {
+	// CreateErasure stores the task with an audit entry and emits a member
+	// erasure requested event to the comment job.
+	CreateErasure(ctx context.Context, e *Erasure) error
+	GetErasure(ctx context.Context, id int64) (*Erasure, error)
+}
+
+// ErasureUsecase is erasure usecase.
+type ErasureUsecase struct {
+	repo ErasureRepo
+	log  *log.Helper
+}
+
+// NewErasureUsecase new an erasure usecase.
+func NewErasureUsecase(repo ErasureRepo, logger log.Logger) *ErasureUsecase {
+	return &ErasureUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// Erase requests the erasure of a member.
+func (uc *ErasureUsecase) Erase(ctx context.Context, e *Erasure) error {
+	if e.MemberID <= 0 {
+		return v1.ErrorArgumentInvalid("member is missing")
+	}
+	if e.OperatorID == 0 {
+		return v1.ErrorArgumentInvalid("operator is missing")
+	}
+	e.State = ErasureStatePending
+	return uc.repo.CreateErasure(ctx, e)
+}
+
+// GetErasure returns the progress of an erasure task.
+func (uc *ErasureUsecase) GetErasure(ctx context.Context, id int64) (*Erasure, error) {
+	return uc.repo.GetErasure(ctx, id)
+}
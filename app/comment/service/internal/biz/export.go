@@ -0,0 +1,105 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// 导出记录的类型
+const (
+	ExportTypeComment = "comment"
+	ExportTypeLike    = "like"
+	ExportTypeReport  = "report"
+)
+
+const exportBatch = 100
+
+// ExportRecord is a piece of data written by a member, one of Comment,
+// Like and Report is set by Type.
+type ExportRecord struct {
+	Type    string
+	Comment *Comment
+	Like    *Like
+	Report  *Report
+}
+
+// ExportUsecase exports member data.
+type ExportUsecase struct {
+	comment CommentRepo
+	like    LikeRepo
+	report  ReportRepo
+	audit   AuditRepo
+	log     *log.Helper
+}
+
+// NewExportUsecase new an export usecase.
+func NewExportUsecase(comment CommentRepo, like LikeRepo, report ReportRepo, audit AuditRepo, logger log.Logger) *ExportUsecase {
+	return &ExportUsecase{
+		comment: comment,
+		like:    like,
+		report:  report,
+		audit:   audit,
+		log:     log.NewHelper(logger),
+	}
+}
+
+// Export passes all the comments in any state, likes and reports of the
+// member to fn in batches, the export is recorded in the audit trail.
+func (uc *ExportUsecase) Export(ctx context.Context, operatorID, memberID int64, fn func(*ExportRecord) error) error {
+	err := uc.audit.AddAudit(ctx, &Audit{Action: AuditActionExport, OperatorID: operatorID, MemberID: memberID})
+	if err != nil {
+		return err
+	}
+
+	f := &MemberCommentFilter{MemberID: memberID, Limit: exportBatch}
+	states := []int8{CommentStateNormal, CommentStateDeleted, CommentStatePending, CommentStateRemoved}
+	for {
+		cs, err := uc.comment.ListMemberComment(ctx, f, states)
+		if err != nil {
+			return err
+		}
+		for _, c := range cs {
+			if err = fn(&ExportRecord{Type: ExportTypeComment, Comment: c}); err != nil {
+				return err
+			}
+		}
+		if len(cs) < exportBatch {
+			break
+		}
+		f.Cursor = cs[len(cs)-1].ID
+	}
+
+	for cursor := int64(0); ; {
+		ls, err := uc.like.ListMemberLike(ctx, memberID, cursor, exportBatch)
+		if err != nil {
+			return err
+		}
+		for _, l := range ls {
+			if err = fn(&ExportRecord{Type: ExportTypeLike, Like: l}); err != nil {
+				return err
+			}
+		}
+		if len(ls) < exportBatch {
+			break
+		}
+		cursor = ls[len(ls)-1].ID
+	}
+
+	for cursor := int64(0); ; {
+		rs, err := uc.report.ListMemberReport(ctx, memberID, cursor, exportBatch)
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			if err = fn(&ExportRecord{Type: ExportTypeReport, Report: r}); err != nil {
+				return err
+			}
+		}
+		if len(rs) < exportBatch {
+			break
+		}
+		cursor = rs[len(rs)-1].ID
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// 点赞操作
+const (
+	LikeActionCancel int8 = 0 // 取消
+	LikeActionLike   int8 = 1 // 点赞
+	LikeActionHate   int8 = 2 // 点踩
+)
+
+// Like is a member liking or hating a comment.
+type Like struct {
+	ID         int64
+	CommentID  int64
+	MemberID   int64
+	Action     int8
+	CreateTime time.Time
+}
+
+// LikeRepo is like storage.
+type LikeRepo interface {
+	// SaveLike sets the action of the member on the comment and updates the
+	// like and hate counts of the comment, LikeActionCancel removes it.
+	SaveLike(ctx context.Context, l *Like) error
+	// ListMemberLike returns the likes of the member after the cursor id
+	// ordered by id asc.
+	ListMemberLike(ctx context.Context, memberID, cursor int64, limit int) ([]*Like, error)
+}
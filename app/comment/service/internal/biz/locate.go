@@ -0,0 +1,45 @@
+package biz
+
+import "context"
+
+// CommentLocation is the position of a comment in the lists.
+type CommentLocation struct {
+	RootID    int64
+	RootRank  int32 // 根评论在 ListComment 中的位置, 从 0 开始
+	ReplyRank int32 // 回复在 ListReply 中的位置, 根评论为 -1
+}
+
+// LocateComment returns where a normal comment is listed by ListComment
+// and ListReply for the viewer.
+func (uc *CommentUsecase) LocateComment(ctx context.Context, viewer, id int64) (*CommentLocation, error) {
+	c, err := uc.comment.GetComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.State != CommentStateNormal {
+		return nil, ErrCommentNotFound
+	}
+	root := c
+	if c.Root != 0 {
+		if root, err = uc.comment.GetComment(ctx, c.Root); err != nil {
+			return nil, err
+		}
+		if root.State != CommentStateNormal {
+			return nil, ErrCommentNotFound
+		}
+	}
+	muted, err := uc.mute.MutedID(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	loc := &CommentLocation{RootID: root.ID, ReplyRank: -1}
+	if loc.RootRank, err = uc.comment.RankComment(ctx, root.ObjID, root.ObjType, root.Floor, muted); err != nil {
+		return nil, err
+	}
+	if c.Root != 0 {
+		if loc.ReplyRank, err = uc.comment.RankReply(ctx, root.ID, c.Floor, muted); err != nil {
+			return nil, err
+		}
+	}
+	return loc, nil
+}
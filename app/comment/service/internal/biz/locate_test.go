@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// rankRepo ranks a comment by its floor, less the muted floors before it.
+type rankRepo struct {
+	CommentRepo
+	comments map[int64]*Comment
+}
+
+func (r *rankRepo) GetComment(_ context.Context, id int64) (*Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+	return c, nil
+}
+
+func (r *rankRepo) RankComment(_ context.Context, _ int64, _ int32, floor int64, excludes []int64) (int32, error) {
+	return int32(10-floor) - int32(len(excludes)), nil
+}
+
+func (r *rankRepo) RankReply(_ context.Context, _, floor int64, excludes []int64) (int32, error) {
+	return int32(floor-1) - int32(len(excludes)), nil
+}
+
+func TestLocateComment(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		repo = &rankRepo{comments: map[int64]*Comment{
+			1: {ID: 1, Floor: 4},
+			2: {ID: 2, Root: 1, Floor: 3},
+			3: {ID: 3, Root: 1, Floor: 4, State: CommentStatePending},
+			4: {ID: 4, Root: 1, Floor: 5, State: CommentStateDeleted},
+			5: {ID: 5, Floor: 5, State: CommentStateRemoved},
+			6: {ID: 6, Root: 5, Floor: 1},
+		}}
+		uc = &CommentUsecase{
+			comment: repo,
+			mute:    NewMuteUsecase(&muteRepo{ids: []int64{20}}, log.DefaultLogger),
+			log:     log.NewHelper(log.DefaultLogger),
+		}
+	)
+	for _, tc := range []struct {
+		name   string
+		viewer int64
+		id     int64
+		want   *CommentLocation
+	}{
+		{"root", 0, 1, &CommentLocation{RootID: 1, RootRank: 6, ReplyRank: -1}},
+		{"root muting", 1, 1, &CommentLocation{RootID: 1, RootRank: 5, ReplyRank: -1}},
+		{"reply", 0, 2, &CommentLocation{RootID: 1, RootRank: 6, ReplyRank: 2}},
+		{"reply muting", 1, 2, &CommentLocation{RootID: 1, RootRank: 5, ReplyRank: 1}},
+		{"pending reply", 0, 3, nil},
+		{"deleted reply", 0, 4, nil},
+		{"removed root", 0, 5, nil},
+		{"reply of removed root", 0, 6, nil},
+		{"unknown", 0, 7, nil},
+	} {
+		loc, err := uc.LocateComment(ctx, tc.viewer, tc.id)
+		if tc.want == nil {
+			if !v1.IsCommentNotFound(err) {
+				t.Errorf("%s: got location %+v, error %v", tc.name, loc, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: got error %v", tc.name, err)
+		}
+		if *loc != *tc.want {
+			t.Errorf("%s: got location %+v, want %+v", tc.name, loc, tc.want)
+		}
+	}
+}
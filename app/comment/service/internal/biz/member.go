@@ -0,0 +1,38 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// MemberCommentFilter filters the comments of a member.
+type MemberCommentFilter struct {
+	MemberID  int64
+	ObjType   int32 // 0 为全部
+	StartTime time.Time
+	EndTime   time.Time
+	Cursor    int64 // 上一页最后一条评论ID, 之后的评论按发布时间和ID倒序, 0 为第一页
+	Limit     int
+}
+
+// ListMemberComment lists the comments of a member for the viewer, newest
+// first. The author also sees the comments pending moderation and removed
+// by moderators, others see only the normal ones. It returns the cursor of
+// the next page, 0 when there is no more.
+func (uc *CommentUsecase) ListMemberComment(ctx context.Context, viewer int64, f *MemberCommentFilter) ([]*Comment, int64, error) {
+	states := []int8{CommentStateNormal}
+	if viewer != 0 && viewer == f.MemberID {
+		states = append(states, CommentStatePending, CommentStateRemoved)
+	}
+	limit := f.Limit
+	f.Limit++
+	cs, err := uc.comment.ListMemberComment(ctx, f, states)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(cs) <= limit {
+		return cs, 0, nil
+	}
+	cs = cs[:limit]
+	return cs, cs[limit-1].ID, nil
+}
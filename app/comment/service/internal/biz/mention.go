@@ -0,0 +1,108 @@
+package biz
+
+import (
+	"context"
+	"unicode"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// MaxMentions is the maximum number of distinct names resolved in a message.
+const MaxMentions = 20
+
+// Mention is a member mentioned by @name in the message.
+type Mention struct {
+	MemberID int64
+	Offset   int32 // @ 在 message 中的位置, 按 unicode 字符计
+	Length   int32 // 包含 @ 的字符数
+}
+
+// MemberRepo resolves member names.
+type MemberRepo interface {
+	// ListMemberID returns the ids of the names, unknown names are left out.
+	ListMemberID(ctx context.Context, names []string) (map[string]int64, error)
+}
+
+type mentionToken struct {
+	name   string
+	offset int32
+	length int32
+}
+
+// parseMentions returns the @name tokens in the message. A name is a run
+// of letters, digits, '_' and '-', the @ must not follow an ascii one of
+// them so an email address is not a mention while "你好@张三" is.
+func parseMentions(msg string) []mentionToken {
+	var (
+		ts []mentionToken
+		rs = []rune(msg)
+	)
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '@' || (i > 0 && rs[i-1] <= unicode.MaxASCII && isNameRune(rs[i-1])) {
+			continue
+		}
+		j := i + 1
+		for j < len(rs) && isNameRune(rs[j]) {
+			j++
+		}
+		if j > i+1 {
+			ts = append(ts, mentionToken{name: string(rs[i+1 : j]), offset: int32(i), length: int32(j - i)})
+			i = j - 1
+		}
+	}
+	return ts
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// mentions resolves the @name tokens of c.Message into c.Mentions and
+// sets c.AtMemberIDs to the members mentioned, in message order. Names
+// which are not members are left as text. An id in c.AtMemberIDs not
+// resolved from a name in the message is rejected, and the comment is
+// rejected when the names cannot be resolved.
+func (uc *CommentUsecase) mentions(ctx context.Context, c *Comment) error {
+	ts := parseMentions(c.Message)
+	var (
+		names = make([]string, 0, len(ts))
+		seen  = make(map[string]bool, len(ts))
+	)
+	for _, t := range ts {
+		if !seen[t.name] && len(names) < MaxMentions {
+			seen[t.name] = true
+			names = append(names, t.name)
+		}
+	}
+	ids := map[string]int64{}
+	if len(names) > 0 {
+		var err error
+		if ids, err = uc.member.ListMemberID(ctx, names); err != nil {
+			return err
+		}
+	}
+
+	var (
+		ats       = make([]int64, 0, len(ids))
+		mentioned = make(map[int64]bool, len(ids))
+	)
+	c.Mentions = c.Mentions[:0]
+	for _, t := range ts {
+		id, ok := ids[t.name]
+		if !ok {
+			continue
+		}
+		c.Mentions = append(c.Mentions, &Mention{MemberID: id, Offset: t.offset, Length: t.length})
+		if !mentioned[id] {
+			mentioned[id] = true
+			ats = append(ats, id)
+		}
+	}
+	for _, id := range c.AtMemberIDs {
+		if !mentioned[id] {
+			return v1.ErrorMentionInvalid("member %d is not mentioned in message", id)
+		}
+	}
+	c.AtMemberIDs = ats
+	return nil
+}
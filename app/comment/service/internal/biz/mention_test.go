@@ -0,0 +1,104 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// memberRepo resolves the names in its map, or fails with err.
+type memberRepo struct {
+	ids map[string]int64
+	err error
+}
+
+func (r *memberRepo) ListMemberID(_ context.Context, names []string) (map[string]int64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	ids := make(map[string]int64)
+	for _, name := range names {
+		if id, ok := r.ids[name]; ok {
+			ids[name] = id
+		}
+	}
+	return ids, nil
+}
+
+func TestParseMentions(t *testing.T) {
+	for _, tc := range []struct {
+		msg  string
+		want []mentionToken
+	}{
+		{"", nil},
+		{"no mention", nil},
+		{"@alice", []mentionToken{{"alice", 0, 6}}},
+		{"hi @alice, @bob_2!", []mentionToken{{"alice", 3, 6}, {"bob_2", 11, 6}}},
+		{"你好@张三 再见", []mentionToken{{"张三", 2, 3}}},
+		{"mail a@example.com", nil},
+		{"@ alone and @@x-y", []mentionToken{{"x-y", 13, 4}}},
+		{"@a@b", []mentionToken{{"a", 0, 2}}},
+	} {
+		if got := parseMentions(tc.msg); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseMentions(%q) = %v, want %v", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	members := &memberRepo{ids: map[string]int64{"alice": 1, "bob": 2}}
+	for _, tc := range []struct {
+		name     string
+		msg      string
+		ats      []int64
+		members  *memberRepo
+		want     []int64
+		mentions []*Mention
+		invalid  bool
+		err      bool
+	}{
+		{name: "resolved", msg: "@bob @alice @bob", members: members, want: []int64{2, 1},
+			mentions: []*Mention{{2, 0, 4}, {1, 5, 6}, {2, 12, 4}}},
+		{name: "client ids resolved", msg: "@alice", ats: []int64{1}, members: members, want: []int64{1},
+			mentions: []*Mention{{1, 0, 6}}},
+		{name: "unresolved name left as text", msg: "@alice @nobody", members: members, want: []int64{1},
+			mentions: []*Mention{{1, 0, 6}}},
+		{name: "client id with an unresolved name", msg: "@alice @nobody", ats: []int64{1, 99}, members: members,
+			invalid: true},
+		{name: "client id not in message", msg: "hello", ats: []int64{2}, members: members, invalid: true},
+		{name: "member service down", msg: "@alice", members: &memberRepo{err: errors.New("down")}, err: true},
+		{name: "no names skips the lookup", msg: "hello", members: &memberRepo{err: errors.New("down")}, want: []int64{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				uc = &CommentUsecase{member: tc.members, log: log.NewHelper(log.DefaultLogger)}
+				c  = &Comment{Message: tc.msg, AtMemberIDs: tc.ats}
+			)
+			err := uc.mentions(context.Background(), c)
+			switch {
+			case tc.invalid:
+				if !v1.IsMentionInvalid(err) {
+					t.Fatalf("got error %v, want mention invalid", err)
+				}
+				return
+			case tc.err:
+				if err == nil {
+					t.Fatal("resolved mentions without the member service")
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.AtMemberIDs, tc.want) {
+				t.Fatalf("got at members %v, want %v", c.AtMemberIDs, tc.want)
+			}
+			if len(tc.mentions) > 0 && !reflect.DeepEqual(c.Mentions, tc.mentions) {
+				t.Fatalf("got mentions %v, want %v", c.Mentions, tc.mentions)
+			}
+		})
+	}
+}
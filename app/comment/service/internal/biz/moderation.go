@@ -0,0 +1,36 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// 进入审核队列的来源
+const (
+	ModerationSourceReport int8 = 1 // 用户举报
+	ModerationSourceSpam   int8 = 2 // 重复或刷屏评论
+)
+
+// 审核状态
+const (
+	ModerationStatePending  int8 = 0 // 待审核
+	ModerationStateApproved int8 = 1 // 审核通过, 恢复展示
+	ModerationStateRemoved  int8 = 2 // 审核不通过, 删除
+)
+
+// Moderation is a comment waiting for review.
+type Moderation struct {
+	CommentID  int64
+	ObjID      int64
+	ObjType    int32
+	Source     int8
+	State      int8
+	CreateTime time.Time
+}
+
+// ModerationRepo is moderation queue storage.
+type ModerationRepo interface {
+	// EnqueueModeration puts the comment into the moderation queue,
+	// a normal comment is hidden and the counts are updated first.
+	EnqueueModeration(ctx context.Context, c *Comment, source int8) error
+}
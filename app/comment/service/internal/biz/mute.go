@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// MaxMuted is the maximum number of members a viewer can mute.
+const MaxMuted = 200
+
+// Mute is a viewer muting comments from a member.
+type Mute struct {
+	ID           int64
+	MemberID     int64 // 查看者
+	MuteMemberID int64 // 被屏蔽的人
+	CreateTime   time.Time
+}
+
+// MuteRepo is mute storage.
+type MuteRepo interface {
+	// CreateMute creates the mute, it does nothing when the mute exists.
+	CreateMute(ctx context.Context, m *Mute) error
+	DeleteMute(ctx context.Context, memberID, muteMemberID int64) error
+	// ListMute returns the mutes of member ordered by create time desc.
+	ListMute(ctx context.Context, memberID int64, offset, limit int) ([]*Mute, int32, error)
+	// ListMutedID returns all the members muted by member.
+	ListMutedID(ctx context.Context, memberID int64) ([]int64, error)
+}
+
+// MuteUsecase is mute usecase.
+type MuteUsecase struct {
+	repo MuteRepo
+	log  *log.Helper
+}
+
+// NewMuteUsecase new a mute usecase.
+func NewMuteUsecase(repo MuteRepo, logger log.Logger) *MuteUsecase {
+	return &MuteUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// Mute mutes a member for the viewer.
+func (uc *MuteUsecase) Mute(ctx context.Context, m *Mute) error {
+	if m.MemberID == 0 {
+		return v1.ErrorArgumentInvalid("member is missing")
+	}
+	if m.MuteMemberID == 0 {
+		return v1.ErrorArgumentInvalid("muted member is missing")
+	}
+	if m.MuteMemberID == m.MemberID {
+		return v1.ErrorArgumentInvalid("member can not mute itself")
+	}
+	ids, err := uc.repo.ListMutedID(ctx, m.MemberID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == m.MuteMemberID {
+			return nil
+		}
+	}
+	if len(ids) >= MaxMuted {
+		return v1.ErrorMuteLimitExceeded("mute at most %d members", MaxMuted)
+	}
+	return uc.repo.CreateMute(ctx, m)
+}
+
+// Unmute unmutes a member for the viewer.
+func (uc *MuteUsecase) Unmute(ctx context.Context, memberID, muteMemberID int64) error {
+	return uc.repo.DeleteMute(ctx, memberID, muteMemberID)
+}
+
+// ListMuted lists the members muted by the viewer.
+func (uc *MuteUsecase) ListMuted(ctx context.Context, memberID int64, offset, limit int) ([]*Mute, int32, error) {
+	return uc.repo.ListMute(ctx, memberID, offset, limit)
+}
+
+// MutedID returns the members muted by the viewer, nil for an anonymous viewer.
+func (uc *MuteUsecase) MutedID(ctx context.Context, memberID int64) ([]int64, error) {
+	if memberID == 0 {
+		return nil, nil
+	}
+	return uc.repo.ListMutedID(ctx, memberID)
+}
@@ -0,0 +1,199 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+// muteRepo keeps the muted members of member 1.
+type muteRepo struct {
+	MuteRepo
+	ids []int64
+}
+
+func (r *muteRepo) CreateMute(_ context.Context, m *Mute) error {
+	r.ids = append(r.ids, m.MuteMemberID)
+	return nil
+}
+
+func (r *muteRepo) ListMutedID(_ context.Context, memberID int64) ([]int64, error) {
+	if memberID != 1 {
+		return nil, nil
+	}
+	return r.ids, nil
+}
+
+// subjectRepo holds a single subject.
+type subjectRepo struct {
+	SubjectRepo
+	s *Subject
+}
+
+func (r *subjectRepo) GetSubject(context.Context, int64, int32) (*Subject, error) {
+	return r.s, nil
+}
+
+// mutedCommentRepo lists the comments and counts those of the excluded members.
+type mutedCommentRepo struct {
+	CommentRepo
+	roots   []*Comment
+	replies map[int64][]*Comment
+}
+
+func excluded(c *Comment, excludes []int64) bool {
+	for _, id := range excludes {
+		if c.MemberID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *mutedCommentRepo) GetComment(_ context.Context, id int64) (*Comment, error) {
+	for _, c := range r.roots {
+		if c.ID == id {
+			cc := *c
+			return &cc, nil
+		}
+	}
+	return nil, ErrCommentNotFound
+}
+
+func (r *mutedCommentRepo) ListComment(_ context.Context, _ int64, _ int32, _ int8, excludes []int64, _, _ int) ([]*Comment, error) {
+	var cs []*Comment
+	for _, c := range r.roots {
+		if !excluded(c, excludes) {
+			cc := *c
+			cs = append(cs, &cc)
+		}
+	}
+	return cs, nil
+}
+
+func (r *mutedCommentRepo) ListReply(_ context.Context, root int64, _ int8, excludes []int64, _, _ int) ([]*Comment, error) {
+	var cs []*Comment
+	for _, c := range r.replies[root] {
+		if !excluded(c, excludes) {
+			cs = append(cs, c)
+		}
+	}
+	return cs, nil
+}
+
+func (r *mutedCommentRepo) CountComment(_ context.Context, _ int64, _ int32, memberIDs []int64) (int32, error) {
+	var n int32
+	for _, c := range r.roots {
+		if excluded(c, memberIDs) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *mutedCommentRepo) CountReply(_ context.Context, roots []int64, memberIDs []int64) (map[int64]int32, error) {
+	counts := make(map[int64]int32)
+	for _, root := range roots {
+		for _, c := range r.replies[root] {
+			if excluded(c, memberIDs) {
+				counts[root]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func TestMute(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		m    *Mute
+		ok   bool
+	}{
+		{"mute", &Mute{MemberID: 1, MuteMemberID: 2}, true},
+		{"mute again", &Mute{MemberID: 1, MuteMemberID: 2}, true},
+		{"no viewer", &Mute{MuteMemberID: 2}, false},
+		{"no muted member", &Mute{MemberID: 1}, false},
+		{"self", &Mute{MemberID: 1, MuteMemberID: 1}, false},
+	} {
+		var (
+			repo = &muteRepo{ids: []int64{2}}
+			uc   = NewMuteUsecase(repo, log.DefaultLogger)
+		)
+		err := uc.Mute(context.Background(), tc.m)
+		switch {
+		case tc.ok && err != nil:
+			t.Errorf("%s: got error %v", tc.name, err)
+		case !tc.ok && !v1.IsArgumentInvalid(err):
+			t.Errorf("%s: got error %v, want argument invalid", tc.name, err)
+		case len(repo.ids) != 1:
+			t.Errorf("%s: muted %v", tc.name, repo.ids)
+		}
+	}
+
+	repo := &muteRepo{}
+	for i := int64(0); i < MaxMuted; i++ {
+		repo.ids = append(repo.ids, 100+i)
+	}
+	err := NewMuteUsecase(repo, log.DefaultLogger).Mute(context.Background(), &Mute{MemberID: 1, MuteMemberID: 2})
+	if !v1.IsMuteLimitExceeded(err) {
+		t.Fatalf("got error %v over the limit", err)
+	}
+}
+
+func TestListCommentMuted(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		repo = &mutedCommentRepo{
+			roots: []*Comment{
+				{ID: 1, MemberID: 10, RootCount: 3},
+				{ID: 2, MemberID: 20, RootCount: 1},
+				{ID: 3, MemberID: 30, RootCount: 2},
+			},
+			replies: map[int64][]*Comment{
+				1: {{ID: 4, MemberID: 20}, {ID: 5, MemberID: 30}, {ID: 6, MemberID: 20}},
+				2: {{ID: 7, MemberID: 10}},
+				3: {{ID: 8, MemberID: 20}, {ID: 9, MemberID: 20}},
+			},
+		}
+		uc = &CommentUsecase{
+			subject: &subjectRepo{s: &Subject{ObjID: 1, ObjType: 1, RootCount: 3}},
+			comment: repo,
+			mute:    NewMuteUsecase(&muteRepo{ids: []int64{20}}, log.DefaultLogger),
+			log:     log.NewHelper(log.DefaultLogger),
+		}
+	)
+	for _, tc := range []struct {
+		name    string
+		viewer  int64
+		roots   []int64
+		total   int32
+		counts  []int32
+		preview []int
+	}{
+		{"anonymous", 0, []int64{1, 2, 3}, 3, []int32{3, 1, 2}, []int{3, 1, 2}},
+		{"muting member 20", 1, []int64{1, 3}, 2, []int32{1, 0}, []int{1, 0}},
+	} {
+		cs, total, err := uc.ListComment(ctx, tc.viewer, 1, 1, SortFloor, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != tc.total || len(cs) != len(tc.roots) {
+			t.Fatalf("%s: got %d of total %d roots", tc.name, len(cs), total)
+		}
+		for i, c := range cs {
+			if c.ID != tc.roots[i] || c.RootCount != tc.counts[i] || len(c.Replies) != tc.preview[i] {
+				t.Errorf("%s: got root %d with %d replies and %d previews", tc.name, c.ID, c.RootCount, len(c.Replies))
+			}
+		}
+	}
+
+	replies, total, err := uc.ListReply(ctx, 1, 1, SortFloor, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(replies) != 1 || replies[0].ID != 5 {
+		t.Fatalf("got %d of total %d replies muting member 20", len(replies), total)
+	}
+}
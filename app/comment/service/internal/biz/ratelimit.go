@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// RateLimit is the token bucket of Key holding at most Limit tokens, refilled with Limit tokens per Window.
+type RateLimit struct {
+	Key    string
+	Limit  int32
+	Window time.Duration
+}
+
+// RateLimitRepo is the token bucket storage, an in-memory store limits
+// per instance and a shared store limits across instances.
+type RateLimitRepo interface {
+	// Take takes a token from every bucket when none of them is empty, it
+	// returns how long to wait until all of them have a token, or 0 when taken.
+	Take(ctx context.Context, limits ...RateLimit) (time.Duration, error)
+}
+
+// RateLimitUsecase limits how often members create comments.
+type RateLimitUsecase struct {
+	c    *conf.Comment_RateLimit
+	repo RateLimitRepo
+	log  *log.Helper
+}
+
+// NewRateLimitUsecase new a rate limit usecase.
+func NewRateLimitUsecase(c *conf.Comment, repo RateLimitRepo, logger log.Logger) *RateLimitUsecase {
+	return &RateLimitUsecase{c: c.GetRateLimit(), repo: repo, log: log.NewHelper(logger)}
+}
+
+// Allow takes a token from the member, ip and member-subject buckets of
+// the obj_type, it returns a RATE_LIMITED error and takes no token when
+// any of them is empty.
+func (uc *RateLimitUsecase) Allow(ctx context.Context, memberID, ip, objID int64, objType int32) error {
+	rule := uc.c.GetDefault()
+	if r, ok := uc.c.GetObjTypes()[objType]; ok {
+		rule = r
+	}
+	if rule == nil {
+		return nil
+	}
+	var limits []RateLimit
+	add := func(key string, b *conf.Comment_RateLimit_Bucket) {
+		if b.GetLimit() <= 0 || b.GetWindow() == nil {
+			return
+		}
+		limits = append(limits, RateLimit{Key: key, Limit: b.GetLimit(), Window: b.GetWindow().AsDuration()})
+	}
+	if memberID != 0 {
+		add(fmt.Sprintf("member:%d:%d", objType, memberID), rule.GetMember())
+		add(fmt.Sprintf("member_subject:%d:%d:%d", objType, objID, memberID), rule.GetMemberSubject())
+	}
+	if ip != 0 {
+		add(fmt.Sprintf("ip:%d:%d", objType, ip), rule.GetIp())
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+	wait, err := uc.repo.Take(ctx, limits...)
+	if err != nil {
+		return err
+	}
+	if wait == 0 {
+		return nil
+	}
+	retryAfter := int64(math.Ceil(wait.Seconds()))
+	return v1.ErrorRateLimited("too many comments, retry after %ds", retryAfter).
+		WithMetadata(map[string]string{"retry_after": strconv.FormatInt(retryAfter, 10)})
+}
@@ -0,0 +1,118 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// Report is a member reporting a comment.
+type Report struct {
+	ID         int64
+	CommentID  int64
+	ObjID      int64
+	ObjType    int32
+	MemberID   int64 // 举报人
+	Reason     int32
+	Content    string
+	CreateTime time.Time
+}
+
+// ReportStat is the aggregated reports of a comment.
+type ReportStat struct {
+	CommentID  int64
+	ObjID      int64
+	ObjType    int32
+	MemberID   int64 // 评论作者
+	Count      int32
+	Reasons    map[int32]int32
+	Hidden     bool
+	UpdateTime time.Time
+
+	Comment *Comment
+}
+
+// ReportRepo is report storage.
+type ReportRepo interface {
+	// CreateReport stores the report once per reporter and comment,
+	// it returns the report count of the comment and whether the report is new.
+	CreateReport(ctx context.Context, r *Report, author int64) (int32, bool, error)
+	// ListReportStat returns stats ordered by count desc.
+	ListReportStat(ctx context.Context, offset, limit int) ([]*ReportStat, int32, error)
+	// ListMemberReport returns the reports by the member after the cursor
+	// id ordered by id asc.
+	ListMemberReport(ctx context.Context, memberID, cursor int64, limit int) ([]*Report, error)
+}
+
+// ReportUsecase is report usecase.
+type ReportUsecase struct {
+	c          *conf.Comment_Report
+	comment    CommentRepo
+	report     ReportRepo
+	moderation ModerationRepo
+	log        *log.Helper
+}
+
+// NewReportUsecase new a report usecase.
+func NewReportUsecase(c *conf.Comment, comment CommentRepo, report ReportRepo, moderation ModerationRepo, logger log.Logger) *ReportUsecase {
+	return &ReportUsecase{
+		c:          c.GetReport(),
+		comment:    comment,
+		report:     report,
+		moderation: moderation,
+		log:        log.NewHelper(logger),
+	}
+}
+
+// ReportComment reports a comment, the comment is hidden and put into
+// the moderation queue once its report count reaches the threshold.
+func (uc *ReportUsecase) ReportComment(ctx context.Context, r *Report) error {
+	if _, ok := v1.ReportReason_name[r.Reason]; !ok {
+		return v1.ErrorArgumentInvalid("unknown report reason %d", r.Reason)
+	}
+	c, err := uc.comment.GetComment(ctx, r.CommentID)
+	if err != nil {
+		return err
+	}
+	if c.State != CommentStateNormal && c.State != CommentStatePending {
+		return ErrCommentNotFound
+	}
+	r.ObjID, r.ObjType = c.ObjID, c.ObjType
+	count, created, err := uc.report.CreateReport(ctx, r, c.MemberID)
+	if err != nil {
+		return err
+	}
+	threshold := uc.c.GetHideThreshold()
+	if !created || threshold <= 0 || count < threshold || c.State != CommentStateNormal {
+		return nil
+	}
+	uc.log.WithContext(ctx).Infof("comment %d reported %d times, move to moderation queue", c.ID, count)
+	return uc.moderation.EnqueueModeration(ctx, c, ModerationSourceReport)
+}
+
+// ListReportedComment lists the most reported comments.
+func (uc *ReportUsecase) ListReportedComment(ctx context.Context, offset, limit int) ([]*ReportStat, int32, error) {
+	stats, total, err := uc.report.ListReportStat(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]int64, 0, len(stats))
+	for _, s := range stats {
+		ids = append(ids, s.CommentID)
+	}
+	cs, err := uc.comment.ListCommentByID(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	m := make(map[int64]*Comment, len(cs))
+	for _, c := range cs {
+		m[c.ID] = c
+	}
+	for _, s := range stats {
+		s.Comment = m[s.CommentID]
+	}
+	return stats, total, nil
+}
@@ -0,0 +1,217 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"time"
+	"unicode"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"golang.org/x/text/width"
+)
+
+// 重复评论的处理方式
+const (
+	SpamActionNone       = 0 // 不是重复评论
+	SpamActionReject     = 1 // 拒绝
+	SpamActionQuarantine = 2 // 隐藏并进入审核队列
+)
+
+// simhashMinLength is the minimum normalized length compared by simhash,
+// shorter messages are only compared by hash.
+const simhashMinLength = 8
+
+// Fingerprint is the fingerprint of a message.
+type Fingerprint struct {
+	Hash       uint64 // 归一化文本的 hash
+	SimHash    uint64
+	Length     int // 归一化文本的字数
+	CreateTime time.Time
+}
+
+// Similar reports whether the fingerprints are from identical or near-identical messages.
+func (f *Fingerprint) Similar(o *Fingerprint, distance int) bool {
+	if f.Hash == o.Hash {
+		return true
+	}
+	if distance <= 0 || f.Length < simhashMinLength || o.Length < simhashMinLength {
+		return false
+	}
+	return bits.OnesCount64(f.SimHash^o.SimHash) <= distance
+}
+
+// NewFingerprint computes the fingerprint of message.
+func NewFingerprint(message string) *Fingerprint {
+	rs := normalize(message)
+	h := fnv.New64a()
+	h.Write([]byte(string(rs)))
+	return &Fingerprint{
+		Hash:       h.Sum64(),
+		SimHash:    simhash(rs),
+		Length:     len(rs),
+		CreateTime: time.Now(),
+	}
+}
+
+// normalize folds width and case and drops spaces, punctuations and symbols.
+func normalize(message string) []rune {
+	message = width.Fold.String(message)
+	rs := make([]rune, 0, len(message))
+	for _, r := range message {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			rs = append(rs, unicode.ToLower(r))
+		}
+	}
+	return rs
+}
+
+// simhash computes a 64 bits simhash over the rune bigrams.
+func simhash(rs []rune) uint64 {
+	if len(rs) == 0 {
+		return 0
+	}
+	var v [64]int
+	add := func(s string) {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		x := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if x&(1<<uint(i)) != 0 {
+				v[i]++
+			} else {
+				v[i]--
+			}
+		}
+	}
+	if len(rs) == 1 {
+		add(string(rs))
+	}
+	for i := 0; i+1 < len(rs); i++ {
+		add(string(rs[i : i+2]))
+	}
+	var x uint64
+	for i := 0; i < 64; i++ {
+		if v[i] > 0 {
+			x |= 1 << uint(i)
+		}
+	}
+	return x
+}
+
+// SpamRepo stores the recent fingerprints of members and ips.
+type SpamRepo interface {
+	// AddFingerprint lists the fingerprints of keys created after since and
+	// adds f to keys for ttl when add, called with the lists in the order of
+	// keys, returns true. The list and the add are atomic, of two concurrent
+	// adds to a key the later one lists the fingerprint of the other.
+	AddFingerprint(ctx context.Context, keys []string, f *Fingerprint, since time.Time, ttl time.Duration,
+		add func(fps [][]*Fingerprint) bool) error
+	// RemoveFingerprint removes f from keys.
+	RemoveFingerprint(ctx context.Context, keys []string, f *Fingerprint) error
+}
+
+// SpamUsecase detects duplicate and flood comments.
+type SpamUsecase struct {
+	c    *conf.Comment_Spam
+	repo SpamRepo
+	log  *log.Helper
+}
+
+// NewSpamUsecase new a spam usecase.
+func NewSpamUsecase(c *conf.Comment, repo SpamRepo, logger log.Logger) *SpamUsecase {
+	return &SpamUsecase{c: c.GetSpam(), repo: repo, log: log.NewHelper(logger)}
+}
+
+// Check returns how to handle the message of c and records its fingerprint
+// unless it is rejected, a message is a duplicate when the member or the ip
+// has sent max_duplicates identical or near-identical messages within the
+// window, the check is disabled when the window or max_duplicates is not
+// set. The check and the record are atomic, a burst of concurrent
+// duplicates is not let through. The fingerprint of previous, the message
+// replaced by an edit, is left out once, an edited comment is not a
+// duplicate of itself. The recorded fingerprint is returned to be forgotten
+// when the comment is not stored.
+func (uc *SpamUsecase) Check(ctx context.Context, c *Comment, previous string) (int, *Fingerprint, error) {
+	keys := spamKeys(c)
+	if uc.c.GetWindow() == nil || uc.c.GetMaxDuplicates() <= 0 || len(keys) == 0 {
+		return SpamActionNone, nil, nil
+	}
+	window := uc.c.GetWindow().AsDuration()
+	fp := NewFingerprint(c.Message)
+	var prev *Fingerprint
+	if previous != "" {
+		prev = NewFingerprint(previous)
+	}
+	action := SpamActionNone
+	err := uc.repo.AddFingerprint(ctx, keys, fp, fp.CreateTime.Add(-window), window, func(lists [][]*Fingerprint) bool {
+		action = SpamActionNone
+		for _, fps := range lists {
+			if uc.duplicates(fp, prev, fps) >= uc.c.GetMaxDuplicates() {
+				action = uc.action()
+			}
+		}
+		return action != SpamActionReject
+	})
+	if err != nil {
+		return SpamActionNone, nil, err
+	}
+	if action == SpamActionNone {
+		return action, fp, nil
+	}
+	uc.log.WithContext(ctx).Infof("duplicate comment from member %d ip %d, fingerprint %016x",
+		c.MemberID, c.IP, fp.Hash)
+	if action == SpamActionReject {
+		return action, nil, nil
+	}
+	return action, fp, nil
+}
+
+// duplicates counts the fingerprints similar to fp, leaving out prev once.
+func (uc *SpamUsecase) duplicates(fp, prev *Fingerprint, fps []*Fingerprint) int32 {
+	var (
+		n    int32
+		self = prev != nil
+	)
+	for _, f := range fps {
+		if self && f.Hash == prev.Hash {
+			self = false
+			continue
+		}
+		if fp.Similar(f, int(uc.c.GetSimhashDistance())) {
+			n++
+		}
+	}
+	return n
+}
+
+func (uc *SpamUsecase) action() int {
+	if uc.c.GetAction() == conf.Comment_Spam_QUARANTINE {
+		return SpamActionQuarantine
+	}
+	return SpamActionReject
+}
+
+// Forget removes the fingerprint recorded by Check when the comment is not
+// stored, only the stored messages count as duplicates.
+func (uc *SpamUsecase) Forget(ctx context.Context, c *Comment, fp *Fingerprint) {
+	if fp == nil {
+		return
+	}
+	if err := uc.repo.RemoveFingerprint(ctx, spamKeys(c), fp); err != nil {
+		uc.log.WithContext(ctx).Errorf("forget fingerprint of member %d: %v", c.MemberID, err)
+	}
+}
+
+func spamKeys(c *Comment) []string {
+	var keys []string
+	if c.MemberID != 0 {
+		keys = append(keys, fmt.Sprintf("member:%d", c.MemberID))
+	}
+	if c.IP != 0 {
+		keys = append(keys, fmt.Sprintf("ip:%d", c.IP))
+	}
+	return keys
+}
@@ -0,0 +1,180 @@
+package biz
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// spamRepo keeps the fingerprints of the keys without expiry.
+type spamRepo struct {
+	mu   sync.Mutex
+	keys map[string][]*Fingerprint
+}
+
+func (r *spamRepo) AddFingerprint(_ context.Context, keys []string, f *Fingerprint, since time.Time, _ time.Duration,
+	add func(fps [][]*Fingerprint) bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lists [][]*Fingerprint
+	for _, key := range keys {
+		var list []*Fingerprint
+		for _, o := range r.keys[key] {
+			if o.CreateTime.After(since) {
+				list = append(list, o)
+			}
+		}
+		lists = append(lists, list)
+	}
+	if add(lists) {
+		for _, key := range keys {
+			r.keys[key] = append(r.keys[key], f)
+		}
+	}
+	return nil
+}
+
+func (r *spamRepo) RemoveFingerprint(_ context.Context, keys []string, f *Fingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		for i, o := range r.keys[key] {
+			if o == f {
+				r.keys[key] = append(r.keys[key][:i], r.keys[key][i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		msg, want string
+	}{
+		{"", ""},
+		{"Hello, World!", "helloworld"},
+		{"ＡＢＣ　１２３", "abc123"},
+		{"你好，世界。", "你好世界"},
+		{" ... ??? 😀 ", ""},
+		{"Ünïcode Ä", "ünïcodeä"},
+	} {
+		if got := string(normalize(tc.msg)); got != tc.want {
+			t.Errorf("normalize(%q) = %q, want %q", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestFingerprintSimilar(t *testing.T) {
+	const long = "this product is the best deal on the market today"
+	for _, tc := range []struct {
+		name     string
+		a, b     string
+		distance int
+		similar  bool
+	}{
+		{"identical after normalizing", long, "THIS product is the best deal on the market today!!", 0, true},
+		{"near-identical", long, "this product is the best deal in the market today", 8, true},
+		{"near-identical without simhash", long, "this product is the best deal in the market today", 0, false},
+		{"unrelated", long, "the weather was lovely at the beach this weekend", 8, false},
+		{"short messages only by hash", "buy now", "buy n0w", 64, false},
+		{"short identical", "buy now", "Buy now.", 0, true},
+	} {
+		a, b := NewFingerprint(tc.a), NewFingerprint(tc.b)
+		if got := a.Similar(b, tc.distance); got != tc.similar {
+			t.Errorf("%s: got similar %v at distance %d, simhash distance %d", tc.name, got, tc.distance,
+				bits.OnesCount64(a.SimHash^b.SimHash))
+		}
+	}
+}
+
+func TestSpamCheck(t *testing.T) {
+	const msg = "this product is the best deal on the market today"
+	spam := func(maxDuplicates int32, action conf.Comment_Spam_Action) *conf.Comment_Spam {
+		return &conf.Comment_Spam{Window: durationpb.New(time.Minute), MaxDuplicates: maxDuplicates,
+			SimhashDistance: 8, Action: action}
+	}
+	for _, tc := range []struct {
+		name     string
+		c        *conf.Comment_Spam
+		previous string
+		sent     int // the duplicates sent before
+		want     int
+		recorded bool
+	}{
+		{name: "disabled without window", c: &conf.Comment_Spam{MaxDuplicates: 1}, sent: 3, want: SpamActionNone},
+		{name: "disabled without max", c: spam(0, conf.Comment_Spam_REJECT), sent: 3, want: SpamActionNone},
+		{name: "disabled by negative max", c: spam(-1, conf.Comment_Spam_REJECT), sent: 3, want: SpamActionNone},
+		{name: "under max", c: spam(2, conf.Comment_Spam_REJECT), sent: 1, want: SpamActionNone, recorded: true},
+		{name: "at max rejected", c: spam(2, conf.Comment_Spam_REJECT), sent: 2, want: SpamActionReject},
+		{name: "at max quarantined", c: spam(2, conf.Comment_Spam_QUARANTINE), sent: 2, want: SpamActionQuarantine,
+			recorded: true},
+		{name: "edit leaves out the previous message", c: spam(2, conf.Comment_Spam_REJECT), previous: msg, sent: 2,
+			want: SpamActionNone, recorded: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				ctx  = context.Background()
+				repo = &spamRepo{keys: make(map[string][]*Fingerprint)}
+				uc   = &SpamUsecase{c: tc.c, repo: repo, log: log.NewHelper(log.DefaultLogger)}
+				c    = &Comment{MemberID: 1, IP: 2, Message: msg}
+			)
+			for i := 0; i < tc.sent; i++ {
+				repo.keys["member:1"] = append(repo.keys["member:1"], NewFingerprint(msg))
+			}
+			action, fp, err := uc.Check(ctx, c, tc.previous)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action != tc.want {
+				t.Fatalf("got action %d, want %d", action, tc.want)
+			}
+			if got := fp != nil && len(repo.keys["ip:2"]) == 1; got != tc.recorded {
+				t.Fatalf("got recorded %v, want %v", got, tc.recorded)
+			}
+			uc.Forget(ctx, c, fp)
+			if len(repo.keys["ip:2"]) != 0 {
+				t.Fatal("kept a forgotten fingerprint")
+			}
+		})
+	}
+}
+
+func TestSpamCheckConcurrent(t *testing.T) {
+	const n = 8
+	var (
+		ctx  = context.Background()
+		repo = &spamRepo{keys: make(map[string][]*Fingerprint)}
+		uc   = &SpamUsecase{c: &conf.Comment_Spam{Window: durationpb.New(time.Minute), MaxDuplicates: 2},
+			repo: repo, log: log.NewHelper(log.DefaultLogger)}
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted int
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			action, _, err := uc.Check(ctx, &Comment{MemberID: 1, Message: "same message"}, "")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if action == SpamActionNone {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted != 2 {
+		t.Fatalf("accepted %d of a burst of %d duplicates, want 2", accepted, n)
+	}
+}
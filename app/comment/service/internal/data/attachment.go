@@ -0,0 +1,118 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+const attachmentColumns = `id, token, member_id, comment_id, path, url, format, size, width, height, create_time`
+
+type attachmentRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewAttachmentRepo .
+func NewAttachmentRepo(data *Data, logger log.Logger) biz.AttachmentRepo {
+	return &attachmentRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *attachmentRepo) CreateAttachment(ctx context.Context, a *biz.Attachment) error {
+	now := time.Now()
+	res, err := r.data.db.ExecContext(ctx, `INSERT INTO comment_attachment
+		(token, member_id, comment_id, path, url, format, size, width, height, create_time)
+		VALUES (?, ?, 0, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Token, a.MemberID, a.Key, a.URL, a.Format, a.Size, a.Width, a.Height, now)
+	if err != nil {
+		return err
+	}
+	a.ID, err = res.LastInsertId()
+	a.CreateTime = now
+	return err
+}
+
+func (r *attachmentRepo) ListAttachmentByToken(ctx context.Context, tokens []string) ([]*biz.Attachment, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(tokens))
+	for _, t := range tokens {
+		args = append(args, t)
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT `+attachmentColumns+` FROM comment_attachment
+		WHERE token IN (`+placeholders(len(tokens))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
+
+// bindAttachments attaches the uploaded attachments to comment in tx.
+func bindAttachments(ctx context.Context, tx *sql.Tx, commentID int64, as []*biz.Attachment) error {
+	for _, a := range as {
+		res, err := tx.ExecContext(ctx, `UPDATE comment_attachment SET comment_id = ? WHERE id = ? AND comment_id = 0`,
+			commentID, a.ID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return biz.ErrAttachmentUsed
+		}
+		a.CommentID = commentID
+	}
+	return nil
+}
+
+// loadAttachments sets the attachments of cs.
+func (d *Data) loadAttachments(ctx context.Context, cs []*biz.Comment) error {
+	if len(cs) == 0 {
+		return nil
+	}
+	var (
+		args = make([]interface{}, 0, len(cs))
+		byID = make(map[int64]*biz.Comment, len(cs))
+	)
+	for _, c := range cs {
+		args = append(args, c.ID)
+		byID[c.ID] = c
+	}
+	rows, err := d.db.QueryContext(ctx, `SELECT `+attachmentColumns+` FROM comment_attachment
+		WHERE comment_id IN (`+placeholders(len(cs))+`) ORDER BY id`, args...)
+	if err != nil {
+		return err
+	}
+	as, err := scanAttachments(rows)
+	if err != nil {
+		return err
+	}
+	for _, a := range as {
+		if c, ok := byID[a.CommentID]; ok {
+			c.Attachments = append(c.Attachments, a)
+		}
+	}
+	return nil
+}
+
+func scanAttachments(rows *sql.Rows) ([]*biz.Attachment, error) {
+	defer rows.Close()
+	var as []*biz.Attachment
+	for rows.Next() {
+		a := new(biz.Attachment)
+		err := rows.Scan(&a.ID, &a.Token, &a.MemberID, &a.CommentID, &a.Key, &a.URL, &a.Format,
+			&a.Size, &a.Width, &a.Height, &a.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, a)
+	}
+	return as, rows.Err()
+}
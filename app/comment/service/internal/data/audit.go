@@ -0,0 +1,44 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type auditRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewAuditRepo .
+func NewAuditRepo(data *Data, logger log.Logger) biz.AuditRepo {
+	return &auditRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *auditRepo) AddAudit(ctx context.Context, a *biz.Audit) error {
+	return addAudit(ctx, r.data.db, a)
+}
+
+func addAudit(ctx context.Context, e execer, a *biz.Audit) error {
+	now := time.Now()
+	res, err := e.ExecContext(ctx, `INSERT INTO comment_audit_log (action, operator_id, member_id, detail, create_time)
+		VALUES (?, ?, ?, ?, ?)`, a.Action, a.OperatorID, a.MemberID, a.Detail, now)
+	if err != nil {
+		return err
+	}
+	a.ID, err = res.LastInsertId()
+	a.CreateTime = now
+	return err
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type blockRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewBlockRepo .
+func NewBlockRepo(data *Data, logger log.Logger) biz.BlockRepo {
+	return &blockRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *blockRepo) SaveBlock(ctx context.Context, b *biz.Block) error {
+	now := time.Now()
+	expire := sql.NullTime{Time: b.ExpireTime, Valid: !b.ExpireTime.IsZero()}
+	return r.data.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE comment_block SET reason = ?, expire_time = ?, create_time = ?
+			WHERE owner_id = ? AND member_id = ?`, b.Reason, expire, now, b.OwnerID, b.MemberID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n > 0 {
+			return err
+		}
+		res, err = tx.ExecContext(ctx, `INSERT INTO comment_block (owner_id, member_id, reason, expire_time, create_time)
+			VALUES (?, ?, ?, ?, ?)`, b.OwnerID, b.MemberID, b.Reason, expire, now)
+		if err != nil {
+			return err
+		}
+		b.ID, err = res.LastInsertId()
+		b.CreateTime = now
+		return err
+	})
+}
+
+func (r *blockRepo) DeleteBlock(ctx context.Context, ownerID, memberID int64) error {
+	_, err := r.data.db.ExecContext(ctx, `DELETE FROM comment_block WHERE owner_id = ? AND member_id = ?`,
+		ownerID, memberID)
+	return err
+}
+
+func (r *blockRepo) ListMemberBlock(ctx context.Context, memberID int64, ownerIDs []int64) ([]*biz.Block, error) {
+	if len(ownerIDs) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{memberID}
+	for _, id := range ownerIDs {
+		args = append(args, id)
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT id, owner_id, member_id, reason, expire_time, create_time
+		FROM comment_block WHERE member_id = ? AND owner_id IN (`+placeholders(len(ownerIDs))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanBlocks(rows)
+}
+
+func (r *blockRepo) ListBlock(ctx context.Context, ownerID int64, now time.Time, offset, limit int) ([]*biz.Block, int32, error) {
+	var total int32
+	err := r.data.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_block
+		WHERE owner_id = ? AND (expire_time IS NULL OR expire_time > ?)`, ownerID, now).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT id, owner_id, member_id, reason, expire_time, create_time
+		FROM comment_block WHERE owner_id = ? AND (expire_time IS NULL OR expire_time > ?)
+		ORDER BY create_time DESC LIMIT ? OFFSET ?`, ownerID, now, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	bs, err := scanBlocks(rows)
+	return bs, total, err
+}
+
+func scanBlocks(rows *sql.Rows) ([]*biz.Block, error) {
+	defer rows.Close()
+	var bs []*biz.Block
+	for rows.Next() {
+		var (
+			b      = new(biz.Block)
+			expire sql.NullTime
+		)
+		if err := rows.Scan(&b.ID, &b.OwnerID, &b.MemberID, &b.Reason, &expire, &b.CreateTime); err != nil {
+			return nil, err
+		}
+		b.ExpireTime = expire.Time
+		bs = append(bs, b)
+	}
+	return bs, rows.Err()
+}
@@ -0,0 +1,395 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// 布隆过滤器, 保存已存在的主题和评论ID, 不存在的主题和评论不再访问缓存和数据库:
+//   comment:bloom:{name}          过滤器的位图, name 为 subject 或 comment
+//   comment:bloom:{name}:next     重建中的位图
+//   comment:bloom:{name}:building 重建中的标记, 此时新增的键同时写入重建中的位图
+//   comment:bloom:{name}:lock     重建的锁
+//   comment:bloom:{name}:built    最近一次重建的标记, 过期后在后台重建
+// 新增在数据库提交前写入, 只写入已存在的位图, 过滤器丢失后不会留下残缺的位图,
+// 提交失败只留下误判为存在的键, 进程崩溃不会遗漏已提交的键.
+// 写入新增键的事务最长 bloomWriteTimeout, 重建设置标记后等待 bloomSettle 再扫描数据库,
+// 标记之前只写入旧位图的事务此时已经提交或回滚, 提交的键会被扫描到.
+// 过滤器不存在时查询数据库, 并在后台从数据库重建, redis 丢失部分数据留下的遗漏由定期重建修复,
+// 判为不存在的键按 bloomVerifyRate 抽样查询数据库, 存在时丢弃过滤器, 由重建修复.
+// 主题和评论不会从数据库删除, 过滤器只增不减.
+
+const (
+	// bloomBatch is the number of rows read per query of a rebuild.
+	bloomBatch = 1000
+	// bloomLockTTL bounds a rebuild, the lock and the marker of a crashed rebuild expire after it.
+	bloomLockTTL = time.Hour
+	// bloomRetry is the interval between the rebuilds a process starts.
+	bloomRetry = time.Minute
+	// defaultBloomRebuild is the interval of the periodic rebuilds when it is not configured.
+	defaultBloomRebuild = 24 * time.Hour
+	// bloomVerifyRate is the rate of the keys not in the filter looked up in the database.
+	bloomVerifyRate = 0.01
+	// bloomWriteTimeout bounds a write adding a key, it is rolled back after it.
+	bloomWriteTimeout = 10 * time.Second
+	// bloomSettle is the wait of a rebuild between the marker and the scan,
+	// longer than bloomWriteTimeout to cover a commit in flight at its end.
+	bloomSettle = bloomWriteTimeout + 5*time.Second
+)
+
+// bloomCheck checks the keys of ARGV[1] offsets each. It returns {-1} when
+// the filter does not exist, or the state of the filter, 1 when it is due
+// to be rebuilt, followed by 1 for each key that may be in the filter and
+// 0 for each key that is not.
+var bloomCheck = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return {-1}
+end
+local res = {1 - redis.call('EXISTS', KEYS[2])}
+local n = tonumber(ARGV[1])
+for i = 2, #ARGV, n do
+	local found = 1
+	for j = i, i + n - 1 do
+		if redis.call('GETBIT', KEYS[1], ARGV[j]) == 0 then
+			found = 0
+			break
+		end
+	end
+	res[#res + 1] = found
+end
+return res`)
+
+// bloomAdd sets the bits in the filter and, while it is rebuilt, in the
+// next filter, a missing filter is not created.
+var bloomAdd = redis.NewScript(`
+local live = redis.call('EXISTS', KEYS[1]) == 1
+local building = redis.call('EXISTS', KEYS[3]) == 1
+for i = 1, #ARGV do
+	if live then
+		redis.call('SETBIT', KEYS[1], ARGV[i], 1)
+	end
+	if building then
+		redis.call('SETBIT', KEYS[2], ARGV[i], 1)
+	end
+end
+return 0`)
+
+// bloomFilter is a bloom filter in a redis bitmap shared by the instances
+// of the comment service, a nil filter contains every key.
+type bloomFilter struct {
+	rdb      *redis.Client
+	key      string
+	bits     uint64
+	hashes   int
+	interval time.Duration
+	verify   float64                                                       // the rate of the keys not in the filter looked up
+	settle   time.Duration                                                 // the wait of a rebuild before the scan
+	scan     func(ctx context.Context, fn func(keys []string) error) error // all keys in the database
+	exists   func(ctx context.Context, key string) (bool, error)           // whether the key is in the database
+	log      *log.Helper
+
+	mu      sync.Mutex
+	started time.Time // the last rebuild started by this process
+}
+
+func newBloomFilter(rdb *redis.Client, name string, c *conf.Data_Bloom, bits int64,
+	scan func(ctx context.Context, fn func(keys []string) error) error,
+	exists func(ctx context.Context, key string) (bool, error), logger log.Logger) *bloomFilter {
+	if bits <= 0 || c.Hashes <= 0 {
+		return nil
+	}
+	interval := c.RebuildInterval.AsDuration()
+	if interval <= 0 {
+		interval = defaultBloomRebuild
+	}
+	return &bloomFilter{
+		rdb:      rdb,
+		key:      "comment:bloom:" + name,
+		bits:     uint64(bits),
+		hashes:   int(c.Hashes),
+		interval: interval,
+		verify:   bloomVerifyRate,
+		settle:   bloomSettle,
+		scan:     scan,
+		exists:   exists,
+		log:      log.NewHelper(logger),
+	}
+}
+
+func subjectBloomKey(objID int64, objType int32) string {
+	return fmt.Sprintf("%d:%d", objType, objID)
+}
+
+func commentBloomKey(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// offsets returns the bits of the key by double hashing the two halves of its fnv hash.
+func (b *bloomFilter) offsets(key string) []int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := h.Sum64()
+	h1, h2 := sum&0xffffffff, sum>>32|1
+	offs := make([]int64, b.hashes)
+	for i := range offs {
+		offs[i] = int64((h1 + uint64(i)*h2) % b.bits)
+	}
+	return offs
+}
+
+func (b *bloomFilter) args(keys ...string) []interface{} {
+	args := make([]interface{}, 0, len(keys)*b.hashes)
+	for _, key := range keys {
+		for _, off := range b.offsets(key) {
+			args = append(args, off)
+		}
+	}
+	return args
+}
+
+// mayContain reports whether the key may be in the filter, a missing
+// filter contains every key and is rebuilt in the background.
+func (b *bloomFilter) mayContain(ctx context.Context, key string) (bool, error) {
+	found, err := b.mayContainAll(ctx, []string{key})
+	return found[0], err
+}
+
+// mayContainAll reports whether each of the keys may be in the filter. A
+// sample of the keys not in the filter is looked up in the database, the
+// filter missing one of them is dropped to have it rebuilt.
+func (b *bloomFilter) mayContainAll(ctx context.Context, keys []string) ([]bool, error) {
+	found := make([]bool, len(keys))
+	for i := range found {
+		found[i] = true
+	}
+	if b == nil || len(keys) == 0 {
+		return found, nil
+	}
+	args := append([]interface{}{b.hashes}, b.args(keys...)...)
+	res, err := bloomCheck.Run(ctx, b.rdb, []string{b.key, b.key + ":built"}, args...).Result()
+	if err != nil {
+		return found, err
+	}
+	vals, _ := res.([]interface{})
+	if len(vals) != len(keys)+1 {
+		b.rebuildAsync()
+		return found, nil
+	}
+	if vals[0] != int64(0) {
+		b.rebuildAsync()
+	}
+	for i, key := range keys {
+		if vals[i+1] == int64(1) || rand.Float64() >= b.verify {
+			found[i] = vals[i+1] == int64(1)
+			continue
+		}
+		ok, err := b.exists(ctx, key)
+		if err != nil {
+			return found, err
+		}
+		if found[i] = ok; ok {
+			b.log.Errorf("key %s missing from bloom filter %s", key, b.key)
+			if err = b.rdb.Del(ctx, b.key).Err(); err != nil {
+				return found, err
+			}
+			b.rebuildAsync()
+		}
+	}
+	return found, nil
+}
+
+// writeContext bounds the write adding a key by bloomWriteTimeout, a rebuild
+// waits for the writes started before it.
+func (b *bloomFilter) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b == nil {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, bloomWriteTimeout)
+}
+
+// add adds the key before it is committed to the database in a write bound
+// by writeContext. It ignores the
+// cancellation of ctx, a key missing from the filter would hide the row, and
+// drops the filter to have it rebuilt when the key cannot be added.
+func (b *bloomFilter) add(_ context.Context, key string) error {
+	if b == nil {
+		return nil
+	}
+	ctx := context.Background()
+	err := bloomAdd.Run(ctx, b.rdb, []string{b.key, b.key + ":next", b.key + ":building"}, b.args(key)...).Err()
+	if err != nil {
+		if derr := b.rdb.Del(ctx, b.key).Err(); derr != nil {
+			b.log.Errorf("drop bloom filter %s: %v", b.key, derr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (b *bloomFilter) rebuildAsync() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Since(b.started) < bloomRetry {
+		return
+	}
+	b.started = time.Now()
+	go func() {
+		if err := b.rebuild(context.Background()); err != nil {
+			b.log.Errorf("rebuild bloom filter %s: %v", b.key, err)
+		}
+	}()
+}
+
+// rebuild builds the filter from the database into the next filter and
+// replaces the filter with it, it is skipped while another process is
+// rebuilding the filter. The keys added after the building marker is set
+// are set in the next filter, and the scan starts settle after the marker,
+// when the writes that added keys before it have been committed or rolled
+// back, so no committed key is lost.
+func (b *bloomFilter) rebuild(ctx context.Context) error {
+	var (
+		next     = b.key + ":next"
+		building = b.key + ":building"
+		lock     = b.key + ":lock"
+	)
+	ok, err := b.rdb.SetNX(ctx, lock, 1, bloomLockTTL).Result()
+	if err != nil || !ok {
+		return err
+	}
+	defer b.rdb.Del(context.Background(), lock, building)
+
+	// the whole bitmap is allocated up front, so the next filter exists
+	// even when there is no key
+	_, err = b.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Del(ctx, next)
+		p.SetBit(ctx, next, int64(b.bits-1), 0)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = b.rdb.Set(ctx, building, 1, bloomLockTTL).Err(); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(b.settle):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	err = b.scan(ctx, func(keys []string) error {
+		_, err := b.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+			for _, key := range keys {
+				for _, off := range b.offsets(key) {
+					p.SetBit(ctx, next, off, 1)
+				}
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = b.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Rename(ctx, next, b.key)
+		p.Set(ctx, b.key+":built", 1, b.interval)
+		return nil
+	})
+	return err
+}
+
+// subjectExists reports whether the subject of the filter key is in the database.
+func (d *Data) subjectExists(ctx context.Context, key string) (bool, error) {
+	var (
+		objID   int64
+		objType int32
+	)
+	if _, err := fmt.Sscanf(key, "%d:%d", &objType, &objID); err != nil {
+		return false, err
+	}
+	var n int
+	err := d.subjectDB(objID, objType).QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_subject
+		WHERE obj_id = ? AND obj_type = ?`, objID, objType).Scan(&n)
+	return n > 0, err
+}
+
+// commentExists reports whether the comment of the filter key is in the database.
+func (d *Data) commentExists(ctx context.Context, key string) (bool, error) {
+	id, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	var n int
+	err = d.commentDB(id).QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_index WHERE id = ?`, id).Scan(&n)
+	return n > 0, err
+}
+
+// scanSubjectKeys passes the filter keys of all subjects to fn in batches.
+func (d *Data) scanSubjectKeys(ctx context.Context, fn func(keys []string) error) error {
+	return d.scanKeys(ctx, `SELECT id, obj_id, obj_type FROM comment_subject WHERE id > ? ORDER BY id LIMIT ?`,
+		func(rows *sql.Rows) (int64, string, error) {
+			var (
+				id, objID int64
+				objType   int32
+			)
+			err := rows.Scan(&id, &objID, &objType)
+			return id, subjectBloomKey(objID, objType), err
+		}, fn)
+}
+
+// scanCommentKeys passes the filter keys of all comments to fn in batches.
+func (d *Data) scanCommentKeys(ctx context.Context, fn func(keys []string) error) error {
+	return d.scanKeys(ctx, `SELECT id FROM comment_index WHERE id > ? ORDER BY id LIMIT ?`,
+		func(rows *sql.Rows) (int64, string, error) {
+			var id int64
+			err := rows.Scan(&id)
+			return id, commentBloomKey(id), err
+		}, fn)
+}
+
+// scanKeys pages through every shard database by id with query, scan
+// returns the id and the filter key of a row.
+func (d *Data) scanKeys(ctx context.Context, query string, scan func(rows *sql.Rows) (int64, string, error),
+	fn func(keys []string) error) error {
+	for _, db := range d.shardDBs {
+		var last int64
+		for {
+			rows, err := db.QueryContext(ctx, query, last, bloomBatch)
+			if err != nil {
+				return err
+			}
+			keys := make([]string, 0, bloomBatch)
+			for rows.Next() {
+				var key string
+				if last, key, err = scan(rows); err != nil {
+					_ = rows.Close()
+					return err
+				}
+				keys = append(keys, key)
+			}
+			_ = rows.Close()
+			if err = rows.Err(); err != nil {
+				return err
+			}
+			if len(keys) > 0 {
+				if err = fn(keys); err != nil {
+					return err
+				}
+			}
+			if len(keys) < bloomBatch {
+				break
+			}
+		}
+	}
+	return nil
+}
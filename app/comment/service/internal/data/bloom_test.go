@@ -0,0 +1,251 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+)
+
+func newBloomTestData(t *testing.T) (*Data, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	d := openTestData(t, &conf.Data{
+		Redis: &conf.Data_Redis{Addr: mr.Addr()},
+		Bloom: &conf.Data_Bloom{SubjectBits: 1 << 16, CommentBits: 1 << 16, Hashes: 4},
+	})
+	// the keys not in the filter are not sampled and a rebuild does not wait
+	// for writes unless a test asks to
+	d.subjectBloom.verify, d.commentBloom.verify = 0, 0
+	d.subjectBloom.settle, d.commentBloom.settle = 0, 0
+	return d, mr
+}
+
+func TestBloomContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		d, _ := newBloomTestData(t)
+		for _, b := range []*bloomFilter{d.subjectBloom, d.commentBloom} {
+			if err := b.rebuild(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return repos(d)
+	})
+}
+
+func TestBloomFilter(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newBloomTestData(t)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		c       = &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	)
+	// the rows written before the filter exists are found by the rebuild
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(d.subjectBloom.key) {
+		t.Fatal("created a partial filter")
+	}
+	for _, b := range []*bloomFilter{d.subjectBloom, d.commentBloom} {
+		if err := b.rebuild(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := subject.GetSubject(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetComment(ctx, c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// a row missing from the filter is not looked up
+	_, err := d.db.ExecContext(ctx, `INSERT INTO comment_subject
+		(obj_id, obj_type, member_id, count, root_count, all_count, state, create_time, update_time)
+		VALUES (2, 1, 9, 0, 0, 0, 0, ?, ?)`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = subject.GetSubject(ctx, 2, 1); err != biz.ErrSubjectNotFound {
+		t.Fatalf("got %v of a subject missing from the filter", err)
+	}
+	if _, err = repo.GetComment(ctx, c.ID+1000); err != biz.ErrCommentNotFound {
+		t.Fatalf("got %v of a missing comment", err)
+	}
+
+	// the new rows are added
+	if err = subject.CreateSubject(ctx, &biz.Subject{ObjID: 3, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = subject.GetSubject(ctx, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	reply := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 11, Root: c.ID, Parent: c.ID, Message: "reply"}
+	if err = repo.CreateComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.GetComment(ctx, reply.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// a lost filter falls back to the database and is rebuilt
+	mr.Del(d.subjectBloom.key)
+	if _, err = subject.GetSubject(ctx, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !mr.Exists(d.subjectBloom.key) {
+		if time.Now().After(deadline) {
+			t.Fatal("lost filter not rebuilt")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, id := range []int64{1, 2, 3} {
+		if ok, err := d.subjectBloom.mayContain(ctx, subjectBloomKey(id, 1)); err != nil || !ok {
+			t.Fatalf("subject %d missing from the rebuilt filter: %v", id, err)
+		}
+	}
+}
+
+func TestBloomRebuildWrites(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		d, _ = newBloomTestData(t)
+		b    = d.commentBloom
+	)
+	// a key added while the filter is rebuilt is kept by the rebuild
+	b.scan = func(ctx context.Context, fn func(keys []string) error) error {
+		if err := b.add(ctx, "added"); err != nil {
+			return err
+		}
+		return fn([]string{"scanned"})
+	}
+	if err := b.rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"added", "scanned"} {
+		if ok, err := b.mayContain(ctx, key); err != nil || !ok {
+			t.Fatalf("%s missing from the filter: %v", key, err)
+		}
+	}
+}
+
+func TestBloomRebuildInFlight(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		d, mr = newBloomTestData(t)
+		b     = d.subjectBloom
+		key   = subjectBloomKey(5, 1)
+		done  = make(chan error, 1)
+	)
+	if err := b.rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// a write adds its key before the marker is set and commits after it
+	b.settle = 500 * time.Millisecond
+	if err := b.add(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	go func() { done <- b.rebuild(ctx) }()
+	for deadline := time.Now().Add(b.settle); !mr.Exists(b.key + ":building"); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the rebuild did not set the marker")
+		}
+	}
+	_, err := d.db.Exec(`INSERT INTO comment_subject (obj_id, obj_type, member_id, count, root_count, all_count,
+		state, create_time, update_time) VALUES (5, 1, 9, 0, 0, 0, 0, ?, ?)`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := b.mayContain(ctx, key); err != nil || !ok {
+		t.Fatalf("lost the key committed during the rebuild: %v", err)
+	}
+}
+
+func TestBloomVerify(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newBloomTestData(t)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		c       = &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.commentBloom.rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the missing ids are left out of a batch get
+	cs, err := repo.ListCommentByID(ctx, []int64{c.ID, c.ID + 1000})
+	if err != nil || len(cs) != 1 || cs[0].ID != c.ID {
+		t.Fatalf("got %d comments, error %v", len(cs), err)
+	}
+
+	// a key lost by redis is found by the sample and the filter is dropped
+	if err = d.rdb.SetBit(ctx, d.commentBloom.key, d.commentBloom.offsets(commentBloomKey(c.ID))[0], 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	d.commentBloom.verify = 1
+	d.commentBloom.started = time.Now()
+	if _, err = repo.GetComment(ctx, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(d.commentBloom.key) {
+		t.Fatal("kept a filter missing a comment")
+	}
+	if _, err = repo.GetComment(ctx, c.ID+1000); err != biz.ErrCommentNotFound {
+		t.Fatalf("got %v of a missing comment", err)
+	}
+}
+
+func TestBloomPeriodicRebuild(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		d, mr = newBloomTestData(t)
+		b     = d.subjectBloom
+	)
+	if err := b.rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(b.key + ":built"); ttl != defaultBloomRebuild {
+		t.Fatalf("got rebuild marker ttl %v", ttl)
+	}
+
+	// a subject written without the filter is found once the filter is due
+	_, err := d.db.ExecContext(ctx, `INSERT INTO comment_subject
+		(obj_id, obj_type, member_id, count, root_count, all_count, state, create_time, update_time)
+		VALUES (2, 1, 9, 0, 0, 0, 0, ?, ?)`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(defaultBloomRebuild)
+	if ok, err := b.mayContain(ctx, subjectBloomKey(2, 1)); err != nil || ok {
+		t.Fatalf("got %v of a subject missing from the filter, error %v", ok, err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !mr.Exists(b.key + ":built") {
+		if time.Now().After(deadline) {
+			t.Fatal("filter not rebuilt when due")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if ok, err := b.mayContain(ctx, subjectBloomKey(2, 1)); err != nil || !ok {
+		t.Fatalf("subject missing from the rebuilt filter: %v", err)
+	}
+}
@@ -0,0 +1,682 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// 评论缓存, 未配置 redis 时读写都直接访问数据库:
+//   comment:subject:{obj_type}:{obj_id}:{sort} 主题下正常根评论ID的有序集合, floor 按楼层, hot 按热度
+//   comment:reply:{root}:{sort}                根评论下正常回复ID的有序集合, floor 按楼层, hot 按热度
+//   comment:content:{id}                       评论内容的哈希
+//   {有序集合}:empty                            有序集合为空的标记
+//   {有序集合}:loading                          正在重建的次数
+//   {有序集合}:pending                          重建期间新增或改变分数的评论, 重建完成时覆盖
+//   {有序集合}:removed                          重建期间隐藏的评论, 重建完成时删除
+//   comment:subject:{obj_type}:{obj_id}:none    主题不存在的标记
+// 热度的分数为热度乘以 hotFloorSpan 加上楼层, 热度相同时按楼层排序, 点赞和回复
+// 改变热度后从数据库读取最新的热度写入.
+// 有序集合未命中时从数据库整体重建, 同一个有序集合并发的重建合并为一次,
+// 写入只修改已存在的有序集合, 不会留下残缺的索引, 重建期间的写入另外
+// 记录并在重建完成时合并, 不会被重建时读取的旧数据覆盖.
+// 编辑后删除缓存的内容, 创建主题后删除主题不存在的标记, 都在延迟一段时间后再删除
+// 一次, 丢弃写入前读取并在第一次删除后写回的旧值.
+// 计数和状态总是从数据库读取, 缓存只保存排序和内容.
+// 空和不存在的标记使用较短的 emptyTTL, 所有过期时间随机增加 jitter 比例避免同时过期.
+
+const (
+	// defaultCacheTTL is the ttl of the cache when it is not configured.
+	defaultCacheTTL = time.Hour
+	// defaultEmptyTTL is the ttl of the empty and missing markers when it is not configured.
+	defaultEmptyTTL = 10 * time.Second
+	// rebuildTTL is the longest a rebuild of a sorted set is marked.
+	rebuildTTL = 10 * time.Second
+	// rebuildTimeout is the timeout of a rebuild, shorter than rebuildTTL.
+	rebuildTimeout = 5 * time.Second
+	// redeleteDelay is the delay of the second delete of a stale key.
+	redeleteDelay = 2 * time.Second
+	// redeleteTimeout is the timeout of the second delete.
+	redeleteTimeout = time.Second
+	// hotFloorSpan is the span of the floors of a hot count in the hot score.
+	hotFloorSpan = 1 << 32
+)
+
+// addIndex adds the comment to the sorted set only when the set is cached,
+// and to the pending comments of the rebuilds in progress.
+var addIndex = redis.NewScript(`
+local added = 0
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	added = redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+end
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	redis.call('ZADD', KEYS[3], ARGV[1], ARGV[2])
+	redis.call('PEXPIRE', KEYS[3], ARGV[3])
+	redis.call('SREM', KEYS[4], ARGV[2])
+end
+return added`)
+
+// scoreIndex updates the score of a comment in the sorted set, and in the
+// pending comments of the rebuilds in progress unless it is removed meanwhile.
+var scoreIndex = redis.NewScript(`
+redis.call('ZADD', KEYS[1], 'XX', ARGV[1], ARGV[2])
+if redis.call('EXISTS', KEYS[2]) == 1 and redis.call('SISMEMBER', KEYS[4], ARGV[2]) == 0 then
+	redis.call('ZADD', KEYS[3], ARGV[1], ARGV[2])
+	redis.call('PEXPIRE', KEYS[3], ARGV[3])
+end
+return 0`)
+
+// removeIndex removes the comment from the sorted set, and from the
+// members of the rebuilds in progress.
+var removeIndex = redis.NewScript(`
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	redis.call('SADD', KEYS[4], ARGV[1])
+	redis.call('PEXPIRE', KEYS[4], ARGV[2])
+end
+return 0`)
+
+// setIndex replaces the sorted set with the members rebuilt, overridden by
+// the comments added or scored and without the comments removed meanwhile,
+// or caches the empty marker. The changes are kept until the last rebuild
+// in progress ends.
+var setIndex = redis.NewScript(`
+redis.call('DEL', KEYS[1])
+for i = 3, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+local pending = redis.call('ZRANGE', KEYS[4], 0, -1, 'WITHSCORES')
+for i = 1, #pending, 2 do
+	redis.call('ZADD', KEYS[1], pending[i + 1], pending[i])
+end
+for _, member in ipairs(redis.call('SMEMBERS', KEYS[5])) do
+	redis.call('ZREM', KEYS[1], member)
+end
+if redis.call('DECR', KEYS[3]) <= 0 then
+	redis.call('DEL', KEYS[3], KEYS[4], KEYS[5])
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('DEL', KEYS[2])
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	return 1
+end
+redis.call('SET', KEYS[2], 1, 'PX', ARGV[2])
+return 0`)
+
+// endRebuild ends a failed rebuild.
+var endRebuild = redis.NewScript(`
+if redis.call('DECR', KEYS[1]) <= 0 then
+	redis.call('DEL', KEYS[1], KEYS[2], KEYS[3])
+end
+return 0`)
+
+func subjectIndexKey(objID int64, objType int32, sort int8) string {
+	return fmt.Sprintf("comment:subject:%d:%d:%s", objType, objID, sortName(sort))
+}
+
+func replyIndexKey(root int64, sort int8) string {
+	return fmt.Sprintf("comment:reply:%d:%s", root, sortName(sort))
+}
+
+// indexKey returns the key of the sorted set listing a comment of the subject under root.
+func indexKey(objID int64, objType int32, root int64, sort int8) string {
+	if root != 0 {
+		return replyIndexKey(root, sort)
+	}
+	return subjectIndexKey(objID, objType, sort)
+}
+
+func sortName(sort int8) string {
+	if sort == biz.SortHot {
+		return "hot"
+	}
+	return "floor"
+}
+
+// indexKeys returns the keys of the sorted set and of its rebuild.
+func indexKeys(key string) []string {
+	return []string{key, loadingKey(key), pendingKey(key), removedKey(key)}
+}
+
+// hotScore is the score of a comment in the hot order.
+func hotScore(hot, floor int64) float64 {
+	return float64(hot)*hotFloorSpan + float64(floor)
+}
+
+func contentKey(id int64) string {
+	return fmt.Sprintf("comment:content:%d", id)
+}
+
+func emptyKey(key string) string {
+	return key + ":empty"
+}
+
+func loadingKey(key string) string {
+	return key + ":loading"
+}
+
+func pendingKey(key string) string {
+	return key + ":pending"
+}
+
+func removedKey(key string) string {
+	return key + ":removed"
+}
+
+func noSubjectKey(objID int64, objType int32) string {
+	return fmt.Sprintf("comment:subject:%d:%d:none", objType, objID)
+}
+
+// expiry returns ttl increased by a random jitter.
+func (d *Data) expiry(ttl time.Duration) time.Duration {
+	if d.jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Float64()*d.jitter*float64(ttl))
+}
+
+// rangeIndex returns the ids on the page of the cached sorted set and
+// refreshes its ttl, ok is false when the set is not cached.
+func (d *Data) rangeIndex(ctx context.Context, key string, desc bool, offset, limit int) (ids []int64, ok bool, err error) {
+	var (
+		pipe   = d.rdb.Pipeline()
+		expire = pipe.Expire(ctx, key, d.expiry(d.ttl))
+		empty  = pipe.Exists(ctx, emptyKey(key))
+		start  = int64(offset)
+		stop   = int64(offset + limit - 1)
+		page   *redis.StringSliceCmd
+	)
+	if desc {
+		page = pipe.ZRevRange(ctx, key, start, stop)
+	} else {
+		page = pipe.ZRange(ctx, key, start, stop)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+	if !expire.Val() {
+		return nil, empty.Val() == 1, nil
+	}
+	ids = make([]int64, 0, len(page.Val()))
+	for _, m := range page.Val() {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			return nil, false, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, true, nil
+}
+
+// rangeOrLoad returns the page of the cached sorted set, load reads the
+// members to rebuild the set on a miss, the concurrent misses of the same
+// set share one rebuild. The rebuild runs detached from the caller, so a
+// canceled caller neither fails the rebuild shared with the others nor
+// waits for it.
+func (d *Data) rangeOrLoad(ctx context.Context, key string, desc bool, offset, limit int, load func(ctx context.Context) ([]*redis.Z, error)) ([]int64, error) {
+	ids, ok, err := d.rangeIndex(ctx, key, desc, offset, limit)
+	if err != nil || ok {
+		return ids, err
+	}
+	ch := d.flight.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), rebuildTimeout)
+		defer cancel()
+		return nil, d.rebuildIndex(ctx, key, load)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+	}
+	ids, _, err = d.rangeIndex(ctx, key, desc, offset, limit)
+	return ids, err
+}
+
+// rebuildIndex marks the rebuild before load reads the database, so a
+// comment added after the read is merged into the set rebuilt.
+func (d *Data) rebuildIndex(ctx context.Context, key string, load func(ctx context.Context) ([]*redis.Z, error)) error {
+	pipe := d.rdb.TxPipeline()
+	pipe.Incr(ctx, loadingKey(key))
+	pipe.PExpire(ctx, loadingKey(key), rebuildTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	zs, err := load(ctx)
+	if err != nil {
+		if eerr := endRebuild.Run(ctx, d.rdb, indexKeys(key)[1:]).Err(); eerr != nil {
+			return eerr
+		}
+		return err
+	}
+	args := make([]interface{}, 0, 2+2*len(zs))
+	args = append(args, d.expiry(d.ttl).Milliseconds(), d.expiry(d.emptyTTL).Milliseconds())
+	for _, z := range zs {
+		args = append(args, z.Score, z.Member)
+	}
+	keys := []string{key, emptyKey(key), loadingKey(key), pendingKey(key), removedKey(key)}
+	return setIndex.Run(ctx, d.rdb, keys, args...).Err()
+}
+
+// cacheCreateComment adds a normal comment to the cached sorted sets and
+// updates the hot score of its root.
+func (d *Data) cacheCreateComment(ctx context.Context, c *biz.Comment) error {
+	if c.State != biz.CommentStateNormal {
+		return nil
+	}
+	d.local.evict(c.ObjID, c.ObjType, c.Root)
+	if d.rdb == nil {
+		return nil
+	}
+	pipe := d.rdb.Pipeline()
+	for sort, score := range map[int8]float64{
+		biz.SortFloor: float64(c.Floor),
+		biz.SortHot:   hotScore(int64(c.Like+c.RootCount), c.Floor),
+	} {
+		key := indexKey(c.ObjID, c.ObjType, c.Root, sort)
+		pipe.Del(ctx, emptyKey(key))
+		addIndex.Eval(ctx, pipe, indexKeys(key), score, c.ID, rebuildTTL.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	if c.Root == 0 {
+		return nil
+	}
+	return d.cacheHotComment(ctx, c.Root)
+}
+
+// cacheHideComment removes a comment no longer normal from the cached
+// sorted sets and updates the hot score of its root.
+func (d *Data) cacheHideComment(ctx context.Context, c *biz.Comment) error {
+	d.local.evict(c.ObjID, c.ObjType, c.Root)
+	if d.rdb == nil {
+		return nil
+	}
+	pipe := d.rdb.Pipeline()
+	for _, sort := range []int8{biz.SortFloor, biz.SortHot} {
+		removeIndex.Eval(ctx, pipe, indexKeys(indexKey(c.ObjID, c.ObjType, c.Root, sort)), c.ID, rebuildTTL.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	if c.Root == 0 {
+		return nil
+	}
+	return d.cacheHotComment(ctx, c.Root)
+}
+
+// cacheHotComment updates the score of a normal comment in the cached hot
+// order to the hot count read from the database after its likes or replies
+// changed, and drops the hot lists cached in process.
+func (d *Data) cacheHotComment(ctx context.Context, id int64) error {
+	if d.rdb == nil && d.local == nil {
+		return nil
+	}
+	var (
+		objID, root, floor, hot int64
+		objType                 int32
+		state                   int8
+	)
+	err := d.commentDB(id).QueryRowContext(ctx, `SELECT i.obj_id, i.obj_type, i.root, i.floor, i.state, `+hotCount+`
+		FROM comment_index i WHERE i.id = ?`, id).Scan(&objID, &objType, &root, &floor, &state, &hot)
+	if err == sql.ErrNoRows || err == nil && state != biz.CommentStateNormal {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d.local.evictSort(objID, objType, root, biz.SortHot)
+	if d.rdb == nil {
+		return nil
+	}
+	key := indexKey(objID, objType, root, biz.SortHot)
+	return scoreIndex.Run(ctx, d.rdb, indexKeys(key), hotScore(hot, floor), id, rebuildTTL.Milliseconds()).Err()
+}
+
+// delTwice deletes the key, and again after redeleteDelay to drop a value
+// read before the write and cached after the first delete.
+func (d *Data) delTwice(ctx context.Context, key string) error {
+	time.AfterFunc(redeleteDelay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), redeleteTimeout)
+		defer cancel()
+		if err := d.rdb.Del(ctx, key).Err(); err != nil {
+			d.log.Errorf("delete %s again: %v", key, err)
+		}
+	})
+	return d.rdb.Del(ctx, key).Err()
+}
+
+// cacheEditComment removes the cached content of the comment twice.
+func (d *Data) cacheEditComment(ctx context.Context, c *biz.Comment) error {
+	d.local.evict(c.ObjID, c.ObjType, c.Root)
+	if d.rdb == nil {
+		return nil
+	}
+	return d.delTwice(ctx, contentKey(c.ID))
+}
+
+// cachedNoSubject reports whether the subject is cached as missing.
+func (d *Data) cachedNoSubject(ctx context.Context, objID int64, objType int32) (bool, error) {
+	n, err := d.rdb.Exists(ctx, noSubjectKey(objID, objType)).Result()
+	return n == 1, err
+}
+
+// cacheNoSubject caches the subject as missing.
+func (d *Data) cacheNoSubject(ctx context.Context, objID int64, objType int32) error {
+	return d.rdb.Set(ctx, noSubjectKey(objID, objType), 1, d.expiry(d.emptyTTL)).Err()
+}
+
+// cacheCreateSubject removes the missing marker of the created subject
+// twice, a lookup missing the subject before the insert may set it again.
+func (d *Data) cacheCreateSubject(ctx context.Context, objID int64, objType int32) error {
+	if d.rdb == nil {
+		return nil
+	}
+	return d.delTwice(ctx, noSubjectKey(objID, objType))
+}
+
+// commentContent is a row of comment_content, it is cached as a hash.
+type commentContent struct {
+	id       int64
+	ats      string
+	mentions string
+	message  string
+	meta     string
+	content  []byte
+	ip       int64
+	platform string
+	device   string
+	edit     sql.NullTime
+}
+
+const contentColumns = `c.comment_id, c.at_member_ids, c.mentions, c.message, c.meta, c.content,
+	c.ip, c.platform, c.device, c.edit_time`
+
+func scanContent(s scanner) (*commentContent, error) {
+	ct := new(commentContent)
+	err := s.Scan(&ct.id, &ct.ats, &ct.mentions, &ct.message, &ct.meta, &ct.content,
+		&ct.ip, &ct.platform, &ct.device, &ct.edit)
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+func (ct *commentContent) fields() map[string]interface{} {
+	var edit int64
+	if ct.edit.Valid {
+		edit = ct.edit.Time.UnixNano()
+	}
+	return map[string]interface{}{
+		"ats":      ct.ats,
+		"mentions": ct.mentions,
+		"message":  ct.message,
+		"meta":     ct.meta,
+		"content":  ct.content,
+		"ip":       ct.ip,
+		"platform": ct.platform,
+		"device":   ct.device,
+		"edit":     edit,
+	}
+}
+
+func contentFromFields(id int64, m map[string]string) (*commentContent, error) {
+	ct := &commentContent{
+		id:       id,
+		ats:      m["ats"],
+		mentions: m["mentions"],
+		message:  m["message"],
+		meta:     m["meta"],
+		content:  []byte(m["content"]),
+		platform: m["platform"],
+		device:   m["device"],
+	}
+	var err error
+	if ct.ip, err = strconv.ParseInt(m["ip"], 10, 64); err != nil {
+		return nil, err
+	}
+	edit, err := strconv.ParseInt(m["edit"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if edit != 0 {
+		ct.edit = sql.NullTime{Time: time.Unix(0, edit), Valid: true}
+	}
+	return ct, nil
+}
+
+// fill sets the content of the comment.
+func (ct *commentContent) fill(c *biz.Comment) (err error) {
+	c.AtMemberIDs = splitIDs(ct.ats)
+	c.Mentions = splitMentions(ct.mentions)
+	c.Message = ct.message
+	c.Meta = ct.meta
+	c.IP = ct.ip
+	c.Platform = ct.platform
+	c.Device = ct.device
+	c.EditTime = ct.edit.Time
+	c.Content, err = unmarshalContent(ct.content)
+	return err
+}
+
+// getContents returns the cached contents of ids in one round trip and
+// refreshes their ttl, the missing ids are left out.
+func (d *Data) getContents(ctx context.Context, ids []int64) (map[int64]*commentContent, error) {
+	var (
+		pipe = d.rdb.Pipeline()
+		cmds = make([]*redis.StringStringMapCmd, 0, len(ids))
+	)
+	for _, id := range ids {
+		cmds = append(cmds, pipe.HGetAll(ctx, contentKey(id)))
+		pipe.Expire(ctx, contentKey(id), d.expiry(d.ttl))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	cts := make(map[int64]*commentContent, len(ids))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		ct, err := contentFromFields(ids[i], cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		cts[ct.id] = ct
+	}
+	return cts, nil
+}
+
+// setContents caches the contents in one round trip.
+func (d *Data) setContents(ctx context.Context, cts []*commentContent) error {
+	if len(cts) == 0 {
+		return nil
+	}
+	pipe := d.rdb.Pipeline()
+	for _, ct := range cts {
+		pipe.HSet(ctx, contentKey(ct.id), ct.fields())
+		pipe.Expire(ctx, contentKey(ct.id), d.expiry(d.ttl))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// cacheListComment lists the normal root comments of the subject in the sort from the cache.
+func (r *commentRepo) cacheListComment(ctx context.Context, objID int64, objType int32, sort int8, offset, limit int) ([]*biz.Comment, error) {
+	ids, err := r.data.rangeOrLoad(ctx, subjectIndexKey(objID, objType, sort), true, offset, limit, func(ctx context.Context) ([]*redis.Z, error) {
+		return r.loadSubjectIndex(ctx, objID, objType, sort)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.listCommentByIndex(ctx, ids)
+}
+
+// cacheListReply lists the normal replies of root in the sort from the cache.
+func (r *commentRepo) cacheListReply(ctx context.Context, root int64, sort int8, offset, limit int) ([]*biz.Comment, error) {
+	ids, err := r.data.rangeOrLoad(ctx, replyIndexKey(root, sort), sort == biz.SortHot, offset, limit, func(ctx context.Context) ([]*redis.Z, error) {
+		return r.loadReplyIndex(ctx, root, sort)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.listCommentByIndex(ctx, ids)
+}
+
+// loadSubjectIndex reads the normal root comments of the subject scored in the sort.
+func (r *commentRepo) loadSubjectIndex(ctx context.Context, objID int64, objType int32, sort int8) ([]*redis.Z, error) {
+	rows, err := r.data.subjectDB(objID, objType).QueryContext(ctx, `SELECT i.id, i.floor, `+hotCount+` FROM comment_index i
+		WHERE i.obj_id = ? AND i.obj_type = ? AND i.root = 0 AND i.state = ?`, objID, objType, biz.CommentStateNormal)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexScores(rows, sort)
+}
+
+// loadReplyIndex reads the normal replies of root scored in the sort.
+func (r *commentRepo) loadReplyIndex(ctx context.Context, root int64, sort int8) ([]*redis.Z, error) {
+	rows, err := r.data.commentDB(root).QueryContext(ctx, `SELECT i.id, i.floor, `+hotCount+` FROM comment_index i
+		WHERE i.root = ? AND i.state = ?`, root, biz.CommentStateNormal)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexScores(rows, sort)
+}
+
+func scanIndexScores(rows *sql.Rows, sort int8) ([]*redis.Z, error) {
+	defer rows.Close()
+	var zs []*redis.Z
+	for rows.Next() {
+		var id, floor, hot int64
+		if err := rows.Scan(&id, &floor, &hot); err != nil {
+			return nil, err
+		}
+		score := float64(floor)
+		if sort == biz.SortHot {
+			score = hotScore(hot, floor)
+		}
+		zs = append(zs, &redis.Z{Score: score, Member: id})
+	}
+	return zs, rows.Err()
+}
+
+// listCommentByIndex returns the normal comments of ids in order, the index
+// rows are read from the database and the contents from the cache.
+func (r *commentRepo) listCommentByIndex(ctx context.Context, ids []int64) ([]*biz.Comment, error) {
+	return r.listCommentByContent(ctx, ids, true)
+}
+
+// listCommentByContent returns the comments of ids in order, only the
+// normal ones when normal is set, the index rows are read from the database
+// and the contents from the cache, the missed contents in one query.
+func (r *commentRepo) listCommentByContent(ctx context.Context, ids []int64, normal bool) ([]*biz.Comment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	byID := make(map[int64]*biz.Comment, len(ids))
+	for db, args := range r.data.groupComment(ids) {
+		rows, err := db.QueryContext(ctx, `SELECT `+indexColumns+` FROM comment_index i
+			WHERE i.id IN (`+placeholders(len(args))+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		cs, err := scanIndexes(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			byID[c.ID] = c
+		}
+	}
+
+	cts, err := r.data.getContents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var missed []int64
+	for _, id := range ids {
+		if _, ok := cts[id]; !ok && byID[id] != nil {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) != 0 {
+		loaded, err := r.loadContents(ctx, missed)
+		if err != nil {
+			return nil, err
+		}
+		if err = r.data.setContents(ctx, loaded); err != nil {
+			return nil, err
+		}
+		for _, ct := range loaded {
+			cts[ct.id] = ct
+		}
+	}
+
+	// a comment hidden meanwhile may still be in the sorted set
+	cs := make([]*biz.Comment, 0, len(ids))
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok || (normal && c.State != biz.CommentStateNormal) || cts[id] == nil {
+			continue
+		}
+		if err = cts[id].fill(c); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, r.data.loadAttachments(ctx, cs)
+}
+
+// loadContents reads the contents of ids from the database.
+func (r *commentRepo) loadContents(ctx context.Context, ids []int64) ([]*commentContent, error) {
+	var cts []*commentContent
+	for db, args := range r.data.groupComment(ids) {
+		rows, err := db.QueryContext(ctx, `SELECT `+contentColumns+` FROM comment_content c
+			WHERE c.comment_id IN (`+placeholders(len(args))+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		shard, err := scanContents(rows)
+		if err != nil {
+			return nil, err
+		}
+		cts = append(cts, shard...)
+	}
+	return cts, nil
+}
+
+func scanIndexes(rows *sql.Rows) ([]*biz.Comment, error) {
+	defer rows.Close()
+	var cs []*biz.Comment
+	for rows.Next() {
+		c := new(biz.Comment)
+		if err := rows.Scan(indexDest(c)...); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, rows.Err()
+}
+
+func scanContents(rows *sql.Rows) ([]*commentContent, error) {
+	defer rows.Close()
+	var cts []*commentContent
+	for rows.Next() {
+		ct, err := scanContent(rows)
+		if err != nil {
+			return nil, err
+		}
+		cts = append(cts, ct)
+	}
+	return cts, rows.Err()
+}
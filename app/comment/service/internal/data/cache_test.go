@@ -0,0 +1,521 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const testCacheTTL = time.Minute
+
+// newCachedTestData opens a migrated SQLite database cached by an embedded
+// redis, the ttl is testCacheTTL without jitter when c is nil.
+func newCachedTestData(t *testing.T, c *conf.Data_Redis) (*Data, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	if c == nil {
+		c = &conf.Data_Redis{Ttl: durationpb.New(testCacheTTL)}
+	}
+	c.Addr = mr.Addr()
+	return openTestData(t, &conf.Data{Redis: c}), mr
+}
+
+func TestCachedContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		d, _ := newCachedTestData(t, nil)
+		return repos(d)
+	})
+}
+
+func TestCacheListComment(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		d, mr    = newCachedTestData(t, nil)
+		subject  = NewSubjectRepo(d, log.DefaultLogger)
+		repo     = NewCommentRepo(d, log.DefaultLogger)
+		like     = NewLikeRepo(d, log.DefaultLogger)
+		floorKey = subjectIndexKey(1, 1, biz.SortFloor)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	var roots []*biz.Comment
+	for i := 0; i < 3; i++ {
+		c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: int64(10 + i), Message: "root " + strconv.Itoa(i)}
+		if err := repo.CreateComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, c)
+	}
+	// nothing is cached until read
+	if mr.Exists(floorKey) {
+		t.Fatal("cached the index before the first read")
+	}
+
+	cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 2 || cs[0].ID != roots[2].ID || cs[1].ID != roots[1].ID || cs[0].Message != "root 2" {
+		t.Fatalf("got %d comments", len(cs))
+	}
+	if ids, _ := mr.ZMembers(floorKey); len(ids) != 3 {
+		t.Fatalf("cached %d comments in the index", len(ids))
+	}
+	if !mr.Exists(contentKey(roots[2].ID)) || mr.Exists(contentKey(roots[0].ID)) {
+		t.Fatal("cached the contents not on the page")
+	}
+
+	// a read refreshes the ttl
+	mr.FastForward(testCacheTTL / 2)
+	if _, err = repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(floorKey); ttl != testCacheTTL {
+		t.Fatalf("got index ttl %v after read", ttl)
+	}
+	if ttl := mr.TTL(contentKey(roots[2].ID)); ttl != testCacheTTL {
+		t.Fatalf("got content ttl %v after read", ttl)
+	}
+
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 13, Message: "root 3"}
+	if err = repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	roots = append(roots, c)
+	if err = repo.DeleteComment(ctx, roots[2]); err != nil {
+		t.Fatal(err)
+	}
+	roots[1].Message = "edited"
+	if err = repo.EditComment(ctx, roots[1]); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(contentKey(roots[1].ID)) {
+		t.Fatal("kept the content of an edited comment")
+	}
+	if err = like.SaveLike(ctx, &biz.Like{CommentID: roots[0].ID, MemberID: 20, Action: biz.LikeActionLike}); err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err = repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 3 || cs[0].ID != roots[3].ID || cs[1].Message != "edited" || cs[2].Like != 1 {
+		t.Fatalf("got %d comments after writes", len(cs))
+	}
+
+	// a comment hidden without the cache is left out
+	if _, err = d.db.Exec(`UPDATE comment_index SET state = ? WHERE id = ?`, biz.CommentStateRemoved, roots[3].ID); err != nil {
+		t.Fatal(err)
+	}
+	if cs, err = repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10); err != nil || len(cs) != 2 {
+		t.Fatalf("got %d comments after a removal, error %v", len(cs), err)
+	}
+}
+
+func TestCacheHotScore(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		like    = NewLikeRepo(d, log.DefaultLogger)
+		hotKey  = subjectIndexKey(1, 1, biz.SortHot)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	root := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	if err := repo.CreateComment(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ListComment(ctx, 1, 1, biz.SortHot, nil, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	score := func() float64 {
+		t.Helper()
+		s, err := mr.ZScore(hotKey, strconv.FormatInt(root.ID, 10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	if s := score(); s != hotScore(0, root.Floor) {
+		t.Fatalf("got hot score %v after the rebuild", s)
+	}
+
+	if err := like.SaveLike(ctx, &biz.Like{CommentID: root.ID, MemberID: 20, Action: biz.LikeActionLike}); err != nil {
+		t.Fatal(err)
+	}
+	if s := score(); s != hotScore(1, root.Floor) {
+		t.Fatalf("got hot score %v after a like", s)
+	}
+	reply := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 11, Root: root.ID, Parent: root.ID, Message: "reply"}
+	if err := repo.CreateComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if s := score(); s != hotScore(2, root.Floor) {
+		t.Fatalf("got hot score %v after a reply", s)
+	}
+	if err := repo.DeleteComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if s := score(); s != hotScore(1, root.Floor) {
+		t.Fatalf("got hot score %v after the reply was deleted", s)
+	}
+}
+
+func TestCacheListReply(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		root    = &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateComment(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	var replies []*biz.Comment
+	for i := 0; i < 4; i++ {
+		c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: int64(11 + i%2), Root: root.ID, Parent: root.ID, Message: "reply"}
+		if err := repo.CreateComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, c)
+	}
+
+	cs, err := repo.ListReply(ctx, root.ID, biz.SortFloor, nil, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 2 || cs[0].ID != replies[1].ID || cs[1].ID != replies[2].ID {
+		t.Fatalf("got %d replies", len(cs))
+	}
+	if ids, _ := mr.ZMembers(replyIndexKey(root.ID, biz.SortFloor)); len(ids) != 4 {
+		t.Fatalf("cached %d replies", len(ids))
+	}
+	// the cache is not used with excludes
+	if cs, err = repo.ListReply(ctx, root.ID, biz.SortFloor, []int64{11}, 0, 10); err != nil || len(cs) != 2 || cs[0].ID != replies[1].ID {
+		t.Fatalf("got %d replies excluding member 11, error %v", len(cs), err)
+	}
+}
+
+func TestCacheUnavailable(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	mr.Close()
+	// writes and reads fall back to the database
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	if err := repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 1 || cs[0].ID != c.ID || cs[0].Message != "root" {
+		t.Fatalf("got %d comments without the cache", len(cs))
+	}
+}
+
+func TestCacheEmpty(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		d, mr    = newCachedTestData(t, nil)
+		subject  = NewSubjectRepo(d, log.DefaultLogger)
+		repo     = NewCommentRepo(d, log.DefaultLogger)
+		floorKey = subjectIndexKey(1, 1, biz.SortFloor)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+	if err != nil || len(cs) != 0 {
+		t.Fatalf("got %d comments of an empty subject, error %v", len(cs), err)
+	}
+	if ttl := mr.TTL(emptyKey(floorKey)); ttl != defaultEmptyTTL {
+		t.Fatalf("got empty marker ttl %v", ttl)
+	}
+	// the marker answers without the database
+	if _, err = d.db.Exec(`INSERT INTO comment_index (id, obj_id, obj_type, member_id, root, parent, reply_member_id,
+		floor, count, root_count, like_count, hate_count, state, create_time, update_time)
+		VALUES (100, 1, 1, 10, 0, 0, 0, 1, 0, 0, 0, 0, 0, ?, ?)`, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if cs, err = repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10); err != nil || len(cs) != 0 {
+		t.Fatalf("got %d comments with the empty marker, error %v", len(cs), err)
+	}
+
+	// a created comment removes the marker
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	if err = repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(emptyKey(floorKey)) {
+		t.Fatal("kept the empty marker after a comment")
+	}
+	if cs, err = repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10); err != nil || len(cs) != 1 || cs[0].ID != c.ID {
+		t.Fatalf("got %d comments after a comment, error %v", len(cs), err)
+	}
+}
+
+func TestCacheMissingSubject(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+	)
+	if _, err := subject.GetSubject(ctx, 1, 1); err != biz.ErrSubjectNotFound {
+		t.Fatalf("got error %v for a missing subject", err)
+	}
+	if !mr.Exists(noSubjectKey(1, 1)) {
+		t.Fatal("missing subject not cached")
+	}
+	if _, err := d.db.Exec(`INSERT INTO comment_subject (obj_id, obj_type, member_id, count, root_count, all_count,
+		state, create_time, update_time) VALUES (1, 1, 9, 0, 0, 0, 0, ?, ?)`, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subject.GetSubject(ctx, 1, 1); err != biz.ErrSubjectNotFound {
+		t.Fatalf("got error %v with the missing marker", err)
+	}
+	mr.FastForward(defaultEmptyTTL)
+	if _, err := subject.GetSubject(ctx, 1, 1); err != nil {
+		t.Fatalf("got error %v after the missing marker expired", err)
+	}
+
+	if _, err := subject.GetSubject(ctx, 2, 1); err != biz.ErrSubjectNotFound {
+		t.Fatalf("got error %v for a missing subject", err)
+	}
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 2, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subject.GetSubject(ctx, 2, 1); err != nil {
+		t.Fatalf("got error %v for a created subject", err)
+	}
+
+	// a lookup that missed before the create sets the marker after its first delete
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 3, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.cacheNoSubject(ctx, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(redeleteDelay + time.Second); mr.Exists(noSubjectKey(3, 1)); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("kept the missing marker of a created subject")
+		}
+	}
+	if _, err := subject.GetSubject(ctx, 3, 1); err != nil {
+		t.Fatalf("got error %v for a created subject", err)
+	}
+}
+
+func TestCacheJitter(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, &conf.Data_Redis{Ttl: durationpb.New(testCacheTTL), Jitter: 0.5})
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for i := 0; i < 20; i++ {
+		c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+		if err := repo.CreateComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, c.ID)
+	}
+	if _, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 20); err != nil {
+		t.Fatal(err)
+	}
+	ttls := make(map[time.Duration]bool)
+	for _, id := range ids {
+		ttl := mr.TTL(contentKey(id))
+		if ttl < testCacheTTL || ttl > testCacheTTL*3/2 {
+			t.Fatalf("got ttl %v out of the jitter", ttl)
+		}
+		ttls[ttl] = true
+	}
+	if len(ttls) == 1 {
+		t.Fatal("got the same ttl for all the contents")
+	}
+}
+
+// rebuildCounter counts the rebuilds of the sorted sets, and holds each
+// rebuild for a while so the concurrent misses run into it.
+type rebuildCounter struct {
+	n int32
+}
+
+func (h *rebuildCounter) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *rebuildCounter) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+func (h *rebuildCounter) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	for _, cmd := range cmds {
+		if cmd.Name() == "incr" {
+			atomic.AddInt32(&h.n, 1)
+			time.Sleep(100 * time.Millisecond)
+			break
+		}
+	}
+	return ctx, nil
+}
+
+func (h *rebuildCounter) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+func TestCacheStampede(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, _    = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		counter = new(rebuildCounter)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := repo.CreateComment(ctx, &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.rdb.AddHook(counter)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 20)
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+			if err == nil && len(cs) != 3 {
+				err = fmt.Errorf("got %d comments", len(cs))
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if counter.n != 1 {
+		t.Fatalf("rebuilt the index %d times", counter.n)
+	}
+}
+
+func TestCacheCreateDuringRebuild(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d, mr   = newCachedTestData(t, nil)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger).(*commentRepo)
+		key     = subjectIndexKey(1, 1, biz.SortFloor)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	old := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "old"}
+	if err := repo.CreateComment(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+	// the comment is created after the rebuild read the database
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 11, Message: "new"}
+	ids, err := d.rangeOrLoad(ctx, key, true, 0, 10, func(ctx context.Context) ([]*redis.Z, error) {
+		zs, err := repo.loadSubjectIndex(ctx, 1, 1, biz.SortFloor)
+		if err != nil {
+			return nil, err
+		}
+		return zs, repo.CreateComment(ctx, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != c.ID || ids[1] != old.ID {
+		t.Fatalf("got index %v after a create during the rebuild", ids)
+	}
+	if mr.Exists(loadingKey(key)) || mr.Exists(pendingKey(key)) {
+		t.Fatal("kept the rebuild marker")
+	}
+}
+
+func TestCacheRebuildDetached(t *testing.T) {
+	var (
+		d, mr   = newCachedTestData(t, nil)
+		key     = subjectIndexKey(1, 1, biz.SortFloor)
+		once    sync.Once
+		started = make(chan struct{})
+		release = make(chan struct{})
+		load    = func(ctx context.Context) ([]*redis.Z, error) {
+			once.Do(func() { close(started) })
+			<-release
+			return []*redis.Z{{Score: 1, Member: int64(7)}}, ctx.Err()
+		}
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := d.rangeOrLoad(ctx, key, true, 0, 10, load)
+		errc <- err
+	}()
+	<-started
+	// the caller who started the rebuild gives up
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Fatalf("got error %v of a canceled caller", err)
+	}
+
+	idc := make(chan []int64, 1)
+	go func() {
+		ids, err := d.rangeOrLoad(context.Background(), key, true, 0, 10, load)
+		if err != nil {
+			t.Error(err)
+		}
+		idc <- ids
+	}()
+	close(release)
+	if ids := <-idc; len(ids) != 1 || ids[0] != 7 {
+		t.Fatalf("got index %v of the shared rebuild", ids)
+	}
+	if !mr.Exists(key) {
+		t.Fatal("the rebuild failed with the caller")
+	}
+}
@@ -0,0 +1,665 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	indexColumns = `i.id, i.obj_id, i.obj_type, i.member_id, i.root, i.parent, i.reply_member_id,
+	i.floor, i.count, i.root_count, i.like_count, i.hate_count, i.state, i.create_time`
+	commentColumns = indexColumns + `,
+	c.at_member_ids, c.mentions, c.message, c.meta, c.content, c.ip, c.platform, c.device, c.edit_time`
+)
+
+type commentRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewCommentRepo .
+func NewCommentRepo(data *Data, logger log.Logger) biz.CommentRepo {
+	return &commentRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *commentRepo) CreateComment(ctx context.Context, c *biz.Comment) error {
+	now := time.Now()
+	// only normal comments are counted, the floor is allocated anyway
+	var incr int32
+	if c.State == biz.CommentStateNormal {
+		incr = 1
+	}
+	content, err := marshalContent(c.Content)
+	if err != nil {
+		return err
+	}
+	var mentions string
+	mentions, c.Mentions = joinMentions(c.Mentions)
+	shard := r.data.subjectShard(c.ObjID, c.ObjType)
+	ctx, cancel := r.data.commentBloom.writeContext(ctx)
+	defer cancel()
+	err = r.data.crossTx(ctx, shard, func(main, tx *sql.Tx) (err error) {
+		subject := &counterDelta{objID: c.ObjID, objType: c.ObjType, allCount: incr}
+		if c.Root == 0 {
+			subject.rootCount = incr
+			_, err = tx.ExecContext(ctx, `UPDATE comment_subject SET count = count + 1, update_time = ?
+				WHERE obj_id = ? AND obj_type = ?`, now, c.ObjID, c.ObjType)
+			if err != nil {
+				return err
+			}
+			if err = r.data.incrCounters(ctx, tx, subject); err != nil {
+				return err
+			}
+			err = tx.QueryRowContext(ctx, `SELECT count FROM comment_subject WHERE obj_id = ? AND obj_type = ?`,
+				c.ObjID, c.ObjType).Scan(&c.Floor)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE comment_index SET count = count + 1, update_time = ? WHERE id = ?`,
+				now, c.Root)
+			if err != nil {
+				return err
+			}
+			root := &counterDelta{commentID: c.Root, objID: c.ObjID, objType: c.ObjType, rootCount: incr}
+			if err = r.data.incrCounters(ctx, tx, subject, root); err != nil {
+				return err
+			}
+			err = tx.QueryRowContext(ctx, `SELECT count FROM comment_index WHERE id = ?`, c.Root).Scan(&c.Floor)
+		}
+		if err != nil {
+			return err
+		}
+
+		if c.ID, err = r.data.nextCommentID(ctx, tx, shard); err != nil {
+			return err
+		}
+		if err = r.data.commentBloom.add(ctx, commentBloomKey(c.ID)); err != nil {
+			r.log.Errorf("add comment %d to bloom filter: %v", c.ID, err)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_index
+			(id, obj_id, obj_type, member_id, root, parent, reply_member_id, floor, count, root_count,
+			like_count, hate_count, state, create_time, update_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, ?, ?, ?)`,
+			c.ID, c.ObjID, c.ObjType, c.MemberID, c.Root, c.Parent, c.ReplyMemberID, c.Floor, c.State, now, now)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_content
+			(comment_id, at_member_ids, mentions, message, meta, content, ip, platform, device, create_time, update_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.ID, joinIDs(c.AtMemberIDs), mentions, c.Message, c.Meta, content, c.IP, c.Platform, c.Device, now, now)
+		if err != nil {
+			return err
+		}
+		if err = bindAttachments(ctx, main, c.ID, c.Attachments); err != nil {
+			return err
+		}
+		if c.State == biz.CommentStatePending {
+			if err = enqueueModeration(ctx, main, c, biz.ModerationSourceSpam, now); err != nil {
+				return err
+			}
+		}
+		c.CreateTime = now
+		var source int8
+		if c.State == biz.CommentStatePending {
+			source = biz.ModerationSourceSpam
+		}
+		var owner int64
+		err = tx.QueryRowContext(ctx, `SELECT member_id FROM comment_subject WHERE obj_id = ? AND obj_type = ?`,
+			c.ObjID, c.ObjType).Scan(&owner)
+		if err != nil {
+			return err
+		}
+		attachments := make([]int64, 0, len(c.Attachments))
+		for _, a := range c.Attachments {
+			attachments = append(attachments, a.ID)
+		}
+		return addEvent(ctx, tx, &v1.Event{Event: &v1.Event_CommentCreated{CommentCreated: &v1.CommentCreated{
+			CommentId:        c.ID,
+			ObjId:            c.ObjID,
+			ObjType:          c.ObjType,
+			MemberId:         c.MemberID,
+			Root:             c.Root,
+			Parent:           c.Parent,
+			ReplyMemberId:    c.ReplyMemberID,
+			AtMemberIds:      c.AtMemberIDs,
+			State:            int32(c.State),
+			SubjectMemberId:  owner,
+			AttachmentIds:    attachments,
+			ModerationSource: int32(source),
+		}}})
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.data.cacheCreateComment(ctx, c); err != nil {
+		r.log.Errorf("cache comment %d: %v", c.ID, err)
+	}
+	return nil
+}
+
+func (r *commentRepo) GetComment(ctx context.Context, id int64) (*biz.Comment, error) {
+	ok, err := r.data.commentBloom.mayContain(ctx, commentBloomKey(id))
+	if err != nil {
+		r.log.Errorf("check comment %d in bloom filter: %v", id, err)
+	}
+	if !ok {
+		return nil, biz.ErrCommentNotFound
+	}
+	row := r.data.commentDB(id).QueryRowContext(ctx, `SELECT `+commentColumns+`
+		FROM comment_index i JOIN comment_content c ON c.comment_id = i.id WHERE i.id = ?`, id)
+	c, err := scanComment(row)
+	if err == sql.ErrNoRows {
+		return nil, biz.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = r.data.mergeCommentDeltas(ctx, []*biz.Comment{c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *commentRepo) ListCommentByID(ctx context.Context, ids []int64) ([]*biz.Comment, error) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, commentBloomKey(id))
+	}
+	found, err := r.data.commentBloom.mayContainAll(ctx, keys)
+	if err != nil {
+		r.log.Errorf("check comments in bloom filter: %v", err)
+	}
+	known := make([]int64, 0, len(ids))
+	for i, id := range ids {
+		if found[i] {
+			known = append(known, id)
+		}
+	}
+	if ids = known; len(ids) == 0 {
+		return nil, nil
+	}
+	var cs []*biz.Comment
+	if r.data.rdb != nil {
+		if cs, err = r.listCommentByContent(ctx, ids, false); err != nil {
+			r.log.Errorf("list comments from cache: %v", err)
+		}
+	}
+	if r.data.rdb == nil || err != nil {
+		if cs, err = r.listCommentByID(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+	if err = r.data.mergeCommentDeltas(ctx, cs); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// listCommentByID reads the comments of ids with their attachments from the database.
+func (r *commentRepo) listCommentByID(ctx context.Context, ids []int64) ([]*biz.Comment, error) {
+	var cs []*biz.Comment
+	for db, args := range r.data.groupComment(ids) {
+		rows, err := db.QueryContext(ctx, `SELECT `+commentColumns+`
+			FROM comment_index i JOIN comment_content c ON c.comment_id = i.id
+			WHERE i.id IN (`+placeholders(len(args))+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		shard, err := scanComments(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, shard...)
+	}
+	return cs, r.data.loadAttachments(ctx, cs)
+}
+
+func (r *commentRepo) DeleteComment(ctx context.Context, c *biz.Comment) error {
+	err := runTx(ctx, r.data.commentDB(c.ID), func(tx *sql.Tx) error {
+		ok, err := r.data.hideComment(ctx, tx, c, biz.CommentStateDeleted)
+		if err != nil || !ok {
+			return err
+		}
+		return addCommentChanged(ctx, tx, c, 0)
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.data.cacheHideComment(ctx, c); err != nil {
+		r.log.Errorf("uncache comment %d: %v", c.ID, err)
+	}
+	return nil
+}
+
+func (r *commentRepo) EditComment(ctx context.Context, c *biz.Comment) error {
+	now := time.Now()
+	content, err := marshalContent(c.Content)
+	if err != nil {
+		return err
+	}
+	var (
+		mentions   string
+		quarantine = c.State == biz.CommentStatePending
+		hidden     bool
+	)
+	mentions, c.Mentions = joinMentions(c.Mentions)
+	err = r.data.crossTx(ctx, r.data.commentShard(c.ID), func(main, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO comment_content_history
+			(comment_id, at_member_ids, mentions, message, meta, content, create_time)
+			SELECT comment_id, at_member_ids, mentions, message, meta, content, COALESCE(edit_time, create_time)
+			FROM comment_content WHERE comment_id = ?`, c.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE comment_content SET at_member_ids = ?, mentions = ?, message = ?, meta = ?,
+			content = ?, edit_time = ?, update_time = ? WHERE comment_id = ?`,
+			joinIDs(c.AtMemberIDs), mentions, c.Message, c.Meta, content, now, now, c.ID)
+		if err != nil {
+			return err
+		}
+		var source int8
+		if quarantine {
+			if hidden, err = r.data.hideComment(ctx, tx, c, biz.CommentStatePending); err != nil {
+				return err
+			}
+			if hidden {
+				source = biz.ModerationSourceSpam
+				if err = enqueueModeration(ctx, main, c, source, now); err != nil {
+					return err
+				}
+			}
+		}
+		c.EditTime = now
+		return addCommentChanged(ctx, tx, c, source)
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.data.cacheEditComment(ctx, c); err != nil {
+		r.log.Errorf("uncache content %d: %v", c.ID, err)
+	}
+	if hidden {
+		if err = r.data.cacheHideComment(ctx, c); err != nil {
+			r.log.Errorf("uncache comment %d: %v", c.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r *commentRepo) ListCommentVersion(ctx context.Context, id int64) ([]*biz.CommentVersion, error) {
+	rows, err := r.data.commentDB(id).QueryContext(ctx, `SELECT comment_id, at_member_ids, mentions, message, meta, content, create_time
+		FROM comment_content_history WHERE comment_id = ? ORDER BY id DESC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var vs []*biz.CommentVersion
+	for rows.Next() {
+		var (
+			v        = new(biz.CommentVersion)
+			ats      string
+			mentions string
+			content  []byte
+		)
+		err = rows.Scan(&v.CommentID, &ats, &mentions, &v.Message, &v.Meta, &content, &v.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		v.AtMemberIDs = splitIDs(ats)
+		v.Mentions = splitMentions(mentions)
+		if v.Content, err = unmarshalContent(content); err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, rows.Err()
+}
+
+func (r *commentRepo) ListComment(ctx context.Context, objID int64, objType int32, sort int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	// the caches are not filtered by member
+	key := subjectIndexKey(objID, objType, sort)
+	if len(excludes) == 0 {
+		if cs, ok := r.data.local.get(key, offset, limit); ok {
+			return cs, nil
+		}
+	}
+	cs, err := r.listComment(ctx, objID, objType, sort, excludes, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.data.mergeCommentDeltas(ctx, cs); err != nil {
+		return nil, err
+	}
+	if len(excludes) == 0 {
+		r.data.local.set(key, offset, limit, cs)
+	}
+	return cs, nil
+}
+
+func (r *commentRepo) listComment(ctx context.Context, objID int64, objType int32, sort int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	if r.data.rdb != nil && len(excludes) == 0 {
+		cs, err := r.cacheListComment(ctx, objID, objType, sort, offset, limit)
+		if err == nil {
+			return cs, nil
+		}
+		r.log.Errorf("list comment of subject %d %d from cache: %v", objID, objType, err)
+	}
+	args := []interface{}{objID, objType, biz.CommentStateNormal}
+	exclude := excludeMember(excludes, &args)
+	args = append(args, limit, offset)
+	rows, err := r.data.subjectDB(objID, objType).QueryContext(ctx, `SELECT `+commentColumns+`
+		FROM comment_index i JOIN comment_content c ON c.comment_id = i.id
+		WHERE i.obj_id = ? AND i.obj_type = ? AND i.root = 0 AND i.state = ?`+exclude+`
+		ORDER BY `+listOrder(sort, "DESC")+` LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := scanComments(rows)
+	if err != nil {
+		return nil, err
+	}
+	return cs, r.data.loadAttachments(ctx, cs)
+}
+
+func (r *commentRepo) ListReply(ctx context.Context, root int64, sort int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	key := replyIndexKey(root, sort)
+	if len(excludes) == 0 {
+		if cs, ok := r.data.local.get(key, offset, limit); ok {
+			return cs, nil
+		}
+	}
+	cs, err := r.listReply(ctx, root, sort, excludes, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.data.mergeCommentDeltas(ctx, cs); err != nil {
+		return nil, err
+	}
+	if len(excludes) == 0 {
+		r.data.local.set(key, offset, limit, cs)
+	}
+	return cs, nil
+}
+
+func (r *commentRepo) listReply(ctx context.Context, root int64, sort int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	if r.data.rdb != nil && len(excludes) == 0 {
+		cs, err := r.cacheListReply(ctx, root, sort, offset, limit)
+		if err == nil {
+			return cs, nil
+		}
+		r.log.Errorf("list reply of %d from cache: %v", root, err)
+	}
+	args := []interface{}{root, biz.CommentStateNormal}
+	exclude := excludeMember(excludes, &args)
+	args = append(args, limit, offset)
+	rows, err := r.data.commentDB(root).QueryContext(ctx, `SELECT `+commentColumns+`
+		FROM comment_index i JOIN comment_content c ON c.comment_id = i.id
+		WHERE i.root = ? AND i.state = ?`+exclude+`
+		ORDER BY `+listOrder(sort, "ASC")+` LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := scanComments(rows)
+	if err != nil {
+		return nil, err
+	}
+	return cs, r.data.loadAttachments(ctx, cs)
+}
+
+// hotCount is the hot count of the comment i, its likes and replies with
+// their pending changes.
+const hotCount = `(i.like_count + i.root_count + COALESCE((SELECT SUM(d.like_count + d.root_count)
+	FROM comment_counter_delta d WHERE d.comment_id = i.id), 0))`
+
+// listOrder returns the ORDER BY of the sort, floor is the direction of the floor order.
+func listOrder(sort int8, floor string) string {
+	if sort == biz.SortHot {
+		return hotCount + ` DESC, i.floor DESC`
+	}
+	return `i.floor ` + floor
+}
+
+func (r *commentRepo) ListMemberComment(ctx context.Context, f *biz.MemberCommentFilter, states []int8) ([]*biz.Comment, error) {
+	var (
+		where = `i.member_id = ? AND i.state IN (` + placeholders(len(states)) + `)`
+		args  = []interface{}{f.MemberID}
+	)
+	for _, st := range states {
+		args = append(args, st)
+	}
+	if f.ObjType != 0 {
+		where += ` AND i.obj_type = ?`
+		args = append(args, f.ObjType)
+	}
+	if !f.StartTime.IsZero() {
+		where += ` AND i.create_time >= ?`
+		args = append(args, f.StartTime)
+	}
+	if !f.EndTime.IsZero() {
+		where += ` AND i.create_time < ?`
+		args = append(args, f.EndTime)
+	}
+	if f.Cursor != 0 {
+		// the ids of the shards are not in time order, the page after the
+		// cursor starts at its create time
+		var last time.Time
+		err := r.data.commentDB(f.Cursor).QueryRowContext(ctx, `SELECT create_time FROM comment_index WHERE id = ?`,
+			f.Cursor).Scan(&last)
+		if err == sql.ErrNoRows {
+			return nil, biz.ErrCommentNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		where += ` AND (i.create_time < ? OR i.create_time = ? AND i.id < ?)`
+		args = append(args, last, last, f.Cursor)
+	}
+	args = append(args, f.Limit)
+	// the first limit comments of each shard database are merged
+	var cs []*biz.Comment
+	for _, db := range r.data.shardDBs {
+		rows, err := db.QueryContext(ctx, `SELECT `+commentColumns+`
+			FROM comment_index i JOIN comment_content c ON c.comment_id = i.id
+			WHERE `+where+` ORDER BY i.create_time DESC, i.id DESC LIMIT ?`, args...)
+		if err != nil {
+			return nil, err
+		}
+		shard, err := scanComments(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, shard...)
+	}
+	sort.Slice(cs, func(i, j int) bool { return newer(cs[i], cs[j]) })
+	if len(cs) > f.Limit {
+		cs = cs[:f.Limit]
+	}
+	if err := r.data.mergeCommentDeltas(ctx, cs); err != nil {
+		return nil, err
+	}
+	return cs, r.data.loadAttachments(ctx, cs)
+}
+
+// newer reports whether a is listed before b in the comments of a member.
+func newer(a, b *biz.Comment) bool {
+	if !a.CreateTime.Equal(b.CreateTime) {
+		return a.CreateTime.After(b.CreateTime)
+	}
+	return a.ID > b.ID
+}
+
+func (r *commentRepo) RankComment(ctx context.Context, objID int64, objType int32, floor int64, excludes []int64) (int32, error) {
+	args := []interface{}{objID, objType, biz.CommentStateNormal, floor}
+	exclude := excludeMember(excludes, &args)
+	var n int32
+	err := r.data.subjectDB(objID, objType).QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_index i
+		WHERE i.obj_id = ? AND i.obj_type = ? AND i.root = 0 AND i.state = ? AND i.floor > ?`+exclude,
+		args...).Scan(&n)
+	return n, err
+}
+
+func (r *commentRepo) RankReply(ctx context.Context, root, floor int64, excludes []int64) (int32, error) {
+	args := []interface{}{root, biz.CommentStateNormal, floor}
+	exclude := excludeMember(excludes, &args)
+	var n int32
+	err := r.data.commentDB(root).QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_index i
+		WHERE i.root = ? AND i.state = ? AND i.floor < ?`+exclude, args...).Scan(&n)
+	return n, err
+}
+
+func (r *commentRepo) CountComment(ctx context.Context, objID int64, objType int32, memberIDs []int64) (int32, error) {
+	if len(memberIDs) == 0 {
+		return 0, nil
+	}
+	args := []interface{}{objID, objType, biz.CommentStateNormal}
+	for _, id := range memberIDs {
+		args = append(args, id)
+	}
+	var n int32
+	err := r.data.subjectDB(objID, objType).QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_index
+		WHERE obj_id = ? AND obj_type = ? AND root = 0 AND state = ? AND member_id IN (`+placeholders(len(memberIDs))+`)`,
+		args...).Scan(&n)
+	return n, err
+}
+
+func (r *commentRepo) CountReply(ctx context.Context, roots []int64, memberIDs []int64) (map[int64]int32, error) {
+	counts := make(map[int64]int32, len(roots))
+	if len(roots) == 0 || len(memberIDs) == 0 {
+		return counts, nil
+	}
+	for db, ids := range r.data.groupComment(roots) {
+		args := append([]interface{}{biz.CommentStateNormal}, ids...)
+		for _, id := range memberIDs {
+			args = append(args, id)
+		}
+		rows, err := db.QueryContext(ctx, `SELECT root, COUNT(*) FROM comment_index
+			WHERE state = ? AND root IN (`+placeholders(len(ids))+`) AND member_id IN (`+placeholders(len(memberIDs))+`)
+			GROUP BY root`, args...)
+		if err != nil {
+			return nil, err
+		}
+		if err = scanCounts(rows, counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+func scanCounts(rows *sql.Rows, counts map[int64]int32) error {
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id int64
+			n  int32
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return err
+		}
+		counts[id] = n
+	}
+	return rows.Err()
+}
+
+// excludeMember returns the condition leaving out the comments of members and appends its arguments.
+func excludeMember(memberIDs []int64, args *[]interface{}) string {
+	if len(memberIDs) == 0 {
+		return ""
+	}
+	for _, id := range memberIDs {
+		*args = append(*args, id)
+	}
+	return ` AND i.member_id NOT IN (` + placeholders(len(memberIDs)) + `)`
+}
+
+// hideComment changes a normal comment to state and decreases the counts
+// of its subject and root, it reports false if the comment is not normal.
+func (d *Data) hideComment(ctx context.Context, tx *sql.Tx, c *biz.Comment, state int8) (bool, error) {
+	res, err := tx.ExecContext(ctx, `UPDATE comment_index SET state = ?, update_time = ? WHERE id = ? AND state = ?`,
+		state, time.Now(), c.ID, biz.CommentStateNormal)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	subject := &counterDelta{objID: c.ObjID, objType: c.ObjType, allCount: -1}
+	if c.Root == 0 {
+		subject.rootCount = -1
+		err = d.incrCounters(ctx, tx, subject)
+	} else {
+		err = d.incrCounters(ctx, tx, subject, &counterDelta{commentID: c.Root, objID: c.ObjID, objType: c.ObjType, rootCount: -1})
+	}
+	return err == nil, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanComment(s scanner) (*biz.Comment, error) {
+	var (
+		c        = new(biz.Comment)
+		ats      string
+		mentions string
+		content  []byte
+		edit     sql.NullTime
+	)
+	err := s.Scan(append(indexDest(c),
+		&ats, &mentions, &c.Message, &c.Meta, &content, &c.IP, &c.Platform, &c.Device, &edit)...)
+	if err != nil {
+		return nil, err
+	}
+	c.EditTime = edit.Time
+	c.AtMemberIDs = splitIDs(ats)
+	c.Mentions = splitMentions(mentions)
+	if c.Content, err = unmarshalContent(content); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// indexDest returns the scan destinations of indexColumns.
+func indexDest(c *biz.Comment) []interface{} {
+	return []interface{}{&c.ID, &c.ObjID, &c.ObjType, &c.MemberID, &c.Root, &c.Parent, &c.ReplyMemberID,
+		&c.Floor, &c.Count, &c.RootCount, &c.Like, &c.Hate, &c.State, &c.CreateTime}
+}
+
+func marshalContent(ct *v1.RichContent) ([]byte, error) {
+	if ct == nil {
+		return nil, nil
+	}
+	return proto.Marshal(ct)
+}
+
+func unmarshalContent(b []byte) (*v1.RichContent, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	ct := new(v1.RichContent)
+	if err := proto.Unmarshal(b, ct); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+func scanComments(rows *sql.Rows) ([]*biz.Comment, error) {
+	defer rows.Close()
+	var cs []*biz.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, rows.Err()
+}
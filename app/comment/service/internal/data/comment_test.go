@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func TestCommentRepo(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d       = newTestData(t)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	var roots []*biz.Comment
+	for i := 0; i < 3; i++ {
+		c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: int64(10 + i), Message: "root", AtMemberIDs: []int64{9}}
+		if err := repo.CreateComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Floor != int64(i+1) {
+			t.Fatalf("root %d got floor %d", i, c.Floor)
+		}
+		roots = append(roots, c)
+	}
+	reply := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 11, Root: roots[0].ID, Parent: roots[0].ID,
+		ReplyMemberID: 10, Message: "reply"}
+	if err := repo.CreateComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := subject.GetSubject(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count != 3 || s.RootCount != 3 || s.AllCount != 4 {
+		t.Fatalf("got subject counts %d %d %d", s.Count, s.RootCount, s.AllCount)
+	}
+	c, err := repo.GetComment(ctx, roots[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RootCount != 1 || c.Message != "root" || len(c.AtMemberIDs) != 1 || c.AtMemberIDs[0] != 9 {
+		t.Fatalf("got comment %+v", c)
+	}
+	if _, err = repo.GetComment(ctx, 100); err != biz.ErrCommentNotFound {
+		t.Fatalf("got error %v for a missing comment", err)
+	}
+
+	cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, []int64{11}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 2 || cs[0].ID != roots[2].ID || cs[1].ID != roots[0].ID {
+		t.Fatalf("got %d comments", len(cs))
+	}
+	rs, err := repo.ListReply(ctx, roots[0].ID, biz.SortFloor, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].ID != reply.ID || rs[0].ReplyMemberID != 10 {
+		t.Fatalf("got %d replies", len(rs))
+	}
+
+	if err = repo.DeleteComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = repo.GetComment(ctx, roots[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = subject.GetSubject(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if c.RootCount != 0 || s.AllCount != 3 {
+		t.Fatalf("got root count %d and all count %d after delete", c.RootCount, s.AllCount)
+	}
+
+	// a pending comment is queued for moderation with the comment
+	pending := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 12, Message: "spam", State: biz.CommentStatePending}
+	if err = repo.CreateComment(ctx, pending); err != nil {
+		t.Fatal(err)
+	}
+	var source int8
+	err = d.db.QueryRowContext(ctx, `SELECT source FROM comment_moderation WHERE comment_id = ?`, pending.ID).Scan(&source)
+	if err != nil || source != biz.ModerationSourceSpam {
+		t.Fatalf("got moderation source %d: %v", source, err)
+	}
+}
+
+func TestEditComment(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		d    = newTestData(t)
+		repo = NewCommentRepo(d, log.DefaultLogger)
+	)
+	if err := NewSubjectRepo(d, log.DefaultLogger).CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1}); err != nil {
+		t.Fatal(err)
+	}
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "v1"}
+	if err := repo.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"v2", "v3"} {
+		c.Message = msg
+		if err := repo.EditComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := repo.GetComment(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "v3" || got.EditTime.IsZero() {
+		t.Fatalf("got message %q edited at %v", got.Message, got.EditTime)
+	}
+	vs, err := repo.ListCommentVersion(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 2 || vs[0].Message != "v2" || vs[1].Message != "v1" {
+		t.Fatalf("got %d versions", len(vs))
+	}
+
+	// a quarantined edit hides the comment and queues it with the edit
+	c.Message, c.State = "spam", biz.CommentStatePending
+	if err = repo.EditComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.GetComment(ctx, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	var source int8
+	err = d.db.QueryRowContext(ctx, `SELECT source FROM comment_moderation WHERE comment_id = ?`, c.ID).Scan(&source)
+	if err != nil || got.State != biz.CommentStatePending || source != biz.ModerationSourceSpam {
+		t.Fatalf("got state %d moderation source %d: %v", got.State, source, err)
+	}
+}
@@ -0,0 +1,32 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+)
+
+func repos(d *Data) *repotest.Repos {
+	return &repotest.Repos{
+		Subject: NewSubjectRepo(d, log.DefaultLogger),
+		Comment: NewCommentRepo(d, log.DefaultLogger),
+		Like:    NewLikeRepo(d, log.DefaultLogger),
+	}
+}
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		return repos(newTestData(t))
+	})
+}
+
+func TestShardedContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		d, err := newShardedTestData(t, 4, 2, []int32{0, 1, 0, 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repos(d)
+	})
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// 点赞, 点踩和回复数在写入合并开启时不直接修改计数, 变化和写入在同一个事务中
+// 写入所在分片库的 comment_counter_delta, comment job 定期按评论和主题合并后
+// 批量写入计数并删除已合并的变化. 读取计数时加上还未合并的变化.
+// 楼层计数分配楼层, 总是直接修改.
+
+// counterDelta is a change of the counters of a comment, or of a subject
+// when commentID is 0.
+type counterDelta struct {
+	commentID int64
+	objID     int64
+	objType   int32
+	like      int32
+	hate      int32
+	rootCount int32 // 评论的现存回复数量或主题的现存根评论数量
+	allCount  int32 // 主题的现存评论总数
+}
+
+// incrCounters changes the counters in tx on the shard, or records the
+// changes for the comment job when the writes are coalesced.
+func (d *Data) incrCounters(ctx context.Context, tx *sql.Tx, deltas ...*counterDelta) error {
+	now := time.Now()
+	for _, c := range deltas {
+		if c.like == 0 && c.hate == 0 && c.rootCount == 0 && c.allCount == 0 {
+			continue
+		}
+		var err error
+		switch {
+		case d.coalesce:
+			_, err = tx.ExecContext(ctx, `INSERT INTO comment_counter_delta
+				(comment_id, obj_id, obj_type, like_count, hate_count, root_count, all_count, create_time)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				c.commentID, c.objID, c.objType, c.like, c.hate, c.rootCount, c.allCount, now)
+		case c.commentID != 0:
+			_, err = tx.ExecContext(ctx, `UPDATE comment_index SET like_count = like_count + ?,
+				hate_count = hate_count + ?, root_count = root_count + ?, update_time = ? WHERE id = ?`,
+				c.like, c.hate, c.rootCount, now, c.commentID)
+		default:
+			_, err = tx.ExecContext(ctx, `UPDATE comment_subject SET root_count = root_count + ?,
+				all_count = all_count + ?, update_time = ? WHERE obj_id = ? AND obj_type = ?`,
+				c.rootCount, c.allCount, now, c.objID, c.objType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCommentDeltas sets the counters of the comments to the stored
+// counters plus the pending changes. Both are read in one statement, a
+// merge by the comment job between two reads would count its changes twice
+// or not at all.
+func (d *Data) mergeCommentDeltas(ctx context.Context, cs []*biz.Comment) error {
+	if !d.coalesce || len(cs) == 0 {
+		return nil
+	}
+	byID := make(map[int64][]*biz.Comment, len(cs))
+	ids := make([]int64, 0, len(cs))
+	for _, c := range cs {
+		if _, ok := byID[c.ID]; !ok {
+			ids = append(ids, c.ID)
+		}
+		byID[c.ID] = append(byID[c.ID], c)
+	}
+	for db, args := range d.groupComment(ids) {
+		rows, err := db.QueryContext(ctx, `SELECT i.id, i.like_count + COALESCE(SUM(d.like_count), 0),
+			i.hate_count + COALESCE(SUM(d.hate_count), 0), i.root_count + COALESCE(SUM(d.root_count), 0)
+			FROM comment_index i LEFT JOIN comment_counter_delta d ON d.comment_id = i.id
+			WHERE i.id IN (`+placeholders(len(args))+`) GROUP BY i.id, i.like_count, i.hate_count, i.root_count`, args...)
+		if err != nil {
+			return err
+		}
+		err = scanDeltas(rows, func(id int64, like, hate, rootCount int32) {
+			for _, c := range byID[id] {
+				c.Like = like
+				c.Hate = hate
+				c.RootCount = rootCount
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSubjectDeltas sets the counters of the subjects to the stored
+// counters plus the pending changes, read in one statement.
+func (d *Data) mergeSubjectDeltas(ctx context.Context, ss ...*biz.Subject) error {
+	if !d.coalesce {
+		return nil
+	}
+	for _, s := range ss {
+		err := d.subjectDB(s.ObjID, s.ObjType).QueryRowContext(ctx, `SELECT
+			s.root_count + COALESCE(SUM(d.root_count), 0), s.all_count + COALESCE(SUM(d.all_count), 0)
+			FROM comment_subject s LEFT JOIN comment_counter_delta d
+			ON d.comment_id = 0 AND d.obj_id = s.obj_id AND d.obj_type = s.obj_type
+			WHERE s.obj_id = ? AND s.obj_type = ? GROUP BY s.id, s.root_count, s.all_count`,
+			s.ObjID, s.ObjType).Scan(&s.RootCount, &s.AllCount)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanDeltas(rows *sql.Rows, fn func(id int64, like, hate, rootCount int32)) error {
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id                    int64
+			like, hate, rootCount int32
+		)
+		if err := rows.Scan(&id, &like, &hate, &rootCount); err != nil {
+			return err
+		}
+		fn(id, like, hate, rootCount)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+)
+
+func newCoalescedTestData(t *testing.T) *Data {
+	return openTestData(t, &conf.Data{Counter: &conf.Data_Counter{Coalesce: true}})
+}
+
+func TestCoalescedContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		return repos(newCoalescedTestData(t))
+	})
+}
+
+func TestCoalescedCounters(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d       = newCoalescedTestData(t)
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		like    = NewLikeRepo(d, log.DefaultLogger)
+		root    = &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateComment(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	reply := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 11, Root: root.ID, Parent: root.ID, Message: "reply"}
+	if err := repo.CreateComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if err := like.SaveLike(ctx, &biz.Like{CommentID: root.ID, MemberID: 11, Action: biz.LikeActionLike}); err != nil {
+		t.Fatal(err)
+	}
+	if err := like.SaveLike(ctx, &biz.Like{CommentID: root.ID, MemberID: 12, Action: biz.LikeActionHate}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the counters are not written, only the floors
+	var floors, rootCount, likes int32
+	err := d.db.QueryRowContext(ctx, `SELECT s.count, s.root_count, i.like_count FROM comment_subject s
+		JOIN comment_index i ON i.id = ?`, root.ID).Scan(&floors, &rootCount, &likes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if floors != 1 || rootCount != 0 || likes != 0 {
+		t.Fatalf("got floors %d root count %d likes %d in the rows", floors, rootCount, likes)
+	}
+
+	check := func(rootCount, allCount, replies, like, hate int32) {
+		t.Helper()
+		s, err := subject.GetSubject(ctx, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.RootCount != rootCount || s.AllCount != allCount {
+			t.Fatalf("got subject counts %d %d, want %d %d", s.RootCount, s.AllCount, rootCount, allCount)
+		}
+		c, err := repo.GetComment(ctx, root.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.RootCount != replies || c.Like != like || c.Hate != hate {
+			t.Fatalf("got comment counts %d %d %d, want %d %d %d", c.RootCount, c.Like, c.Hate, replies, like, hate)
+		}
+		cs, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cs) != 1 || cs[0].RootCount != replies || cs[0].Like != like {
+			t.Fatalf("got %d listed comments", len(cs))
+		}
+	}
+	check(1, 2, 1, 1, 1)
+
+	if err = repo.DeleteComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if err = like.SaveLike(ctx, &biz.Like{CommentID: root.ID, MemberID: 11, Action: biz.LikeActionCancel}); err != nil {
+		t.Fatal(err)
+	}
+	check(1, 1, 0, 0, 1)
+}
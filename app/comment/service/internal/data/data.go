@@ -0,0 +1,273 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/wire"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"golang.org/x/sync/singleflight"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ProviderSet is data providers.
+var ProviderSet = wire.NewSet(NewData, NewSubjectRepo, NewCommentRepo, NewReportRepo, NewModerationRepo, NewRateLimitRepo, NewSpamRepo, NewBlockRepo, NewMuteRepo, NewMemberRepo, NewAttachmentRepo, NewAttachmentStorage,
+	NewLikeRepo, NewAuditRepo, NewErasureRepo)
+
+// Data .
+type Data struct {
+	db     *sql.DB
+	driver string // mysql 或 sqlite3
+
+	shards   int64     // 逻辑分片数
+	shardDBs []*sql.DB // 分片库
+	shardMap []*sql.DB // 逻辑分片所在的分片库
+
+	rdb      *redis.Client // 缓存, 为空时不使用缓存
+	ttl      time.Duration
+	emptyTTL time.Duration
+	jitter   float64
+	flight   singleflight.Group // 合并同一个有序集合的重建
+
+	local        *localCache   // 进程内热点缓存, 为空时不使用
+	invalidation *redis.PubSub // comment job 广播的主题变化
+
+	subjectBloom *bloomFilter // 已存在主题的布隆过滤器, 为空时不使用
+	commentBloom *bloomFilter // 已存在评论的布隆过滤器, 为空时不使用
+
+	coalesce bool // 计数的变化由 comment job 合并写入
+
+	sharedLimit bool // 限流的令牌桶保存在 redis
+
+	log *log.Helper
+}
+
+// NewData .
+func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
+	d := &Data{log: log.NewHelper(logger)}
+	cleanup := func() {
+		d.log.Info("closing the data resources")
+		for _, db := range d.databases() {
+			if err := db.Close(); err != nil {
+				log.NewHelper(logger).Error(err)
+			}
+		}
+		if d.invalidation != nil {
+			if err := d.invalidation.Close(); err != nil {
+				log.NewHelper(logger).Error(err)
+			}
+		}
+		if d.rdb != nil {
+			if err := d.rdb.Close(); err != nil {
+				log.NewHelper(logger).Error(err)
+			}
+		}
+	}
+	db, err := openDB(c.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.db, d.driver = db, c.Database.Driver
+	if err = d.openShards(c.Sharding); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if c.Redis != nil {
+		d.rdb = redis.NewClient(&redis.Options{
+			Network:      c.Redis.Network,
+			Addr:         c.Redis.Addr,
+			ReadTimeout:  c.Redis.ReadTimeout.AsDuration(),
+			WriteTimeout: c.Redis.WriteTimeout.AsDuration(),
+		})
+		d.ttl = c.Redis.Ttl.AsDuration()
+		if d.ttl <= 0 {
+			d.ttl = defaultCacheTTL
+		}
+		d.emptyTTL = c.Redis.EmptyTtl.AsDuration()
+		if d.emptyTTL <= 0 {
+			d.emptyTTL = defaultEmptyTTL
+		}
+		d.jitter = c.Redis.Jitter
+	}
+	if d.local = newLocalCache(c.Local); d.local != nil && d.rdb != nil {
+		d.invalidation = d.rdb.Subscribe(context.Background(), invalidateChannel)
+		go d.watchInvalidation(d.invalidation, log.NewHelper(logger))
+	}
+	d.coalesce = c.GetCounter().GetCoalesce()
+	if d.sharedLimit = c.GetLimit().GetShared(); d.sharedLimit && d.rdb == nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("shared limit needs redis")
+	}
+	if c.Bloom != nil && d.rdb != nil {
+		d.subjectBloom = newBloomFilter(d.rdb, "subject", c.Bloom, c.Bloom.SubjectBits, d.scanSubjectKeys, d.subjectExists, logger)
+		d.commentBloom = newBloomFilter(d.rdb, "comment", c.Bloom, c.Bloom.CommentBits, d.scanCommentKeys, d.commentExists, logger)
+	}
+	return d, cleanup, nil
+}
+
+// sqliteDefaults are the options of a SQLite database not set in the
+// source. A transaction waits on the single writer and takes the write lock
+// at begin, so a transaction reading before it writes is not failed busy.
+var sqliteDefaults = [][2]string{{"_busy_timeout", "5000"}, {"_txlock", "immediate"}}
+
+// sqliteSource adds the default options missing from the source.
+func sqliteSource(source string) string {
+	var query string
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		query = source[i+1:]
+	}
+	values, _ := url.ParseQuery(query)
+	for _, o := range sqliteDefaults {
+		if _, ok := values[o[0]]; ok {
+			continue
+		}
+		switch {
+		case !strings.Contains(source, "?"):
+			source += "?"
+		case !strings.HasSuffix(source, "?") && !strings.HasSuffix(source, "&"):
+			source += "&"
+		}
+		source += o[0] + "=" + o[1]
+	}
+	return source
+}
+
+// openDB opens the database and applies the migrations if asked to.
+func openDB(c *conf.Data_Database) (*sql.DB, error) {
+	source := c.Source
+	if c.Driver == "sqlite3" {
+		source = sqliteSource(source)
+	}
+	db, err := sql.Open(c.Driver, source)
+	if err != nil {
+		return nil, err
+	}
+	if c.Migrate {
+		if err = migrate(context.Background(), db, c.Driver); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// databases returns the main database and the shard databases.
+func (d *Data) databases() []*sql.DB {
+	dbs := []*sql.DB{d.db}
+	for _, db := range d.shardDBs {
+		if db != d.db {
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs
+}
+
+// tx runs fn in a transaction on the main database, it is committed when fn returns nil.
+func (d *Data) tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return runTx(ctx, d.db, fn)
+}
+
+// runTx runs fn in a transaction on db, it is committed when fn returns nil.
+func runTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// onDuplicate returns the clause of an insert conflicting with the unique
+// key on cols that updates the row with set, or keeps it when set is empty,
+// the rows affected are 0 when the row is kept.
+func (d *Data) onDuplicate(cols, set string) string {
+	if d.driver == "sqlite3" {
+		if set == "" {
+			return " ON CONFLICT(" + cols + ") DO NOTHING"
+		}
+		return " ON CONFLICT(" + cols + ") DO UPDATE SET " + set
+	}
+	if set == "" {
+		col := strings.TrimSpace(strings.Split(cols, ",")[0])
+		set = col + " = " + col
+	}
+	return " ON DUPLICATE KEY UPDATE " + set
+}
+
+func joinIDs(ids []int64) string {
+	ss := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ss = append(ss, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(ss, ",")
+}
+
+func splitIDs(s string) []int64 {
+	if s == "" {
+		return nil
+	}
+	ss := strings.Split(s, ",")
+	ids := make([]int64, 0, len(ss))
+	for _, s := range ss {
+		if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// maxMentionsLength is the size of the mentions column.
+const maxMentionsLength = 2048
+
+// joinMentions encodes the mentions as "member_id:offset:length,...", the
+// mentions beyond the size of the column are dropped.
+func joinMentions(ms []*biz.Mention) (string, []*biz.Mention) {
+	var b strings.Builder
+	for i, m := range ms {
+		s := fmt.Sprintf("%d:%d:%d", m.MemberID, m.Offset, m.Length)
+		if i > 0 {
+			s = "," + s
+		}
+		if b.Len()+len(s) > maxMentionsLength {
+			return b.String(), ms[:i]
+		}
+		b.WriteString(s)
+	}
+	return b.String(), ms
+}
+
+func splitMentions(s string) []*biz.Mention {
+	if s == "" {
+		return nil
+	}
+	ss := strings.Split(s, ",")
+	ms := make([]*biz.Mention, 0, len(ss))
+	for _, s := range ss {
+		m := new(biz.Mention)
+		if _, err := fmt.Sscanf(s, "%d:%d:%d", &m.MemberID, &m.Offset, &m.Length); err == nil {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// placeholders returns "?,?,?" for n arguments.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?,", n-1) + "?"
+}
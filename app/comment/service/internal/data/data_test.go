@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// newTestData opens a migrated SQLite database in a temporary directory.
+func newTestData(t *testing.T) *Data {
+	t.Helper()
+	return openTestData(t, &conf.Data{})
+}
+
+// openTestData opens c on a migrated SQLite database in a temporary directory.
+func openTestData(t *testing.T, c *conf.Data) *Data {
+	t.Helper()
+	c.Database = &conf.Data_Database{
+		Driver:  "sqlite3",
+		Source:  "file:" + filepath.Join(t.TempDir(), "comment.db"),
+		Migrate: true,
+	}
+	d, cleanup, err := NewData(c, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+	return d
+}
+
+func TestMigrate(t *testing.T) {
+	d := newTestData(t)
+	// a second run applies nothing
+	if err := migrate(context.Background(), d.db, "sqlite3"); err != nil {
+		t.Fatal(err)
+	}
+	ms, err := loadMigrations("sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err = d.db.QueryRow(`SELECT COUNT(*) FROM schema_migration`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != len(ms) {
+		t.Fatalf("applied %d migrations, want %d", n, len(ms))
+	}
+}
+
+func TestMigrationsMatch(t *testing.T) {
+	mysql, err := loadMigrations("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := loadMigrations("sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mysql) != len(sqlite) {
+		t.Fatalf("got %d mysql and %d sqlite3 migrations", len(mysql), len(sqlite))
+	}
+	for i := range mysql {
+		if mysql[i].version != sqlite[i].version || mysql[i].name != sqlite[i].name {
+			t.Errorf("mysql migration %d_%s, sqlite3 migration %d_%s",
+				mysql[i].version, mysql[i].name, sqlite[i].version, sqlite[i].name)
+		}
+	}
+	if _, err = loadMigrations("postgres"); err == nil {
+		t.Error("loaded migrations of an unknown driver")
+	}
+}
+
+func TestMigrateConcurrent(t *testing.T) {
+	const n = 4
+	var (
+		source = sqliteSource("file:" + filepath.Join(t.TempDir(), "comment.db"))
+		wg     sync.WaitGroup
+		errs   = make(chan error, n)
+	)
+	// instances started at the same time migrate the same database
+	for i := 0; i < n; i++ {
+		db, err := sql.Open("sqlite3", source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- migrate(context.Background(), db, "sqlite3")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		script    string
+		backslash bool
+		want      []string
+	}{
+		{"CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n", false,
+			[]string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"}},
+		{"CREATE TABLE a (id INT COMMENT 'x; y');", true, []string{"CREATE TABLE a (id INT COMMENT 'x; y')"}},
+		{"INSERT INTO a VALUES ('it''s; ok')", false, []string{"INSERT INTO a VALUES ('it''s; ok')"}},
+		{`INSERT INTO a VALUES ('\'; x')`, true, []string{`INSERT INTO a VALUES ('\'; x')`}},
+		{`INSERT INTO a VALUES ('\'); SELECT 1`, false, []string{`INSERT INTO a VALUES ('\')`, "SELECT 1"}},
+		{"-- a; b\nSELECT 1; /* c; d */ SELECT `e;f`", false,
+			[]string{"-- a; b\nSELECT 1", "/* c; d */ SELECT `e;f`"}},
+	}
+	for i, tt := range tests {
+		if got := splitStatements(tt.script, tt.backslash); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestSQLiteSource(t *testing.T) {
+	tests := []struct {
+		source, want string
+	}{
+		{"file:a.db", "file:a.db?_busy_timeout=5000&_txlock=immediate"},
+		{"file:a.db?cache=shared", "file:a.db?cache=shared&_busy_timeout=5000&_txlock=immediate"},
+		{"file:a.db?_txlock=deferred", "file:a.db?_txlock=deferred&_busy_timeout=5000"},
+		{"file:a.db?_busy_timeout=100&_txlock=exclusive", "file:a.db?_busy_timeout=100&_txlock=exclusive"},
+	}
+	for _, tt := range tests {
+		if got := sqliteSource(tt.source); got != tt.want {
+			t.Errorf("got %s from %s, want %s", got, tt.source, tt.want)
+		}
+	}
+}
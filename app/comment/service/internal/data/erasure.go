@@ -0,0 +1,70 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type erasureRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewErasureRepo .
+func NewErasureRepo(data *Data, logger log.Logger) biz.ErasureRepo {
+	return &erasureRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *erasureRepo) CreateErasure(ctx context.Context, e *biz.Erasure) error {
+	now := time.Now()
+	return r.data.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `INSERT INTO comment_erasure
+			(member_id, operator_id, reason, state, erased, create_time, update_time)
+			VALUES (?, ?, ?, ?, 0, ?, ?)`, e.MemberID, e.OperatorID, e.Reason, e.State, now, now)
+		if err != nil {
+			return err
+		}
+		if e.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		e.CreateTime = now
+		err = addAudit(ctx, tx, &biz.Audit{
+			Action:     biz.AuditActionErase,
+			OperatorID: e.OperatorID,
+			MemberID:   e.MemberID,
+			Detail:     e.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		return addEvent(ctx, tx, &v1.Event{Event: &v1.Event_MemberErasureRequested{
+			MemberErasureRequested: &v1.MemberErasureRequested{TaskId: e.ID, MemberId: e.MemberID},
+		}})
+	})
+}
+
+func (r *erasureRepo) GetErasure(ctx context.Context, id int64) (*biz.Erasure, error) {
+	var (
+		e      = new(biz.Erasure)
+		finish sql.NullTime
+	)
+	err := r.data.db.QueryRowContext(ctx, `SELECT id, member_id, operator_id, reason, state, erased, create_time, finish_time
+		FROM comment_erasure WHERE id = ?`, id).
+		Scan(&e.ID, &e.MemberID, &e.OperatorID, &e.Reason, &e.State, &e.Erased, &e.CreateTime, &finish)
+	if err == sql.ErrNoRows {
+		return nil, biz.ErrErasureNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.FinishTime = finish.Time
+	return e, nil
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"google.golang.org/protobuf/proto"
+)
+
+// addEvent writes the event into the comment_event outbox in tx, the
+// comment job consumes the outbox of each database in id order. The events
+// of a comment are written on its shard, so they commit with the comment.
+func addEvent(ctx context.Context, tx *sql.Tx, e *v1.Event) error {
+	now := time.Now()
+	e.CreateTime = now.Unix()
+	b, err := proto.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO comment_event (payload, create_time) VALUES (?, ?)`, b, now)
+	return err
+}
+
+// addCommentChanged writes the comment changed event of c into the outbox
+// in tx on the shard of c, source is the moderation source when the change
+// put c into the moderation queue.
+func addCommentChanged(ctx context.Context, tx *sql.Tx, c *biz.Comment, source int8) error {
+	return addEvent(ctx, tx, &v1.Event{Event: &v1.Event_CommentChanged{CommentChanged: &v1.CommentChanged{
+		CommentId:        c.ID,
+		ObjId:            c.ObjID,
+		ObjType:          c.ObjType,
+		Root:             c.Root,
+		ModerationSource: int32(source),
+	}}})
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type likeRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewLikeRepo .
+func NewLikeRepo(data *Data, logger log.Logger) biz.LikeRepo {
+	return &likeRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// likeDelta adds n to the count of the action.
+func likeDelta(c *counterDelta, action int8, n int32) {
+	switch action {
+	case biz.LikeActionLike:
+		c.like += n
+	case biz.LikeActionHate:
+		c.hate += n
+	}
+}
+
+// errLikeRaced is returned by saveLike when the action read was changed by
+// a concurrent request, the like is saved again from the changed action.
+var errLikeRaced = errors.New("like raced")
+
+// likeRetries is the times a raced like is saved again.
+const likeRetries = 3
+
+func (r *likeRepo) SaveLike(ctx context.Context, l *biz.Like) (err error) {
+	for i := 0; i < likeRetries; i++ {
+		if err = r.saveLike(ctx, l); err != errLikeRaced {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err = r.data.cacheHotComment(ctx, l.CommentID); err != nil {
+		r.log.Errorf("cache hot score of comment %d: %v", l.CommentID, err)
+	}
+	return nil
+}
+
+// saveLike saves the action in a transaction, the changes of the row are
+// conditioned on the action read so the counters change once per change.
+func (r *likeRepo) saveLike(ctx context.Context, l *biz.Like) error {
+	now := time.Now()
+	return r.data.crossTx(ctx, r.data.commentShard(l.CommentID), func(tx, shard *sql.Tx) error {
+		var old int8
+		err := tx.QueryRowContext(ctx, `SELECT id, action FROM comment_action WHERE comment_id = ? AND member_id = ?`,
+			l.CommentID, l.MemberID).Scan(&l.ID, &old)
+		switch {
+		case err == sql.ErrNoRows:
+			old = biz.LikeActionCancel
+		case err != nil:
+			return err
+		}
+		if old == l.Action {
+			return nil
+		}
+
+		var res sql.Result
+		switch {
+		case l.Action == biz.LikeActionCancel:
+			res, err = tx.ExecContext(ctx, `DELETE FROM comment_action WHERE id = ? AND action = ?`, l.ID, old)
+		case old == biz.LikeActionCancel:
+			res, err = tx.ExecContext(ctx, `INSERT INTO comment_action (comment_id, member_id, action, create_time, update_time)
+				VALUES (?, ?, ?, ?, ?)`+r.data.onDuplicate("comment_id, member_id", ""),
+				l.CommentID, l.MemberID, l.Action, now, now)
+		default:
+			res, err = tx.ExecContext(ctx, `UPDATE comment_action SET action = ?, update_time = ? WHERE id = ? AND action = ?`,
+				l.Action, now, l.ID, old)
+		}
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return errLikeRaced
+		}
+		if old == biz.LikeActionCancel {
+			if l.ID, err = res.LastInsertId(); err != nil {
+				return err
+			}
+		}
+		l.CreateTime = now
+
+		c := &biz.Comment{ID: l.CommentID}
+		err = shard.QueryRowContext(ctx, `SELECT obj_id, obj_type FROM comment_index WHERE id = ?`,
+			l.CommentID).Scan(&c.ObjID, &c.ObjType)
+		if err != nil {
+			return err
+		}
+		delta := &counterDelta{commentID: c.ID, objID: c.ObjID, objType: c.ObjType}
+		likeDelta(delta, old, -1)
+		likeDelta(delta, l.Action, 1)
+		return r.data.incrCounters(ctx, shard, delta)
+	})
+}
+
+func (r *likeRepo) ListMemberLike(ctx context.Context, memberID, cursor int64, limit int) ([]*biz.Like, error) {
+	rows, err := r.data.db.QueryContext(ctx, `SELECT id, comment_id, member_id, action, create_time FROM comment_action
+		WHERE member_id = ? AND id > ? ORDER BY id LIMIT ?`, memberID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ls []*biz.Like
+	for rows.Next() {
+		l := new(biz.Like)
+		if err = rows.Scan(&l.ID, &l.CommentID, &l.MemberID, &l.Action, &l.CreateTime); err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	return ls, rows.Err()
+}
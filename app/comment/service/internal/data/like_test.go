@@ -0,0 +1,100 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func TestLikeRepo(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		d       = newTestData(t)
+		comment = NewCommentRepo(d, log.DefaultLogger)
+		repo    = NewLikeRepo(d, log.DefaultLogger)
+	)
+	if err := NewSubjectRepo(d, log.DefaultLogger).CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1}); err != nil {
+		t.Fatal(err)
+	}
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	if err := comment.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		memberID   int64
+		action     int8
+		like, hate int32
+	}{
+		{11, biz.LikeActionLike, 1, 0},
+		{11, biz.LikeActionLike, 1, 0},
+		{12, biz.LikeActionHate, 1, 1},
+		{11, biz.LikeActionHate, 0, 2},
+		{12, biz.LikeActionCancel, 0, 1},
+		{12, biz.LikeActionCancel, 0, 1},
+	}
+	for i, tt := range tests {
+		if err := repo.SaveLike(ctx, &biz.Like{CommentID: c.ID, MemberID: tt.memberID, Action: tt.action}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := comment.GetComment(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Like != tt.like || got.Hate != tt.hate {
+			t.Errorf("%d: got like %d hate %d, want %d %d", i, got.Like, got.Hate, tt.like, tt.hate)
+		}
+	}
+
+	ls, err := repo.ListMemberLike(ctx, 11, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 || ls[0].Action != biz.LikeActionHate {
+		t.Fatalf("got %d likes", len(ls))
+	}
+}
+
+func TestSaveLikeConcurrent(t *testing.T) {
+	const n = 8
+	var (
+		ctx     = context.Background()
+		d       = newTestData(t)
+		comment = NewCommentRepo(d, log.DefaultLogger)
+		repo    = NewLikeRepo(d, log.DefaultLogger)
+		wg      sync.WaitGroup
+		errs    = make(chan error, n)
+	)
+	if err := NewSubjectRepo(d, log.DefaultLogger).CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1}); err != nil {
+		t.Fatal(err)
+	}
+	c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}
+	if err := comment.CreateComment(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	// the member likes the comment several times at the same time
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.SaveLike(ctx, &biz.Like{CommentID: c.ID, MemberID: 11, Action: biz.LikeActionLike})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := comment.GetComment(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Like != 1 {
+		t.Fatalf("got like %d after repeated likes", got.Like)
+	}
+}
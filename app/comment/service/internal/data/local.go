@@ -0,0 +1,199 @@
+package data
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// invalidateChannel is the redis channel the comment job broadcasts the
+// changed subjects on, the message is "{obj_type}:{obj_id}:{root}".
+const invalidateChannel = "comment:invalidate"
+
+// localCache keeps the first pages of the hot lists in process in front of
+// redis, the lists are keyed by their sorted set keys and the least
+// recently read list is dropped when the cache is full.
+type localCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	pages   int
+	lru     *list.List // *localList, the most recently read first
+	entries map[string]*list.Element
+	hot     *hotKeys
+}
+
+// localList is the cached pages of a list.
+type localList struct {
+	key    string
+	expire time.Time
+	pages  map[[2]int][]*biz.Comment // offset, limit
+}
+
+func newLocalCache(c *conf.Data_Local) *localCache {
+	if c == nil || c.Size <= 0 || c.Pages <= 0 {
+		return nil
+	}
+	return &localCache{
+		size:    int(c.Size),
+		ttl:     c.Ttl.AsDuration(),
+		pages:   int(c.Pages),
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		hot:     newHotKeys(c.HotWindow.AsDuration(), int(c.HotThreshold)),
+	}
+}
+
+// get returns the cached page of the list and counts the request, a nil
+// cache never hits.
+func (l *localCache) get(key string, offset, limit int) ([]*biz.Comment, bool) {
+	if l == nil || offset >= l.pages*limit {
+		return nil, false
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hot.hit(key, now)
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	ll := e.Value.(*localList)
+	if now.After(ll.expire) {
+		l.remove(e)
+		return nil, false
+	}
+	cs, ok := ll.pages[[2]int{offset, limit}]
+	if !ok {
+		return nil, false
+	}
+	l.lru.MoveToFront(e)
+	return copyComments(cs), true
+}
+
+// set caches the page of the list if the list is hot.
+func (l *localCache) set(key string, offset, limit int, cs []*biz.Comment) {
+	if l == nil || offset >= l.pages*limit {
+		return
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.hot.isHot(key) {
+		return
+	}
+	e, ok := l.entries[key]
+	if !ok || now.After(e.Value.(*localList).expire) {
+		if ok {
+			l.remove(e)
+		}
+		e = l.lru.PushFront(&localList{
+			key:    key,
+			expire: now.Add(l.ttl),
+			pages:  make(map[[2]int][]*biz.Comment),
+		})
+		l.entries[key] = e
+		for l.lru.Len() > l.size {
+			l.remove(l.lru.Back())
+		}
+	}
+	e.Value.(*localList).pages[[2]int{offset, limit}] = copyComments(cs)
+}
+
+// evict drops the cached lists of the subject and the replies of root.
+func (l *localCache) evict(objID int64, objType int32, root int64) {
+	l.evictSort(objID, objType, root, biz.SortFloor, biz.SortHot)
+}
+
+// evictSort drops the cached lists of the subject and the replies of root in the sorts.
+func (l *localCache) evictSort(objID int64, objType int32, root int64, sorts ...int8) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var keys []string
+	for _, sort := range sorts {
+		keys = append(keys, subjectIndexKey(objID, objType, sort))
+		if root != 0 {
+			keys = append(keys, replyIndexKey(root, sort))
+		}
+	}
+	for _, key := range keys {
+		if e, ok := l.entries[key]; ok {
+			l.remove(e)
+		}
+	}
+}
+
+func (l *localCache) remove(e *list.Element) {
+	l.lru.Remove(e)
+	delete(l.entries, e.Value.(*localList).key)
+}
+
+// copyComments copies the comments, the callers set the replies and the
+// counts of the comments they get.
+func copyComments(cs []*biz.Comment) []*biz.Comment {
+	cp := make([]*biz.Comment, 0, len(cs))
+	for _, c := range cs {
+		c := *c
+		cp = append(cp, &c)
+	}
+	return cp
+}
+
+// hotKeys counts the requests of each key in the current and the previous
+// window, a key is hot when either count reaches the threshold.
+type hotKeys struct {
+	window    time.Duration
+	threshold int
+	start     time.Time
+	prev      map[string]int
+	cur       map[string]int
+}
+
+func newHotKeys(window time.Duration, threshold int) *hotKeys {
+	return &hotKeys{
+		window:    window,
+		threshold: threshold,
+		cur:       make(map[string]int),
+	}
+}
+
+func (h *hotKeys) hit(key string, now time.Time) {
+	if now.Sub(h.start) >= h.window {
+		h.prev, h.cur = h.cur, make(map[string]int)
+		// the previous window is too old to count after a quiet window
+		if now.Sub(h.start) >= 2*h.window {
+			h.prev = nil
+		}
+		h.start = now
+	}
+	h.cur[key]++
+}
+
+func (h *hotKeys) isHot(key string) bool {
+	return h.cur[key] >= h.threshold || h.prev[key] >= h.threshold
+}
+
+// watchInvalidation evicts the local cache on the invalidations broadcast
+// by the comment job until the subscription is closed.
+func (d *Data) watchInvalidation(sub *redis.PubSub, log *log.Helper) {
+	for msg := range sub.Channel() {
+		var (
+			objType     int32
+			objID, root int64
+		)
+		if _, err := fmt.Sscanf(msg.Payload, "%d:%d:%d", &objType, &objID, &root); err != nil {
+			log.Errorf("invalid invalidation %q: %v", msg.Payload, err)
+			continue
+		}
+		d.local.evict(objID, objType, root)
+	}
+}
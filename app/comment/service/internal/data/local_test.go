@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestLocalCache(size int32, ttl time.Duration) *localCache {
+	return newLocalCache(&conf.Data_Local{
+		Size:         size,
+		Ttl:          durationpb.New(ttl),
+		Pages:        2,
+		HotThreshold: 2,
+		HotWindow:    durationpb.New(time.Minute),
+	})
+}
+
+func TestLocalContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		mr := miniredis.RunT(t)
+		return repos(openTestData(t, &conf.Data{
+			Redis: &conf.Data_Redis{Addr: mr.Addr()},
+			Local: &conf.Data_Local{Size: 10, Ttl: durationpb.New(time.Minute), Pages: 2, HotThreshold: 1,
+				HotWindow: durationpb.New(time.Minute)},
+		}))
+	})
+}
+
+func TestLocalCache(t *testing.T) {
+	var (
+		l  = newTestLocalCache(2, time.Minute)
+		cs = []*biz.Comment{{ID: 1, RootCount: 1}}
+	)
+	// a list is cached once it is hot
+	l.get("a", 0, 10)
+	l.set("a", 0, 10, cs)
+	if _, ok := l.get("a", 0, 10); ok {
+		t.Fatal("cached a cold list")
+	}
+	l.set("a", 0, 10, cs)
+	got, ok := l.get("a", 0, 10)
+	if !ok || len(got) != 1 || got[0].ID != 1 {
+		t.Fatal("hot list not cached")
+	}
+	// the callers change their copies
+	got[0].RootCount = 0
+	cs[0].RootCount = 2
+	if got, _ = l.get("a", 0, 10); got[0].RootCount != 1 {
+		t.Fatalf("got root count %d of the cached comment", got[0].RootCount)
+	}
+
+	// only the first pages are cached
+	l.set("a", 20, 10, cs)
+	if _, ok = l.get("a", 20, 10); ok {
+		t.Fatal("cached the third page")
+	}
+
+	// the least recently read list is dropped
+	for _, key := range []string{"b", "c"} {
+		l.get(key, 0, 10)
+		l.get(key, 0, 10)
+		l.set(key, 0, 10, cs)
+		l.get("a", 0, 10)
+	}
+	if _, ok = l.get("b", 0, 10); ok {
+		t.Fatal("kept the least recently read list")
+	}
+	if _, ok = l.get("a", 0, 10); !ok {
+		t.Fatal("dropped the most recently read list")
+	}
+
+	l = newTestLocalCache(2, time.Millisecond)
+	l.get("a", 0, 10)
+	l.get("a", 0, 10)
+	l.set("a", 0, 10, cs)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok = l.get("a", 0, 10); ok {
+		t.Fatal("got an expired list")
+	}
+}
+
+func TestLocalInvalidation(t *testing.T) {
+	var (
+		ctx = context.Background()
+		mr  = miniredis.RunT(t)
+		d   = openTestData(t, &conf.Data{
+			Redis: &conf.Data_Redis{Addr: mr.Addr()},
+			Local: &conf.Data_Local{Size: 10, Ttl: durationpb.New(time.Minute), Pages: 1, HotThreshold: 2,
+				HotWindow: durationpb.New(time.Minute)},
+		})
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		key     = subjectIndexKey(1, 1, biz.SortFloor)
+	)
+	if err := subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1, MemberID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	cached := func() bool {
+		d.local.mu.Lock()
+		defer d.local.mu.Unlock()
+		_, ok := d.local.entries[key]
+		return ok
+	}
+	list := func() {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			if _, err := repo.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !cached() {
+			t.Fatal("hot list not cached")
+		}
+	}
+
+	// a write of this process evicts the list at once
+	list()
+	if err := repo.CreateComment(ctx, &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 10, Message: "root"}); err != nil {
+		t.Fatal(err)
+	}
+	if cached() {
+		t.Fatal("kept the list after a comment")
+	}
+
+	// a write of another process is broadcast by the comment job
+	list()
+	deadline := time.Now().Add(5 * time.Second)
+	for cached() {
+		if time.Now().After(deadline) {
+			t.Fatal("kept the list after the invalidation")
+		}
+		mr.Publish(invalidateChannel, "1:1:0")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
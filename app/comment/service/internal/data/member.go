@@ -0,0 +1,67 @@
+package data
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// listMemberIDPath is the path of the name lookup of the member service.
+const listMemberIDPath = "/member.service.v1.MemberService/ListMemberID"
+
+type listMemberIDReq struct {
+	Names []string `json:"names"`
+}
+
+type listMemberIDReply struct {
+	IDs map[string]int64 `json:"ids"`
+}
+
+// memberRepo resolves names by the member service, or from the configured
+// member names during development when the member service is not set.
+type memberRepo struct {
+	names  map[string]int64
+	client *http.Client // 用户服务, 为空时使用 names
+	log    *log.Helper
+}
+
+// NewMemberRepo .
+func NewMemberRepo(c *conf.Data, logger log.Logger) (biz.MemberRepo, error) {
+	r := &memberRepo{
+		names: c.GetMember().GetNames(),
+		log:   log.NewHelper(logger),
+	}
+	if endpoint := c.GetMember().GetEndpoint(); endpoint != "" {
+		opts := []http.ClientOption{http.WithEndpoint(endpoint)}
+		if c.Member.Timeout != nil {
+			opts = append(opts, http.WithTimeout(c.Member.Timeout.AsDuration()))
+		}
+		client, err := http.NewClient(context.Background(), opts...)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+	return r, nil
+}
+
+func (r *memberRepo) ListMemberID(ctx context.Context, names []string) (map[string]int64, error) {
+	if r.client != nil {
+		var reply listMemberIDReply
+		err := r.client.Invoke(ctx, "POST", listMemberIDPath, &listMemberIDReq{Names: names}, &reply)
+		if err != nil {
+			return nil, err
+		}
+		return reply.IDs, nil
+	}
+	ids := make(map[string]int64, len(names))
+	for _, name := range names {
+		if id, ok := r.names[name]; ok {
+			ids[name] = id
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+func TestMemberService(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var in listMemberIDReq
+		if req.URL.Path != listMemberIDPath || json.NewDecoder(req.Body).Decode(&in) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out := listMemberIDReply{IDs: map[string]int64{}}
+		for _, name := range in.Names {
+			if name == "alice" {
+				out.IDs[name] = 1
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	repo, err := NewMemberRepo(&conf.Data{Member: &conf.Data_Member{Endpoint: srv.URL}}, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := repo.ListMemberID(context.Background(), []string{"alice", "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids["alice"] != 1 {
+		t.Fatalf("got ids %v", ids)
+	}
+}
+
+func TestJoinMentions(t *testing.T) {
+	ms := make([]*biz.Mention, 100)
+	for i := range ms {
+		ms[i] = &biz.Mention{MemberID: 1 << 60, Offset: int32(i * 10), Length: 9}
+	}
+	s, kept := joinMentions(ms)
+	if len(s) > maxMentionsLength || len(kept) == len(ms) {
+		t.Fatalf("got %d bytes of %d mentions", len(s), len(kept))
+	}
+	if got := len(strings.Split(s, ",")); got != len(kept) {
+		t.Fatalf("encoded %d of %d kept mentions", got, len(kept))
+	}
+	if got := splitMentions(s); len(got) != len(kept) {
+		t.Fatalf("decoded %d mentions", len(got))
+	}
+}
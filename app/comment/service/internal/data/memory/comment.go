@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type commentRepo struct {
+	s *Store
+}
+
+// NewCommentRepo .
+func NewCommentRepo(s *Store) biz.CommentRepo {
+	return &commentRepo{s: s}
+}
+
+func (r *commentRepo) CreateComment(ctx context.Context, c *biz.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	s, ok := r.s.subjects[subjectKey{objID: c.ObjID, objType: c.ObjType}]
+	if !ok {
+		return biz.ErrSubjectNotFound
+	}
+	var root *biz.Comment
+	if c.Root != 0 {
+		if root, ok = r.s.comments[c.Root]; !ok {
+			return biz.ErrCommentNotFound
+		}
+	}
+	for _, a := range c.Attachments {
+		if id, ok := r.s.attachments[a.ID]; ok && id != 0 {
+			return biz.ErrAttachmentUsed
+		}
+	}
+
+	// only normal comments are counted, the floor is allocated anyway
+	var incr int32
+	if c.State == biz.CommentStateNormal {
+		incr = 1
+	}
+	if root == nil {
+		s.Count++
+		s.RootCount += incr
+		s.AllCount += incr
+		c.Floor = int64(s.Count)
+	} else {
+		s.AllCount += incr
+		root.Count++
+		root.RootCount += incr
+		c.Floor = int64(root.Count)
+	}
+	r.s.commentSeq++
+	c.ID = r.s.commentSeq
+	c.CreateTime = time.Now()
+	for _, a := range c.Attachments {
+		r.s.attachments[a.ID] = c.ID
+		a.CommentID = c.ID
+	}
+	cc := copyComment(c)
+	cc.Count, cc.RootCount, cc.Like, cc.Hate = 0, 0, 0, 0
+	cc.EditTime = time.Time{}
+	r.s.comments[c.ID] = cc
+	return nil
+}
+
+func (r *commentRepo) GetComment(ctx context.Context, id int64) (*biz.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.comments[id]
+	if !ok {
+		return nil, biz.ErrCommentNotFound
+	}
+	return copyComment(c), nil
+}
+
+func (r *commentRepo) ListCommentByID(ctx context.Context, ids []int64) ([]*biz.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var cs []*biz.Comment
+	for _, id := range ids {
+		if c, ok := r.s.comments[id]; ok {
+			cs = append(cs, copyComment(c))
+		}
+	}
+	return cs, nil
+}
+
+func (r *commentRepo) DeleteComment(ctx context.Context, c *biz.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.hideComment(c, biz.CommentStateDeleted)
+	return nil
+}
+
+func (r *commentRepo) EditComment(ctx context.Context, c *biz.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	old, ok := r.s.comments[c.ID]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	v := &biz.CommentVersion{
+		CommentID:   old.ID,
+		AtMemberIDs: old.AtMemberIDs,
+		Mentions:    old.Mentions,
+		Message:     old.Message,
+		Meta:        old.Meta,
+		Content:     old.Content,
+		CreateTime:  old.CreateTime,
+	}
+	if !old.EditTime.IsZero() {
+		v.CreateTime = old.EditTime
+	}
+	r.s.versions[c.ID] = append(r.s.versions[c.ID], v)
+
+	old.AtMemberIDs = copyIDs(c.AtMemberIDs)
+	old.Mentions = copyMentions(c.Mentions)
+	old.Message = c.Message
+	old.Meta = c.Meta
+	old.Content = copyContent(c.Content)
+	old.EditTime = now
+	c.EditTime = now
+	return nil
+}
+
+func (r *commentRepo) ListCommentVersion(ctx context.Context, id int64) ([]*biz.CommentVersion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	vs := r.s.versions[id]
+	cvs := make([]*biz.CommentVersion, 0, len(vs))
+	for i := len(vs) - 1; i >= 0; i-- {
+		v := *vs[i]
+		v.AtMemberIDs = copyIDs(v.AtMemberIDs)
+		v.Mentions = copyMentions(v.Mentions)
+		v.Content = copyContent(v.Content)
+		cvs = append(cvs, &v)
+	}
+	return cvs, nil
+}
+
+func (r *commentRepo) ListComment(ctx context.Context, objID int64, objType int32, order int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cs := r.s.filter(func(c *biz.Comment) bool {
+		return c.ObjID == objID && c.ObjType == objType && c.Root == 0 &&
+			c.State == biz.CommentStateNormal && !contains(excludes, c.MemberID)
+	})
+	if order == biz.SortHot {
+		sort.Slice(cs, func(i, j int) bool { return hotter(cs[i], cs[j]) })
+	} else {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Floor > cs[j].Floor })
+	}
+	return page(cs, offset, limit), nil
+}
+
+func (r *commentRepo) ListReply(ctx context.Context, root int64, order int8, excludes []int64, offset, limit int) ([]*biz.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cs := r.s.filter(func(c *biz.Comment) bool {
+		return c.Root == root && c.State == biz.CommentStateNormal && !contains(excludes, c.MemberID)
+	})
+	if order == biz.SortHot {
+		sort.Slice(cs, func(i, j int) bool { return hotter(cs[i], cs[j]) })
+	} else {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Floor < cs[j].Floor })
+	}
+	return page(cs, offset, limit), nil
+}
+
+func (r *commentRepo) ListMemberComment(ctx context.Context, f *biz.MemberCommentFilter, states []int8) ([]*biz.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var last *biz.Comment
+	if f.Cursor != 0 {
+		if last = r.s.comments[f.Cursor]; last == nil {
+			return nil, biz.ErrCommentNotFound
+		}
+	}
+	cs := r.s.filter(func(c *biz.Comment) bool {
+		return c.MemberID == f.MemberID && containsState(states, c.State) &&
+			(f.ObjType == 0 || c.ObjType == f.ObjType) &&
+			(f.StartTime.IsZero() || !c.CreateTime.Before(f.StartTime)) &&
+			(f.EndTime.IsZero() || c.CreateTime.Before(f.EndTime)) &&
+			(last == nil || newer(last, c))
+	})
+	sort.Slice(cs, func(i, j int) bool { return newer(cs[i], cs[j]) })
+	return page(cs, 0, f.Limit), nil
+}
+
+// newer reports whether a is listed before b in the comments of a member.
+func newer(a, b *biz.Comment) bool {
+	if !a.CreateTime.Equal(b.CreateTime) {
+		return a.CreateTime.After(b.CreateTime)
+	}
+	return a.ID > b.ID
+}
+
+// hotter reports whether a is listed before b in the hot order.
+func hotter(a, b *biz.Comment) bool {
+	if ha, hb := a.Like+a.RootCount, b.Like+b.RootCount; ha != hb {
+		return ha > hb
+	}
+	return a.Floor > b.Floor
+}
+
+func (r *commentRepo) RankComment(ctx context.Context, objID int64, objType int32, floor int64, excludes []int64) (int32, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int32(len(r.s.filter(func(c *biz.Comment) bool {
+		return c.ObjID == objID && c.ObjType == objType && c.Root == 0 && c.State == biz.CommentStateNormal &&
+			c.Floor > floor && !contains(excludes, c.MemberID)
+	}))), nil
+}
+
+func (r *commentRepo) RankReply(ctx context.Context, root, floor int64, excludes []int64) (int32, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int32(len(r.s.filter(func(c *biz.Comment) bool {
+		return c.Root == root && c.State == biz.CommentStateNormal && c.Floor < floor && !contains(excludes, c.MemberID)
+	}))), nil
+}
+
+func (r *commentRepo) CountComment(ctx context.Context, objID int64, objType int32, memberIDs []int64) (int32, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int32(len(r.s.filter(func(c *biz.Comment) bool {
+		return c.ObjID == objID && c.ObjType == objType && c.Root == 0 && c.State == biz.CommentStateNormal &&
+			contains(memberIDs, c.MemberID)
+	}))), nil
+}
+
+func (r *commentRepo) CountReply(ctx context.Context, roots []int64, memberIDs []int64) (map[int64]int32, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := make(map[int64]int32, len(roots))
+	for _, c := range r.s.comments {
+		if c.State == biz.CommentStateNormal && contains(roots, c.Root) && contains(memberIDs, c.MemberID) {
+			counts[c.Root]++
+		}
+	}
+	return counts, nil
+}
+
+// hideComment changes a normal comment to state and decreases the counts
+// of its subject and root, it reports false if the comment is not normal.
+func (s *Store) hideComment(c *biz.Comment, state int8) bool {
+	sc, ok := s.comments[c.ID]
+	if !ok || sc.State != biz.CommentStateNormal {
+		return false
+	}
+	sc.State = state
+	if sub, ok := s.subjects[subjectKey{objID: c.ObjID, objType: c.ObjType}]; ok {
+		sub.AllCount--
+		if c.Root == 0 {
+			sub.RootCount--
+		}
+	}
+	if root, ok := s.comments[c.Root]; ok && c.Root != 0 {
+		root.RootCount--
+	}
+	return true
+}
+
+// filter returns copies of the comments matching fn.
+func (s *Store) filter(fn func(c *biz.Comment) bool) []*biz.Comment {
+	var cs []*biz.Comment
+	for _, c := range s.comments {
+		if fn(c) {
+			cs = append(cs, copyComment(c))
+		}
+	}
+	return cs
+}
+
+func page(cs []*biz.Comment, offset, limit int) []*biz.Comment {
+	if offset >= len(cs) {
+		return nil
+	}
+	cs = cs[offset:]
+	if len(cs) > limit {
+		cs = cs[:limit]
+	}
+	return cs
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsState(states []int8, state int8) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
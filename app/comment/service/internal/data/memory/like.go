@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type likeRepo struct {
+	s *Store
+}
+
+// NewLikeRepo .
+func NewLikeRepo(s *Store) biz.LikeRepo {
+	return &likeRepo{s: s}
+}
+
+func (r *likeRepo) SaveLike(ctx context.Context, l *biz.Like) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var (
+		key = likeKey{commentID: l.CommentID, memberID: l.MemberID}
+		old = biz.LikeActionCancel
+		now = time.Now()
+	)
+	if o, ok := r.s.likes[key]; ok {
+		old, l.ID = o.Action, o.ID
+	}
+	if old == l.Action {
+		return nil
+	}
+
+	switch {
+	case l.Action == biz.LikeActionCancel:
+		delete(r.s.likes, key)
+	case old == biz.LikeActionCancel:
+		r.s.likeSeq++
+		l.ID = r.s.likeSeq
+		r.s.likes[key] = &biz.Like{ID: l.ID, CommentID: l.CommentID, MemberID: l.MemberID, Action: l.Action, CreateTime: now}
+	default:
+		r.s.likes[key].Action = l.Action
+	}
+	l.CreateTime = now
+
+	c, ok := r.s.comments[l.CommentID]
+	if !ok {
+		return nil
+	}
+	switch old {
+	case biz.LikeActionLike:
+		if c.Like > 0 {
+			c.Like--
+		}
+	case biz.LikeActionHate:
+		if c.Hate > 0 {
+			c.Hate--
+		}
+	}
+	switch l.Action {
+	case biz.LikeActionLike:
+		c.Like++
+	case biz.LikeActionHate:
+		c.Hate++
+	}
+	return nil
+}
+
+func (r *likeRepo) ListMemberLike(ctx context.Context, memberID, cursor int64, limit int) ([]*biz.Like, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var ls []*biz.Like
+	for _, l := range r.s.likes {
+		if l.MemberID == memberID && l.ID > cursor {
+			cl := *l
+			ls = append(ls, &cl)
+		}
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].ID < ls[j].ID })
+	if len(ls) > limit {
+		ls = ls[:limit]
+	}
+	return ls, nil
+}
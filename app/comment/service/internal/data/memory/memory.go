@@ -0,0 +1,91 @@
+// Package memory implements the subject, comment and like repos in memory
+// with the same semantics as the SQL ones, for unit tests and local demos.
+// Comment created events are not emitted and attachments are only bound.
+package memory
+
+import (
+	"sync"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"google.golang.org/protobuf/proto"
+)
+
+type subjectKey struct {
+	objID   int64
+	objType int32
+}
+
+type likeKey struct {
+	commentID int64
+	memberID  int64
+}
+
+// Store holds the data shared by the repos of a backend.
+type Store struct {
+	mu sync.Mutex
+
+	subjectSeq int64
+	commentSeq int64
+	likeSeq    int64
+
+	subjects    map[subjectKey]*biz.Subject
+	comments    map[int64]*biz.Comment
+	versions    map[int64][]*biz.CommentVersion // 评论的历史版本, 旧的在前
+	attachments map[int64]int64                 // 附件ID到评论ID
+	likes       map[likeKey]*biz.Like
+}
+
+// NewStore new an empty store.
+func NewStore() *Store {
+	return &Store{
+		subjects:    make(map[subjectKey]*biz.Subject),
+		comments:    make(map[int64]*biz.Comment),
+		versions:    make(map[int64][]*biz.CommentVersion),
+		attachments: make(map[int64]int64),
+		likes:       make(map[likeKey]*biz.Like),
+	}
+}
+
+func copySubject(s *biz.Subject) *biz.Subject {
+	c := *s
+	return &c
+}
+
+// copyComment returns a copy of c sharing nothing with it, without replies.
+func copyComment(c *biz.Comment) *biz.Comment {
+	cc := *c
+	cc.Replies = nil
+	cc.AtMemberIDs = copyIDs(c.AtMemberIDs)
+	cc.Mentions = copyMentions(c.Mentions)
+	cc.Content = copyContent(c.Content)
+	cc.Attachments = nil
+	for _, a := range c.Attachments {
+		ca := *a
+		cc.Attachments = append(cc.Attachments, &ca)
+	}
+	return &cc
+}
+
+func copyIDs(ids []int64) []int64 {
+	if len(ids) == 0 {
+		return nil
+	}
+	return append([]int64(nil), ids...)
+}
+
+func copyMentions(ms []*biz.Mention) []*biz.Mention {
+	var cms []*biz.Mention
+	for _, m := range ms {
+		cm := *m
+		cms = append(cms, &cm)
+	}
+	return cms
+}
+
+func copyContent(ct *v1.RichContent) *v1.RichContent {
+	if ct == nil {
+		return nil
+	}
+	return proto.Clone(ct).(*v1.RichContent)
+}
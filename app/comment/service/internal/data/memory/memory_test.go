@@ -0,0 +1,18 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/zldongly/comment/app/comment/service/internal/data/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		s := NewStore()
+		return &repotest.Repos{
+			Subject: NewSubjectRepo(s),
+			Comment: NewCommentRepo(s),
+			Like:    NewLikeRepo(s),
+		}
+	})
+}
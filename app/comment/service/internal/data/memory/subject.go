@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type subjectRepo struct {
+	s *Store
+}
+
+// NewSubjectRepo .
+func NewSubjectRepo(s *Store) biz.SubjectRepo {
+	return &subjectRepo{s: s}
+}
+
+func (r *subjectRepo) CreateSubject(ctx context.Context, s *biz.Subject) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	key := subjectKey{objID: s.ObjID, objType: s.ObjType}
+	if _, ok := r.s.subjects[key]; ok {
+		return biz.ErrSubjectExisted
+	}
+	r.s.subjectSeq++
+	s.ID = r.s.subjectSeq
+	s.Count, s.RootCount, s.AllCount = 0, 0, 0
+	s.CreateTime = time.Now()
+	r.s.subjects[key] = copySubject(s)
+	return nil
+}
+
+func (r *subjectRepo) GetSubject(ctx context.Context, objID int64, objType int32) (*biz.Subject, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	s, ok := r.s.subjects[subjectKey{objID: objID, objType: objType}]
+	if !ok {
+		return nil, biz.ErrSubjectNotFound
+	}
+	return copySubject(s), nil
+}
+
+func (r *subjectRepo) ListSubject(ctx context.Context, objIDs []int64) ([]*biz.Subject, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	ids := make(map[int64]bool, len(objIDs))
+	for _, id := range objIDs {
+		ids[id] = true
+	}
+	var ss []*biz.Subject
+	for key, s := range r.s.subjects {
+		if ids[key.objID] {
+			ss = append(ss, copySubject(s))
+		}
+	}
+	return ss, nil
+}
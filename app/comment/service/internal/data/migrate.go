@@ -0,0 +1,221 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrations holds the versioned schema of each driver under
+// migrations/<driver>/<version>_<name>.sql.
+//
+//go:embed migrations
+var migrations embed.FS
+
+type migration struct {
+	version int64
+	name    string
+	stmts   []string
+}
+
+// loadMigrations returns the migrations of the driver ordered by version.
+func loadMigrations(driver string) ([]*migration, error) {
+	dir := path.Join("migrations", driver)
+	es, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+	ms := make([]*migration, 0, len(es))
+	for _, e := range es {
+		name := strings.TrimSuffix(e.Name(), ".sql")
+		i := strings.IndexByte(name, '_')
+		if e.IsDir() || name == e.Name() || i < 0 {
+			return nil, fmt.Errorf("invalid migration %s", e.Name())
+		}
+		version, err := strconv.ParseInt(name[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration %s: %v", e.Name(), err)
+		}
+		b, err := fs.ReadFile(migrations, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m := &migration{version: version, name: name[i+1:]}
+		m.stmts = splitStatements(string(b), driver == "mysql")
+		ms = append(ms, m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].version < ms[j].version })
+	for i := 1; i < len(ms); i++ {
+		if ms[i].version == ms[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %d", ms[i].version)
+		}
+	}
+	return ms, nil
+}
+
+// splitStatements splits the script on the semicolons outside of quoted
+// strings, identifiers and comments, a backslash escapes the next character
+// in the strings of MySQL.
+func splitStatements(script string, backslash bool) []string {
+	var (
+		stmts []string
+		start int
+		quote byte // 当前引号, 0 时不在引号内
+	)
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && backslash && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			if j := strings.IndexByte(script[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			if j := strings.Index(script[i+2:], "*/"); j >= 0 {
+				i += j + 3
+			} else {
+				i = len(script)
+			}
+		case c == ';':
+			if stmt := strings.TrimSpace(script[start:i]); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			start = i + 1
+		}
+	}
+	if start < len(script) {
+		if stmt := strings.TrimSpace(script[start:]); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// 迁移锁, MySQL 使用命名锁, SQLite 使用写事务
+const (
+	migrationLock        = "comment_schema_migration"
+	migrationLockTimeout = 60 // 秒
+)
+
+// lockMigration takes the migration lock of the database on conn, so one
+// of the instances started at the same time applies the migrations and the
+// others see them applied. unlock releases the lock, the SQLite transaction
+// is rolled back when err is not nil.
+func lockMigration(ctx context.Context, conn *sql.Conn, driver string) (unlock func(err error) error, err error) {
+	switch driver {
+	case "mysql":
+		var got sql.NullInt64
+		err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, migrationLock, migrationLockTimeout).Scan(&got)
+		if err != nil {
+			return nil, err
+		}
+		if got.Int64 != 1 {
+			return nil, fmt.Errorf("migration lock %s is held by another instance", migrationLock)
+		}
+		return func(error) error {
+			var released sql.NullInt64
+			return conn.QueryRowContext(context.Background(), `SELECT RELEASE_LOCK(?)`, migrationLock).Scan(&released)
+		}, nil
+	case "sqlite3":
+		if _, err = conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+			return nil, err
+		}
+		return func(err error) error {
+			if err != nil {
+				_, err = conn.ExecContext(context.Background(), `ROLLBACK`)
+				return err
+			}
+			_, err = conn.ExecContext(context.Background(), `COMMIT`)
+			return err
+		}, nil
+	}
+	return nil, fmt.Errorf("no migration lock for driver %q", driver)
+}
+
+// migrate applies the migrations of the driver not recorded in
+// schema_migration in version order under the migration lock. DDL is not
+// transactional in MySQL, so the statements are written to be rerun after
+// a failure.
+func migrate(ctx context.Context, db *sql.DB, driver string) (err error) {
+	ms, err := loadMigrations(driver)
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	unlock, err := lockMigration(ctx, conn, driver)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if uerr := unlock(err); err == nil {
+			err = uerr
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migration (
+		version     BIGINT       NOT NULL,
+		name        VARCHAR(255) NOT NULL,
+		create_time DATETIME     NOT NULL,
+		PRIMARY KEY (version))`)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if applied[m.version] {
+			continue
+		}
+		for _, stmt := range m.stmts {
+			if _, err = conn.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("migration %d_%s: %v", m.version, m.name, err)
+			}
+		}
+		_, err = conn.ExecContext(ctx, `INSERT INTO schema_migration (version, name, create_time) VALUES (?, ?, ?)`,
+			m.version, m.name, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appliedMigrations returns the versions recorded in schema_migration.
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migration`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var v int64
+		if err = rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
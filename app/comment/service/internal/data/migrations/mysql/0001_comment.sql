@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS comment_subject (
+    id          BIGINT      NOT NULL AUTO_INCREMENT,
+    obj_id      BIGINT      NOT NULL,
+    obj_type    INT         NOT NULL,
+    member_id   BIGINT      NOT NULL COMMENT '主题作者',
+    count       INT         NOT NULL DEFAULT 0 COMMENT '根评论楼层计数',
+    root_count  INT         NOT NULL DEFAULT 0 COMMENT '现存根评论数量',
+    all_count   INT         NOT NULL DEFAULT 0 COMMENT '现存评论总数',
+    state       TINYINT     NOT NULL DEFAULT 0,
+    create_time DATETIME    NOT NULL,
+    update_time DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_obj (obj_id, obj_type)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_index (
+    id              BIGINT      NOT NULL AUTO_INCREMENT,
+    obj_id          BIGINT      NOT NULL,
+    obj_type        INT         NOT NULL,
+    member_id       BIGINT      NOT NULL,
+    root            BIGINT      NOT NULL DEFAULT 0 COMMENT '根评论ID, 0 为根评论',
+    parent          BIGINT      NOT NULL DEFAULT 0 COMMENT '回复的评论ID',
+    reply_member_id BIGINT      NOT NULL DEFAULT 0 COMMENT '回复的人',
+    floor           BIGINT      NOT NULL,
+    count           INT         NOT NULL DEFAULT 0 COMMENT '回复楼层计数',
+    root_count      INT         NOT NULL DEFAULT 0 COMMENT '现存回复数量',
+    like_count      INT         NOT NULL DEFAULT 0,
+    hate_count      INT         NOT NULL DEFAULT 0,
+    state           TINYINT     NOT NULL DEFAULT 0,
+    create_time     DATETIME    NOT NULL,
+    update_time     DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_obj (obj_id, obj_type, root, floor),
+    KEY idx_root (root, floor),
+    KEY idx_member (member_id, id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_content (
+    comment_id    BIGINT        NOT NULL,
+    at_member_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    mentions      VARCHAR(2048) NOT NULL DEFAULT '' COMMENT 'member_id:offset:length, 逗号分隔',
+    message       TEXT          NOT NULL,
+    meta          TEXT          NOT NULL,
+    content       BLOB          NULL COMMENT 'protobuf 编码的 comment.service.v1.RichContent',
+    ip            BIGINT        NOT NULL DEFAULT 0,
+    platform      VARCHAR(32)   NOT NULL DEFAULT '',
+    device        VARCHAR(64)   NOT NULL DEFAULT '',
+    edit_time     DATETIME      NULL COMMENT '最后编辑时间, NULL 为未编辑',
+    create_time   DATETIME      NOT NULL,
+    update_time   DATETIME      NOT NULL,
+    PRIMARY KEY (comment_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_action (
+    id          BIGINT   NOT NULL AUTO_INCREMENT,
+    comment_id  BIGINT   NOT NULL,
+    member_id   BIGINT   NOT NULL,
+    action      TINYINT  NOT NULL COMMENT '1 点赞 2 点踩',
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_comment_member (comment_id, member_id),
+    KEY idx_member (member_id, id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS comment_report (
+    id          BIGINT       NOT NULL AUTO_INCREMENT,
+    comment_id  BIGINT       NOT NULL,
+    obj_id      BIGINT       NOT NULL,
+    obj_type    INT          NOT NULL,
+    member_id   BIGINT       NOT NULL COMMENT '举报人',
+    reason      INT          NOT NULL DEFAULT 0,
+    content     VARCHAR(255) NOT NULL DEFAULT '',
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_comment_member (comment_id, member_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_report_stat (
+    comment_id  BIGINT   NOT NULL,
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    member_id   BIGINT   NOT NULL COMMENT '评论作者',
+    count       INT      NOT NULL DEFAULT 0,
+    hidden      TINYINT  NOT NULL DEFAULT 0 COMMENT '是否已进入审核队列',
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (comment_id),
+    KEY idx_count (count)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_moderation (
+    comment_id  BIGINT   NOT NULL,
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    source      TINYINT  NOT NULL COMMENT '1 用户举报 2 重复评论',
+    state       TINYINT  NOT NULL DEFAULT 0 COMMENT '0 待审核 1 通过 2 删除',
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (comment_id),
+    KEY idx_state (state, create_time)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
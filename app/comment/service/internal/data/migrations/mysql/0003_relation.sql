@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS comment_block (
+    id          BIGINT       NOT NULL AUTO_INCREMENT,
+    owner_id    BIGINT       NOT NULL COMMENT '主题作者, 0 为全站封禁',
+    member_id   BIGINT       NOT NULL COMMENT '被拉黑的人',
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    expire_time DATETIME     NULL COMMENT 'NULL 为永久',
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_owner_member (owner_id, member_id),
+    KEY idx_member (member_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_mute (
+    id             BIGINT   NOT NULL AUTO_INCREMENT,
+    member_id      BIGINT   NOT NULL COMMENT '查看者',
+    mute_member_id BIGINT   NOT NULL COMMENT '被屏蔽的人',
+    create_time    DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_member_mute (member_id, mute_member_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS comment_event (
+    id          BIGINT   NOT NULL AUTO_INCREMENT,
+    payload     BLOB     NOT NULL COMMENT 'protobuf 编码的 comment.service.v1.Event',
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_job_cursor (
+    name        VARCHAR(64) NOT NULL COMMENT '消费者名',
+    event_id    BIGINT      NOT NULL DEFAULT 0 COMMENT '已处理的最大事件ID',
+    update_time DATETIME    NOT NULL,
+    PRIMARY KEY (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS comment_content_history (
+    id            BIGINT        NOT NULL AUTO_INCREMENT,
+    comment_id    BIGINT        NOT NULL,
+    at_member_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    mentions      VARCHAR(2048) NOT NULL DEFAULT '',
+    message       TEXT          NOT NULL,
+    meta          TEXT          NOT NULL,
+    content       BLOB          NULL,
+    create_time   DATETIME      NOT NULL COMMENT '该版本的发布时间',
+    PRIMARY KEY (id),
+    KEY idx_comment (comment_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_attachment (
+    id          BIGINT        NOT NULL AUTO_INCREMENT,
+    token       VARCHAR(64)   NOT NULL COMMENT '上传后返回给客户端的引用',
+    member_id   BIGINT        NOT NULL COMMENT '上传者',
+    comment_id  BIGINT        NOT NULL DEFAULT 0 COMMENT '0 为还未使用',
+    path        VARCHAR(255)  NOT NULL COMMENT '存储中的路径',
+    url         VARCHAR(1024) NOT NULL,
+    format      VARCHAR(16)   NOT NULL,
+    size        BIGINT        NOT NULL,
+    width       INT           NOT NULL,
+    height      INT           NOT NULL,
+    create_time DATETIME      NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_token (token),
+    KEY idx_comment (comment_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS comment_erasure (
+    id          BIGINT       NOT NULL AUTO_INCREMENT,
+    member_id   BIGINT       NOT NULL COMMENT '被抹除的人',
+    operator_id BIGINT       NOT NULL COMMENT '管理员',
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    state       TINYINT      NOT NULL DEFAULT 0 COMMENT '0 等待 1 执行中 2 完成',
+    erased      INT          NOT NULL DEFAULT 0 COMMENT '已抹除的评论数',
+    create_time DATETIME     NOT NULL,
+    update_time DATETIME     NOT NULL,
+    finish_time DATETIME     NULL,
+    PRIMARY KEY (id),
+    KEY idx_member (member_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_audit_log (
+    id          BIGINT       NOT NULL AUTO_INCREMENT,
+    action      VARCHAR(32)  NOT NULL COMMENT 'export erase erase_start erase_done',
+    operator_id BIGINT       NOT NULL COMMENT '管理员, 0 为 comment job',
+    member_id   BIGINT       NOT NULL COMMENT '被操作的人',
+    detail      VARCHAR(255) NOT NULL DEFAULT '',
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_member (member_id, id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comment_id_seq (
+    id          BIGINT   NOT NULL AUTO_INCREMENT COMMENT '分片库内的评论序列, 评论ID = id * 分片数 + 分片',
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+INSERT INTO comment_id_seq (id, create_time)
+SELECT id, CURRENT_TIMESTAMP FROM comment_index
+WHERE id = (SELECT MAX(id) FROM comment_index) AND NOT EXISTS (SELECT 1 FROM comment_id_seq);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS comment_counter_delta (
+    id          BIGINT   NOT NULL AUTO_INCREMENT,
+    comment_id  BIGINT   NOT NULL DEFAULT 0 COMMENT '计数变化的评论, 0 为主题的计数',
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    like_count  INT      NOT NULL DEFAULT 0,
+    hate_count  INT      NOT NULL DEFAULT 0,
+    root_count  INT      NOT NULL DEFAULT 0 COMMENT '评论的现存回复数量或主题的现存根评论数量',
+    all_count   INT      NOT NULL DEFAULT 0 COMMENT '主题的现存评论总数',
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_comment (comment_id),
+    KEY idx_obj (obj_id, obj_type)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
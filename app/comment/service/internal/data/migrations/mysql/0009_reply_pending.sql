@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS comment_reply_pending (
+    id             BIGINT      NOT NULL AUTO_INCREMENT,
+    type           VARCHAR(16) NOT NULL COMMENT 'reply 或 comment',
+    member_id      BIGINT      NOT NULL COMMENT '接收人',
+    target         BIGINT      NOT NULL COMMENT '被回复的评论或主题ID',
+    target_type    INT         NOT NULL DEFAULT 0 COMMENT '主题类型, 回复为 0',
+    from_member_id BIGINT      NOT NULL COMMENT '最新一条评论的作者',
+    comment_id     BIGINT      NOT NULL COMMENT '最新一条评论',
+    obj_id         BIGINT      NOT NULL,
+    obj_type       INT         NOT NULL,
+    root           BIGINT      NOT NULL DEFAULT 0,
+    parent         BIGINT      NOT NULL DEFAULT 0,
+    count          INT         NOT NULL DEFAULT 0 COMMENT '聚合的评论作者数量',
+    first_time     DATETIME    NOT NULL COMMENT '聚合窗口的开始时间',
+    create_time    DATETIME    NOT NULL COMMENT '最新一条评论的时间',
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_target (type, member_id, target, target_type),
+    KEY idx_first_time (first_time)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_reply_pending_member (
+    pending_id BIGINT NOT NULL,
+    member_id  BIGINT NOT NULL COMMENT '聚合的评论作者',
+    PRIMARY KEY (pending_id, member_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
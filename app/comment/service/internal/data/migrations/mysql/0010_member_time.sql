@@ -0,0 +1,7 @@
+-- MySQL 没有 CREATE INDEX IF NOT EXISTS, 索引已存在时执行空语句, 失败后可以重新执行
+SET @create_index = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'comment_index' AND index_name = 'idx_member_time') = 0,
+    'CREATE INDEX idx_member_time ON comment_index (member_id, create_time, id)', 'DO 0');
+PREPARE create_index FROM @create_index;
+EXECUTE create_index;
+DEALLOCATE PREPARE create_index;
@@ -0,0 +1,7 @@
+-- 抹除用户时按 reply_member_id 清除回复关系
+SET @create_index = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'comment_index' AND index_name = 'idx_reply_member') = 0,
+    'CREATE INDEX idx_reply_member ON comment_index (reply_member_id)', 'DO 0');
+PREPARE create_index FROM @create_index;
+EXECUTE create_index;
+DEALLOCATE PREPARE create_index;
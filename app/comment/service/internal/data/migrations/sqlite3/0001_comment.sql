@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS comment_subject (
+    id          INTEGER     NOT NULL,
+    obj_id      BIGINT      NOT NULL,
+    obj_type    INT         NOT NULL,
+    member_id   BIGINT      NOT NULL,
+    count       INT         NOT NULL DEFAULT 0,
+    root_count  INT         NOT NULL DEFAULT 0,
+    all_count   INT         NOT NULL DEFAULT 0,
+    state       TINYINT     NOT NULL DEFAULT 0,
+    create_time DATETIME    NOT NULL,
+    update_time DATETIME    NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (obj_id, obj_type)
+);
+
+CREATE TABLE IF NOT EXISTS comment_index (
+    id              INTEGER     NOT NULL,
+    obj_id          BIGINT      NOT NULL,
+    obj_type        INT         NOT NULL,
+    member_id       BIGINT      NOT NULL,
+    root            BIGINT      NOT NULL DEFAULT 0,
+    parent          BIGINT      NOT NULL DEFAULT 0,
+    reply_member_id BIGINT      NOT NULL DEFAULT 0,
+    floor           BIGINT      NOT NULL,
+    count           INT         NOT NULL DEFAULT 0,
+    root_count      INT         NOT NULL DEFAULT 0,
+    like_count      INT         NOT NULL DEFAULT 0,
+    hate_count      INT         NOT NULL DEFAULT 0,
+    state           TINYINT     NOT NULL DEFAULT 0,
+    create_time     DATETIME    NOT NULL,
+    update_time     DATETIME    NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS comment_index_idx_obj ON comment_index (obj_id, obj_type, root, floor);
+CREATE INDEX IF NOT EXISTS comment_index_idx_root ON comment_index (root, floor);
+CREATE INDEX IF NOT EXISTS comment_index_idx_member ON comment_index (member_id, id);
+
+CREATE TABLE IF NOT EXISTS comment_content (
+    comment_id    BIGINT        NOT NULL,
+    at_member_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    mentions      VARCHAR(2048) NOT NULL DEFAULT '',
+    message       TEXT          NOT NULL,
+    meta          TEXT          NOT NULL,
+    content       BLOB          NULL,
+    ip            BIGINT        NOT NULL DEFAULT 0,
+    platform      VARCHAR(32)   NOT NULL DEFAULT '',
+    device        VARCHAR(64)   NOT NULL DEFAULT '',
+    edit_time     DATETIME      NULL,
+    create_time   DATETIME      NOT NULL,
+    update_time   DATETIME      NOT NULL,
+    PRIMARY KEY (comment_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_action (
+    id          INTEGER  NOT NULL,
+    comment_id  BIGINT   NOT NULL,
+    member_id   BIGINT   NOT NULL,
+    action      TINYINT  NOT NULL,
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (comment_id, member_id)
+);
+CREATE INDEX IF NOT EXISTS comment_action_idx_member ON comment_action (member_id, id);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS comment_report (
+    id          INTEGER      NOT NULL,
+    comment_id  BIGINT       NOT NULL,
+    obj_id      BIGINT       NOT NULL,
+    obj_type    INT          NOT NULL,
+    member_id   BIGINT       NOT NULL,
+    reason      INT          NOT NULL DEFAULT 0,
+    content     VARCHAR(255) NOT NULL DEFAULT '',
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (comment_id, member_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_report_stat (
+    comment_id  BIGINT   NOT NULL,
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    member_id   BIGINT   NOT NULL,
+    count       INT      NOT NULL DEFAULT 0,
+    hidden      TINYINT  NOT NULL DEFAULT 0,
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (comment_id)
+);
+CREATE INDEX IF NOT EXISTS comment_report_stat_idx_count ON comment_report_stat (count);
+
+CREATE TABLE IF NOT EXISTS comment_moderation (
+    comment_id  BIGINT   NOT NULL,
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    source      TINYINT  NOT NULL,
+    state       TINYINT  NOT NULL DEFAULT 0,
+    create_time DATETIME NOT NULL,
+    update_time DATETIME NOT NULL,
+    PRIMARY KEY (comment_id)
+);
+CREATE INDEX IF NOT EXISTS comment_moderation_idx_state ON comment_moderation (state, create_time);
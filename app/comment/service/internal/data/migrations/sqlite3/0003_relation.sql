@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS comment_block (
+    id          INTEGER      NOT NULL,
+    owner_id    BIGINT       NOT NULL,
+    member_id   BIGINT       NOT NULL,
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    expire_time DATETIME     NULL,
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (owner_id, member_id)
+);
+CREATE INDEX IF NOT EXISTS comment_block_idx_member ON comment_block (member_id);
+
+CREATE TABLE IF NOT EXISTS comment_mute (
+    id             INTEGER  NOT NULL,
+    member_id      BIGINT   NOT NULL,
+    mute_member_id BIGINT   NOT NULL,
+    create_time    DATETIME NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (member_id, mute_member_id)
+);
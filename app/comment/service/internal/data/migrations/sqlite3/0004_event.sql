@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS comment_event (
+    id          INTEGER  NOT NULL,
+    payload     BLOB     NOT NULL,
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+
+CREATE TABLE IF NOT EXISTS comment_job_cursor (
+    name        VARCHAR(64) NOT NULL,
+    event_id    BIGINT      NOT NULL DEFAULT 0,
+    update_time DATETIME    NOT NULL,
+    PRIMARY KEY (name)
+);
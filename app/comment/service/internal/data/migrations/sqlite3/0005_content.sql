@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS comment_content_history (
+    id            INTEGER       NOT NULL,
+    comment_id    BIGINT        NOT NULL,
+    at_member_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    mentions      VARCHAR(2048) NOT NULL DEFAULT '',
+    message       TEXT          NOT NULL,
+    meta          TEXT          NOT NULL,
+    content       BLOB          NULL,
+    create_time   DATETIME      NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS comment_content_history_idx_comment ON comment_content_history (comment_id);
+
+CREATE TABLE IF NOT EXISTS comment_attachment (
+    id          INTEGER       NOT NULL,
+    token       VARCHAR(64)   NOT NULL,
+    member_id   BIGINT        NOT NULL,
+    comment_id  BIGINT        NOT NULL DEFAULT 0,
+    path        VARCHAR(255)  NOT NULL,
+    url         VARCHAR(1024) NOT NULL,
+    format      VARCHAR(16)   NOT NULL,
+    size        BIGINT        NOT NULL,
+    width       INT           NOT NULL,
+    height      INT           NOT NULL,
+    create_time DATETIME      NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (token)
+);
+CREATE INDEX IF NOT EXISTS comment_attachment_idx_comment ON comment_attachment (comment_id);
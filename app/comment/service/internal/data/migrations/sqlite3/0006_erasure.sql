@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS comment_erasure (
+    id          INTEGER      NOT NULL,
+    member_id   BIGINT       NOT NULL,
+    operator_id BIGINT       NOT NULL,
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    state       TINYINT      NOT NULL DEFAULT 0,
+    erased      INT          NOT NULL DEFAULT 0,
+    create_time DATETIME     NOT NULL,
+    update_time DATETIME     NOT NULL,
+    finish_time DATETIME     NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS comment_erasure_idx_member ON comment_erasure (member_id);
+
+CREATE TABLE IF NOT EXISTS comment_audit_log (
+    id          INTEGER      NOT NULL,
+    action      VARCHAR(32)  NOT NULL,
+    operator_id BIGINT       NOT NULL,
+    member_id   BIGINT       NOT NULL,
+    detail      VARCHAR(255) NOT NULL DEFAULT '',
+    create_time DATETIME     NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS comment_audit_log_idx_member ON comment_audit_log (member_id, id);
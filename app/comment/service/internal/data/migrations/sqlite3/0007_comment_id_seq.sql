@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comment_id_seq (
+    id          INTEGER  NOT NULL,
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+
+INSERT INTO comment_id_seq (id, create_time)
+SELECT id, CURRENT_TIMESTAMP FROM comment_index
+WHERE id = (SELECT MAX(id) FROM comment_index) AND NOT EXISTS (SELECT 1 FROM comment_id_seq);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS comment_counter_delta (
+    id          INTEGER  NOT NULL,
+    comment_id  BIGINT   NOT NULL DEFAULT 0,
+    obj_id      BIGINT   NOT NULL,
+    obj_type    INT      NOT NULL,
+    like_count  INT      NOT NULL DEFAULT 0,
+    hate_count  INT      NOT NULL DEFAULT 0,
+    root_count  INT      NOT NULL DEFAULT 0,
+    all_count   INT      NOT NULL DEFAULT 0,
+    create_time DATETIME NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS comment_counter_delta_idx_comment ON comment_counter_delta (comment_id);
+CREATE INDEX IF NOT EXISTS comment_counter_delta_idx_obj ON comment_counter_delta (obj_id, obj_type);
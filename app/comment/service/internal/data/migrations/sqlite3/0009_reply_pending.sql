@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS comment_reply_pending (
+    id             INTEGER     NOT NULL,
+    type           VARCHAR(16) NOT NULL,
+    member_id      BIGINT      NOT NULL,
+    target         BIGINT      NOT NULL,
+    target_type    INT         NOT NULL DEFAULT 0,
+    from_member_id BIGINT      NOT NULL,
+    comment_id     BIGINT      NOT NULL,
+    obj_id         BIGINT      NOT NULL,
+    obj_type       INT         NOT NULL,
+    root           BIGINT      NOT NULL DEFAULT 0,
+    parent         BIGINT      NOT NULL DEFAULT 0,
+    count          INT         NOT NULL DEFAULT 0,
+    first_time     DATETIME    NOT NULL,
+    create_time    DATETIME    NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    UNIQUE (type, member_id, target, target_type)
+);
+CREATE INDEX IF NOT EXISTS comment_reply_pending_idx_first_time ON comment_reply_pending (first_time);
+
+CREATE TABLE IF NOT EXISTS comment_reply_pending_member (
+    pending_id BIGINT NOT NULL,
+    member_id  BIGINT NOT NULL,
+    PRIMARY KEY (pending_id, member_id)
+);
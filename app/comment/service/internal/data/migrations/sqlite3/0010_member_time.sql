@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS comment_index_idx_member_time ON comment_index (member_id, create_time, id);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS comment_index_idx_reply_member ON comment_index (reply_member_id);
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type moderationRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewModerationRepo .
+func NewModerationRepo(data *Data, logger log.Logger) biz.ModerationRepo {
+	return &moderationRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *moderationRepo) EnqueueModeration(ctx context.Context, c *biz.Comment, source int8) error {
+	var (
+		now    = time.Now()
+		hidden bool
+	)
+	err := r.data.crossTx(ctx, r.data.commentShard(c.ID), func(tx, shard *sql.Tx) (err error) {
+		if c.State == biz.CommentStateNormal {
+			if hidden, err = r.data.hideComment(ctx, shard, c, biz.CommentStatePending); err != nil || !hidden {
+				return err
+			}
+			c.State = biz.CommentStatePending
+			if err = addCommentChanged(ctx, shard, c, source); err != nil {
+				return err
+			}
+		}
+		return enqueueModeration(ctx, tx, c, source, now)
+	})
+	if err != nil || !hidden {
+		return err
+	}
+	if err = r.data.cacheHideComment(ctx, c); err != nil {
+		r.log.Errorf("uncache comment %d: %v", c.ID, err)
+	}
+	return nil
+}
+
+// enqueueModeration puts the comment into the moderation queue in tx on the main database.
+func enqueueModeration(ctx context.Context, tx *sql.Tx, c *biz.Comment, source int8, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO comment_moderation
+		(comment_id, obj_id, obj_type, source, state, create_time, update_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, c.ID, c.ObjID, c.ObjType, source, biz.ModerationStatePending, now, now)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE comment_report_stat SET hidden = 1 WHERE comment_id = ?`, c.ID)
+	return err
+}
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type muteRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewMuteRepo .
+func NewMuteRepo(data *Data, logger log.Logger) biz.MuteRepo {
+	return &muteRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// CreateMute inserts the mute unless it exists, a concurrent duplicate is
+// ignored by the unique key and the existing mute is read back.
+func (r *muteRepo) CreateMute(ctx context.Context, m *biz.Mute) error {
+	now := time.Now()
+	res, err := r.data.db.ExecContext(ctx, `INSERT INTO comment_mute (member_id, mute_member_id, create_time)
+		VALUES (?, ?, ?)`+r.data.onDuplicate("member_id, mute_member_id", ""), m.MemberID, m.MuteMemberID, now)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 1 {
+		m.ID, err = res.LastInsertId()
+		m.CreateTime = now
+		return err
+	}
+	return r.data.db.QueryRowContext(ctx, `SELECT id, create_time FROM comment_mute WHERE member_id = ? AND mute_member_id = ?`,
+		m.MemberID, m.MuteMemberID).Scan(&m.ID, &m.CreateTime)
+}
+
+func (r *muteRepo) DeleteMute(ctx context.Context, memberID, muteMemberID int64) error {
+	_, err := r.data.db.ExecContext(ctx, `DELETE FROM comment_mute WHERE member_id = ? AND mute_member_id = ?`,
+		memberID, muteMemberID)
+	return err
+}
+
+func (r *muteRepo) ListMute(ctx context.Context, memberID int64, offset, limit int) ([]*biz.Mute, int32, error) {
+	var total int32
+	err := r.data.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_mute WHERE member_id = ?`, memberID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT id, member_id, mute_member_id, create_time FROM comment_mute
+		WHERE member_id = ? ORDER BY create_time DESC, id DESC LIMIT ? OFFSET ?`, memberID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var ms []*biz.Mute
+	for rows.Next() {
+		m := new(biz.Mute)
+		if err = rows.Scan(&m.ID, &m.MemberID, &m.MuteMemberID, &m.CreateTime); err != nil {
+			return nil, 0, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, total, rows.Err()
+}
+
+func (r *muteRepo) ListMutedID(ctx context.Context, memberID int64) ([]int64, error) {
+	rows, err := r.data.db.QueryContext(ctx, `SELECT mute_member_id FROM comment_mute WHERE member_id = ?`, memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
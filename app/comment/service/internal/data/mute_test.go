@@ -0,0 +1,39 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func TestCreateMuteConcurrent(t *testing.T) {
+	const n = 8
+	var (
+		ctx  = context.Background()
+		repo = NewMuteRepo(newTestData(t), log.DefaultLogger)
+		ms   = make([]*biz.Mute, n)
+		wg   sync.WaitGroup
+	)
+	for i := range ms {
+		ms[i] = &biz.Mute{MemberID: 1, MuteMemberID: 2}
+		wg.Add(1)
+		go func(m *biz.Mute) {
+			defer wg.Done()
+			if err := repo.CreateMute(ctx, m); err != nil {
+				t.Error(err)
+			}
+		}(ms[i])
+	}
+	wg.Wait()
+	for _, m := range ms {
+		if m.ID != ms[0].ID || m.ID == 0 {
+			t.Fatalf("got mute %d and %d of the same members", m.ID, ms[0].ID)
+		}
+	}
+	if _, total, err := repo.ListMute(ctx, 1, 0, 10); err != nil || total != 1 {
+		t.Fatalf("got %d mutes, error %v", total, err)
+	}
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// 共享的令牌桶:
+//   comment:ratelimit:{key} 令牌桶的哈希, tokens 为剩余令牌数, last 为上次补充的毫秒时间
+// 多个桶在一个脚本里检查和扣减, 实例之间不会同时取走最后一个令牌, 桶在一个
+// window 后已经补满, 过期后删除.
+
+// bucketSweepInterval is how often idle buckets are dropped from memory.
+const bucketSweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  biz.RateLimit
+}
+
+// refill adds the tokens produced since last and reports whether the bucket is full.
+func (b *bucket) refill(now time.Time) bool {
+	rate := float64(b.limit.Limit) / b.limit.Window.Seconds()
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	b.last = now
+	if b.tokens >= float64(b.limit.Limit) {
+		b.tokens = float64(b.limit.Limit)
+		return true
+	}
+	return false
+}
+
+// rateLimitRepo is an in-memory token bucket store, it limits per instance.
+type rateLimitRepo struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweep   time.Time
+	log     *log.Helper
+}
+
+// NewRateLimitRepo returns the token buckets in redis when the limit is
+// shared, or in memory otherwise.
+func NewRateLimitRepo(data *Data, logger log.Logger) biz.RateLimitRepo {
+	if data.sharedLimit {
+		return &redisRateLimitRepo{rdb: data.rdb, log: log.NewHelper(logger)}
+	}
+	return &rateLimitRepo{
+		buckets: make(map[string]*bucket),
+		sweep:   time.Now(),
+		log:     log.NewHelper(logger),
+	}
+}
+
+func (r *rateLimitRepo) Take(ctx context.Context, limits ...biz.RateLimit) (time.Duration, error) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Sub(r.sweep) >= bucketSweepInterval {
+		for k, b := range r.buckets {
+			if b.refill(now) {
+				delete(r.buckets, k)
+			}
+		}
+		r.sweep = now
+	}
+
+	// the tokens are taken only when every bucket has one, a rejected
+	// request does not drain the other buckets
+	var (
+		wait time.Duration
+		bs   = make([]*bucket, 0, len(limits))
+	)
+	for _, limit := range limits {
+		b, ok := r.buckets[limit.Key]
+		if !ok {
+			b = &bucket{tokens: float64(limit.Limit), last: now}
+			r.buckets[limit.Key] = b
+		}
+		b.limit = limit
+		b.refill(now)
+		if b.tokens < 1 {
+			rate := float64(limit.Limit) / limit.Window.Seconds()
+			if d := time.Duration((1 - b.tokens) / rate * float64(time.Second)); d > wait {
+				wait = d
+			}
+		}
+		bs = append(bs, b)
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+	for _, b := range bs {
+		b.tokens--
+	}
+	return 0, nil
+}
+
+// takeTokens refills the buckets of KEYS to the time ARGV[1] in
+// milliseconds, ARGV[2i] and ARGV[2i+1] are the limit and the window in
+// milliseconds of KEYS[i]. It takes a token from every bucket when none of
+// them is empty and returns 0, or returns the milliseconds to wait.
+var takeTokens = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local wait = 0
+local tokens, last = {}, {}
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[i * 2])
+	local rate = limit / tonumber(ARGV[i * 2 + 1])
+	local b = redis.call('HMGET', key, 'tokens', 'last')
+	tokens[i] = tonumber(b[1]) or limit
+	last[i] = tonumber(b[2]) or now
+	if now > last[i] then
+		tokens[i] = math.min(limit, tokens[i] + (now - last[i]) * rate)
+		last[i] = now
+	end
+	if tokens[i] < 1 then
+		wait = math.max(wait, math.ceil((1 - tokens[i]) / rate))
+	end
+end
+if wait > 0 then
+	return wait
+end
+for i, key in ipairs(KEYS) do
+	redis.call('HMSET', key, 'tokens', tostring(tokens[i] - 1), 'last', tostring(last[i]))
+	redis.call('PEXPIRE', key, ARGV[i * 2 + 1])
+end
+return 0
+`)
+
+// redisRateLimitRepo is a token bucket store in redis, it limits across instances.
+type redisRateLimitRepo struct {
+	rdb *redis.Client
+	log *log.Helper
+}
+
+func rateLimitKey(key string) string {
+	return "comment:ratelimit:" + key
+}
+
+func (r *redisRateLimitRepo) Take(ctx context.Context, limits ...biz.RateLimit) (time.Duration, error) {
+	if len(limits) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, 0, len(limits))
+	args := make([]interface{}, 0, 1+2*len(limits))
+	args = append(args, time.Now().UnixNano()/int64(time.Millisecond))
+	for _, limit := range limits {
+		keys = append(keys, rateLimitKey(limit.Key))
+		args = append(args, limit.Limit, limit.Window.Milliseconds())
+	}
+	wait, err := takeTokens.Run(ctx, r.rdb, keys, args...).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
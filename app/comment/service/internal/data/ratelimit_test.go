@@ -0,0 +1,83 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+func TestRateLimitTake(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		open func(t *testing.T) *Data
+	}{
+		{"memory", newTestData},
+		{"redis", func(t *testing.T) *Data {
+			d, _ := newCachedTestData(t, nil)
+			d.sharedLimit = true
+			return d
+		}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				ctx    = context.Background()
+				repo   = NewRateLimitRepo(tt.open(t), log.DefaultLogger)
+				member = biz.RateLimit{Key: "member", Limit: 1, Window: time.Hour}
+				ip     = biz.RateLimit{Key: "ip", Limit: 3, Window: time.Hour}
+			)
+			if wait, err := repo.Take(ctx, member, ip); err != nil || wait != 0 {
+				t.Fatalf("got wait %v err %v of the first take", wait, err)
+			}
+			// the empty member bucket rejects the request without taking from the ip bucket
+			for i := 0; i < 3; i++ {
+				if wait, err := repo.Take(ctx, member, ip); err != nil || wait <= 0 {
+					t.Fatalf("got wait %v err %v of an empty bucket", wait, err)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				if wait, err := repo.Take(ctx, ip); err != nil || wait != 0 {
+					t.Fatalf("got wait %v err %v of take %d from the ip bucket", wait, err, i)
+				}
+			}
+			if wait, _ := repo.Take(ctx, ip); wait <= 0 {
+				t.Fatal("took more tokens than the ip bucket holds")
+			}
+		})
+	}
+}
+
+func TestRateLimitShared(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		d, mr = newCachedTestData(t, nil)
+		limit = biz.RateLimit{Key: "member", Limit: 2, Window: time.Second}
+	)
+	d.sharedLimit = true
+	// two instances share the bucket
+	a, b := NewRateLimitRepo(d, log.DefaultLogger), NewRateLimitRepo(d, log.DefaultLogger)
+	for _, repo := range []biz.RateLimitRepo{a, b} {
+		if wait, err := repo.Take(ctx, limit); err != nil || wait != 0 {
+			t.Fatalf("got wait %v err %v", wait, err)
+		}
+	}
+	if wait, err := b.Take(ctx, limit); err != nil || wait <= 0 || wait > limit.Window/2 {
+		t.Fatalf("got wait %v err %v of the shared empty bucket", wait, err)
+	}
+	if ttl := mr.TTL(rateLimitKey(limit.Key)); ttl <= 0 || ttl > limit.Window {
+		t.Fatalf("got bucket ttl %v", ttl)
+	}
+
+	// the shared limit is not opened without redis
+	c := &conf.Data{
+		Database: &conf.Data_Database{Driver: "sqlite3", Source: "file::memory:"},
+		Limit:    &conf.Data_Limit{Shared: true},
+	}
+	if _, _, err := NewData(c, log.DefaultLogger); err == nil {
+		t.Fatal("opened a shared limit without redis")
+	}
+}
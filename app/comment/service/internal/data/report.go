@@ -0,0 +1,125 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+type reportRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewReportRepo .
+func NewReportRepo(data *Data, logger log.Logger) biz.ReportRepo {
+	return &reportRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *reportRepo) CreateReport(ctx context.Context, rp *biz.Report, author int64) (count int32, created bool, err error) {
+	now := time.Now()
+	err = r.data.tx(ctx, func(tx *sql.Tx) error {
+		// 同一举报人重复举报时不插入, 举报和统计都不先查询, 并发举报由唯一键保证只计一次
+		res, err := tx.ExecContext(ctx, `INSERT INTO comment_report
+			(comment_id, obj_id, obj_type, member_id, reason, content, create_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`+r.data.onDuplicate("comment_id, member_id", ""),
+			rp.CommentID, rp.ObjID, rp.ObjType, rp.MemberID, rp.Reason, rp.Content, now)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return tx.QueryRowContext(ctx, `SELECT count FROM comment_report_stat WHERE comment_id = ?`,
+				rp.CommentID).Scan(&count)
+		}
+		if rp.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		rp.CreateTime, created = now, true
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_report_stat
+			(comment_id, obj_id, obj_type, member_id, count, hidden, create_time, update_time)
+			VALUES (?, ?, ?, ?, 1, 0, ?, ?)`+r.data.onDuplicate("comment_id", "count = count + 1, update_time = ?"),
+			rp.CommentID, rp.ObjID, rp.ObjType, author, now, now, now)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRowContext(ctx, `SELECT count FROM comment_report_stat WHERE comment_id = ?`,
+			rp.CommentID).Scan(&count)
+	})
+	return
+}
+
+func (r *reportRepo) ListReportStat(ctx context.Context, offset, limit int) ([]*biz.ReportStat, int32, error) {
+	var total int32
+	if err := r.data.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comment_report_stat`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.data.db.QueryContext(ctx, `SELECT comment_id, obj_id, obj_type, member_id, count, hidden, update_time
+		FROM comment_report_stat ORDER BY count DESC, update_time DESC LIMIT ? OFFSET ?`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var (
+		stats []*biz.ReportStat
+		ids   []interface{}
+		m     = make(map[int64]*biz.ReportStat)
+	)
+	for rows.Next() {
+		s := &biz.ReportStat{Reasons: make(map[int32]int32)}
+		if err = rows.Scan(&s.CommentID, &s.ObjID, &s.ObjType, &s.MemberID, &s.Count, &s.Hidden, &s.UpdateTime); err != nil {
+			return nil, 0, err
+		}
+		stats = append(stats, s)
+		ids = append(ids, s.CommentID)
+		m[s.CommentID] = s
+	}
+	if err = rows.Err(); err != nil || len(stats) == 0 {
+		return stats, total, err
+	}
+
+	rows, err = r.data.db.QueryContext(ctx, `SELECT comment_id, reason, COUNT(*) FROM comment_report
+		WHERE comment_id IN (`+placeholders(len(ids))+`) GROUP BY comment_id, reason`, ids...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id            int64
+			reason, count int32
+		)
+		if err = rows.Scan(&id, &reason, &count); err != nil {
+			return nil, 0, err
+		}
+		m[id].Reasons[reason] = count
+	}
+	return stats, total, rows.Err()
+}
+
+func (r *reportRepo) ListMemberReport(ctx context.Context, memberID, cursor int64, limit int) ([]*biz.Report, error) {
+	rows, err := r.data.db.QueryContext(ctx, `SELECT id, comment_id, obj_id, obj_type, member_id, reason, content, create_time
+		FROM comment_report WHERE member_id = ? AND id > ? ORDER BY id LIMIT ?`, memberID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rs []*biz.Report
+	for rows.Next() {
+		rp := new(biz.Report)
+		err = rows.Scan(&rp.ID, &rp.CommentID, &rp.ObjID, &rp.ObjType, &rp.MemberID, &rp.Reason, &rp.Content, &rp.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, rp)
+	}
+	return rs, rows.Err()
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func TestCreateReport(t *testing.T) {
+	const n = 10
+	var (
+		ctx  = context.Background()
+		repo = NewReportRepo(newTestData(t), log.DefaultLogger)
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		news int
+	)
+	// every member reports twice at the same time
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func(member int64) {
+				defer wg.Done()
+				_, created, err := repo.CreateReport(ctx, &biz.Report{CommentID: 1, ObjID: 1, ObjType: 1, MemberID: member}, 9)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+				} else if created {
+					news++
+				}
+			}(int64(100 + i))
+		}
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		t.Fatal(errs[0])
+	}
+	if news != n {
+		t.Fatalf("got %d new reports from %d members", news, n)
+	}
+	count, created, err := repo.CreateReport(ctx, &biz.Report{CommentID: 1, ObjID: 1, ObjType: 1, MemberID: 100}, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || count != n {
+		t.Fatalf("got count %d created %v of a repeated report", count, created)
+	}
+}
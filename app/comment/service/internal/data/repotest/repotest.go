@@ -0,0 +1,611 @@
+// Package repotest is the contract every backend of the subject, comment
+// and like repos must pass, so the backends behave the same.
+package repotest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// Repos are the repos of a backend sharing one empty store.
+type Repos struct {
+	Subject biz.SubjectRepo
+	Comment biz.CommentRepo
+	Like    biz.LikeRepo
+}
+
+// Run runs the contract against the backend, open returns the repos on an
+// empty store for each test.
+func Run(t *testing.T, open func(t *testing.T) *Repos) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r *Repos)
+	}{
+		{"Subject", testSubject},
+		{"Floor", testFloor},
+		{"Count", testCount},
+		{"SoftDelete", testSoftDelete},
+		{"Order", testOrder},
+		{"HotOrder", testHotOrder},
+		{"MutedCount", testMutedCount},
+		{"MemberComment", testMemberComment},
+		{"Rank", testRank},
+		{"RankPage", testRankPage},
+		{"Edit", testEdit},
+		{"Like", testLike},
+		{"ConcurrentCreate", testConcurrentCreate},
+		{"ConcurrentLike", testConcurrentLike},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, open(t))
+		})
+	}
+}
+
+var ctx = context.Background()
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createSubject(t *testing.T, r *Repos, objID int64) {
+	t.Helper()
+	must(t, r.Subject.CreateSubject(ctx, &biz.Subject{ObjID: objID, ObjType: 1, MemberID: 100}))
+}
+
+func createComment(t *testing.T, r *Repos, c *biz.Comment) *biz.Comment {
+	t.Helper()
+	if c.ObjType == 0 {
+		c.ObjType = 1
+	}
+	if c.Message == "" {
+		c.Message = "message"
+	}
+	must(t, r.Comment.CreateComment(ctx, c))
+	return c
+}
+
+func getComment(t *testing.T, r *Repos, id int64) *biz.Comment {
+	t.Helper()
+	c, err := r.Comment.GetComment(ctx, id)
+	must(t, err)
+	return c
+}
+
+func getSubject(t *testing.T, r *Repos, objID int64) *biz.Subject {
+	t.Helper()
+	s, err := r.Subject.GetSubject(ctx, objID, 1)
+	must(t, err)
+	return s
+}
+
+func ids(cs []*biz.Comment) []int64 {
+	ids := make([]int64, 0, len(cs))
+	for _, c := range cs {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testSubject(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	must(t, r.Subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 2, MemberID: 101}))
+	createSubject(t, r, 2)
+	if err := r.Subject.CreateSubject(ctx, &biz.Subject{ObjID: 1, ObjType: 1}); err == nil {
+		t.Error("created a subject twice")
+	}
+	s := getSubject(t, r, 1)
+	if s.MemberID != 100 || s.Count != 0 || s.RootCount != 0 || s.AllCount != 0 {
+		t.Errorf("got subject %+v", s)
+	}
+	if _, err := r.Subject.GetSubject(ctx, 3, 1); err != biz.ErrSubjectNotFound {
+		t.Errorf("got error %v for a missing subject", err)
+	}
+	ss, err := r.Subject.ListSubject(ctx, []int64{1, 3})
+	must(t, err)
+	if len(ss) != 2 {
+		t.Errorf("got %d subjects of obj 1", len(ss))
+	}
+}
+
+func testFloor(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	createSubject(t, r, 2)
+	var roots []*biz.Comment
+	for i := int64(1); i <= 3; i++ {
+		c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+		if c.Floor != i {
+			t.Errorf("root %d got floor %d", i, c.Floor)
+		}
+		roots = append(roots, c)
+	}
+	if c := createComment(t, r, &biz.Comment{ObjID: 2, MemberID: 1}); c.Floor != 1 {
+		t.Errorf("root of another subject got floor %d", c.Floor)
+	}
+	// a pending comment takes a floor without being counted
+	pending := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1, State: biz.CommentStatePending})
+	if pending.Floor != 4 {
+		t.Errorf("pending root got floor %d", pending.Floor)
+	}
+
+	root := roots[0]
+	for i := int64(1); i <= 3; i++ {
+		c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 2, Root: root.ID, Parent: root.ID})
+		if c.Floor != i {
+			t.Errorf("reply %d got floor %d", i, c.Floor)
+		}
+	}
+	if c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1, Root: roots[1].ID, Parent: roots[1].ID}); c.Floor != 1 {
+		t.Errorf("reply of another root got floor %d", c.Floor)
+	}
+	if s := getSubject(t, r, 1); s.Count != 4 {
+		t.Errorf("got subject floor count %d", s.Count)
+	}
+}
+
+func testCount(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	root := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 2})
+	createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 3, State: biz.CommentStatePending})
+	createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 2, Root: root.ID, Parent: root.ID})
+	createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 3, Root: root.ID, Parent: root.ID, State: biz.CommentStatePending})
+
+	s := getSubject(t, r, 1)
+	if s.Count != 3 || s.RootCount != 2 || s.AllCount != 3 {
+		t.Errorf("got subject counts %d %d %d, want 3 2 3", s.Count, s.RootCount, s.AllCount)
+	}
+	c := getComment(t, r, root.ID)
+	if c.Count != 2 || c.RootCount != 1 {
+		t.Errorf("got root counts %d %d, want 2 1", c.Count, c.RootCount)
+	}
+
+	n, err := r.Comment.CountComment(ctx, 1, 1, []int64{1, 3})
+	must(t, err)
+	if n != 1 {
+		t.Errorf("counted %d normal roots of members 1 and 3", n)
+	}
+	counts, err := r.Comment.CountReply(ctx, []int64{root.ID}, []int64{2, 3})
+	must(t, err)
+	if counts[root.ID] != 1 {
+		t.Errorf("counted %d normal replies of members 2 and 3", counts[root.ID])
+	}
+}
+
+func testSoftDelete(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	root := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	reply := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 2, Root: root.ID, Parent: root.ID})
+
+	for i := 0; i < 2; i++ {
+		must(t, r.Comment.DeleteComment(ctx, reply))
+	}
+	if c := getComment(t, r, reply.ID); c.State != biz.CommentStateDeleted || c.Message != reply.Message {
+		t.Errorf("got deleted reply in state %d", c.State)
+	}
+	if c := getComment(t, r, root.ID); c.Count != 1 || c.RootCount != 0 {
+		t.Errorf("got root counts %d %d after deleting the reply", c.Count, c.RootCount)
+	}
+	must(t, r.Comment.DeleteComment(ctx, root))
+	if s := getSubject(t, r, 1); s.Count != 1 || s.RootCount != 0 || s.AllCount != 0 {
+		t.Errorf("got subject counts %d %d %d after deleting all", s.Count, s.RootCount, s.AllCount)
+	}
+
+	cs, err := r.Comment.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 10)
+	must(t, err)
+	if len(cs) != 0 {
+		t.Errorf("listed %d deleted roots", len(cs))
+	}
+	cs, err = r.Comment.ListCommentByID(ctx, []int64{root.ID, reply.ID, reply.ID + 100})
+	must(t, err)
+	if len(cs) != 2 {
+		t.Errorf("got %d deleted comments by id", len(cs))
+	}
+	if _, err = r.Comment.GetComment(ctx, reply.ID+100); err != biz.ErrCommentNotFound {
+		t.Errorf("got error %v for a missing comment", err)
+	}
+}
+
+func testOrder(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	var roots []int64
+	for i := 0; i < 5; i++ {
+		roots = append(roots, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2)}).ID)
+	}
+	var replies []int64
+	for i := 0; i < 4; i++ {
+		replies = append(replies, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2),
+			Root: roots[0], Parent: roots[0]}).ID)
+	}
+
+	page1, err := r.Comment.ListComment(ctx, 1, 1, biz.SortFloor, nil, 0, 2)
+	must(t, err)
+	page3, err := r.Comment.ListComment(ctx, 1, 1, biz.SortFloor, nil, 4, 2)
+	must(t, err)
+	if !equal(ids(page1), []int64{roots[4], roots[3]}) || !equal(ids(page3), []int64{roots[0]}) {
+		t.Errorf("got roots %v and %v, want floor desc", ids(page1), ids(page3))
+	}
+	cs, err := r.Comment.ListComment(ctx, 1, 1, biz.SortFloor, []int64{2}, 0, 10)
+	must(t, err)
+	if !equal(ids(cs), []int64{roots[4], roots[2], roots[0]}) {
+		t.Errorf("got roots %v excluding member 2", ids(cs))
+	}
+
+	cs, err = r.Comment.ListReply(ctx, roots[0], biz.SortFloor, nil, 1, 2)
+	must(t, err)
+	if !equal(ids(cs), replies[1:3]) {
+		t.Errorf("got replies %v, want floor asc", ids(cs))
+	}
+	cs, err = r.Comment.ListReply(ctx, roots[0], biz.SortFloor, []int64{1}, 0, 10)
+	must(t, err)
+	if !equal(ids(cs), []int64{replies[1], replies[3]}) {
+		t.Errorf("got replies %v excluding member 1", ids(cs))
+	}
+}
+
+func testHotOrder(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	var roots []int64
+	for i := 0; i < 4; i++ {
+		roots = append(roots, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2)}).ID)
+	}
+	list := func(excludes []int64) []int64 {
+		cs, err := r.Comment.ListComment(ctx, 1, 1, biz.SortHot, excludes, 0, 10)
+		must(t, err)
+		return ids(cs)
+	}
+	if got := list(nil); !equal(got, []int64{roots[3], roots[2], roots[1], roots[0]}) {
+		t.Errorf("got roots %v without likes, want floor desc", got)
+	}
+
+	like := func(id, memberID int64) {
+		must(t, r.Like.SaveLike(ctx, &biz.Like{CommentID: id, MemberID: memberID, Action: biz.LikeActionLike}))
+	}
+	like(roots[0], 10)
+	like(roots[1], 10)
+	like(roots[1], 11)
+	var replies []*biz.Comment
+	for i := 0; i < 2; i++ {
+		replies = append(replies, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 3,
+			Root: roots[2], Parent: roots[2]}))
+	}
+	if got := list(nil); !equal(got, []int64{roots[2], roots[1], roots[0], roots[3]}) {
+		t.Errorf("got roots %v, want likes and replies desc then floor desc", got)
+	}
+	if got := list([]int64{1}); !equal(got, []int64{roots[1], roots[3]}) {
+		t.Errorf("got roots %v excluding member 1", got)
+	}
+
+	like(replies[1].ID, 10)
+	cs, err := r.Comment.ListReply(ctx, roots[2], biz.SortHot, nil, 0, 10)
+	must(t, err)
+	if !equal(ids(cs), []int64{replies[1].ID, replies[0].ID}) {
+		t.Errorf("got replies %v, want likes desc", ids(cs))
+	}
+
+	must(t, r.Comment.DeleteComment(ctx, replies[0]))
+	like(roots[1], 10)
+	must(t, r.Like.SaveLike(ctx, &biz.Like{CommentID: roots[1], MemberID: 11, Action: biz.LikeActionCancel}))
+	if got := list(nil); !equal(got, []int64{roots[2], roots[1], roots[0], roots[3]}) {
+		t.Errorf("got roots %v after a reply was deleted and a like cancelled", got)
+	}
+}
+
+func testMutedCount(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	var roots []*biz.Comment
+	for _, memberID := range []int64{1, 2, 2, 3} {
+		roots = append(roots, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: memberID}))
+	}
+	var replies []*biz.Comment
+	for _, memberID := range []int64{2, 2, 1} {
+		replies = append(replies, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: memberID,
+			Root: roots[0].ID, Parent: roots[0].ID}))
+	}
+	must(t, r.Comment.DeleteComment(ctx, roots[3]))
+	must(t, r.Comment.DeleteComment(ctx, replies[1]))
+
+	for _, tc := range []struct {
+		muted []int64
+		want  int32
+	}{
+		{[]int64{2}, 2},
+		{[]int64{2, 3}, 2},
+		{[]int64{1, 2}, 3},
+		{[]int64{4}, 0},
+	} {
+		n, err := r.Comment.CountComment(ctx, 1, 1, tc.muted)
+		must(t, err)
+		if n != tc.want {
+			t.Errorf("counted %d roots of members %v, want %d", n, tc.muted, tc.want)
+		}
+	}
+	counts, err := r.Comment.CountReply(ctx, []int64{roots[0].ID, roots[1].ID}, []int64{2})
+	must(t, err)
+	if counts[roots[0].ID] != 1 || counts[roots[1].ID] != 0 {
+		t.Errorf("counted replies %v of member 2", counts)
+	}
+	cs, err := r.Comment.ListReply(ctx, roots[0].ID, biz.SortFloor, []int64{2}, 0, 10)
+	must(t, err)
+	if !equal(ids(cs), []int64{replies[2].ID}) {
+		t.Errorf("got replies %v excluding member 2", ids(cs))
+	}
+}
+
+func testMemberComment(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	must(t, r.Subject.CreateSubject(ctx, &biz.Subject{ObjID: 2, ObjType: 2}))
+	var want []int64
+	for i := 0; i < 5; i++ {
+		c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 7})
+		want = append([]int64{c.ID}, want...)
+	}
+	other := createComment(t, r, &biz.Comment{ObjID: 2, ObjType: 2, MemberID: 7})
+	createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 8})
+	pending := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 7, State: biz.CommentStatePending})
+
+	var (
+		got []int64
+		f   = &biz.MemberCommentFilter{MemberID: 7, ObjType: 1, Limit: 2}
+	)
+	for {
+		cs, err := r.Comment.ListMemberComment(ctx, f, []int8{biz.CommentStateNormal})
+		must(t, err)
+		got = append(got, ids(cs)...)
+		if len(cs) < f.Limit {
+			break
+		}
+		f.Cursor = cs[len(cs)-1].ID
+	}
+	if !equal(got, want) {
+		t.Errorf("got member comments %v, want %v", got, want)
+	}
+
+	cs, err := r.Comment.ListMemberComment(ctx, &biz.MemberCommentFilter{MemberID: 7, Limit: 10},
+		[]int8{biz.CommentStateNormal, biz.CommentStatePending})
+	must(t, err)
+	if len(cs) != 7 || cs[0].ID != pending.ID {
+		t.Errorf("got %d member comments in all obj types and states", len(cs))
+	}
+	for _, c := range cs {
+		if c.ID == other.ID && c.ObjType != 2 {
+			t.Errorf("got obj type %d", c.ObjType)
+		}
+	}
+}
+
+func testRank(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	var roots []*biz.Comment
+	for i := 0; i < 4; i++ {
+		roots = append(roots, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2)}))
+	}
+	n, err := r.Comment.RankComment(ctx, 1, 1, roots[1].Floor, nil)
+	must(t, err)
+	if n != 2 {
+		t.Errorf("root on floor 2 ranked %d", n)
+	}
+	if n, err = r.Comment.RankComment(ctx, 1, 1, roots[0].Floor, []int64{2}); err != nil || n != 1 {
+		t.Errorf("root on floor 1 ranked %d excluding member 2, error %v", n, err)
+	}
+
+	var replies []*biz.Comment
+	for i := 0; i < 3; i++ {
+		replies = append(replies, createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 3,
+			Root: roots[0].ID, Parent: roots[0].ID}))
+	}
+	must(t, r.Comment.DeleteComment(ctx, replies[0]))
+	if n, err = r.Comment.RankReply(ctx, roots[0].ID, replies[2].Floor, nil); err != nil || n != 1 {
+		t.Errorf("reply on floor 3 ranked %d, error %v", n, err)
+	}
+}
+
+// testRankPage checks the page and index of each normal comment computed
+// from its rank against the pages listed, with hidden and deleted comments
+// before it.
+func testRankPage(t *testing.T, r *Repos) {
+	const size = 2
+	createSubject(t, r, 1)
+	var roots []*biz.Comment
+	for i := 0; i < 7; i++ {
+		c := &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2)}
+		if i == 5 {
+			c.State = biz.CommentStatePending
+		}
+		roots = append(roots, createComment(t, r, c))
+	}
+	must(t, r.Comment.DeleteComment(ctx, roots[4]))
+	roots[4] = getComment(t, r, roots[4].ID)
+	var replies []*biz.Comment
+	for i := 0; i < 7; i++ {
+		c := &biz.Comment{ObjID: 1, MemberID: int64(1 + i%2), Root: roots[0].ID, Parent: roots[0].ID}
+		if i == 1 {
+			c.State = biz.CommentStatePending
+		}
+		replies = append(replies, createComment(t, r, c))
+	}
+	for _, i := range []int{0, 4} {
+		must(t, r.Comment.DeleteComment(ctx, replies[i]))
+		replies[i] = getComment(t, r, replies[i].ID)
+	}
+
+	for _, excludes := range [][]int64{nil, {2}} {
+		for _, c := range roots {
+			if c.State != biz.CommentStateNormal || (len(excludes) > 0 && c.MemberID == excludes[0]) {
+				continue
+			}
+			n, err := r.Comment.RankComment(ctx, 1, 1, c.Floor, excludes)
+			must(t, err)
+			cs, err := r.Comment.ListComment(ctx, 1, 1, biz.SortFloor, excludes, int(n/size*size), size)
+			must(t, err)
+			if i := int(n % size); i >= len(cs) || cs[i].ID != c.ID {
+				t.Errorf("root on floor %d ranked %d excluding %v, got page %v", c.Floor, n, excludes, ids(cs))
+			}
+		}
+		for _, c := range replies {
+			if c.State != biz.CommentStateNormal || (len(excludes) > 0 && c.MemberID == excludes[0]) {
+				continue
+			}
+			n, err := r.Comment.RankReply(ctx, roots[0].ID, c.Floor, excludes)
+			must(t, err)
+			cs, err := r.Comment.ListReply(ctx, roots[0].ID, biz.SortFloor, excludes, int(n/size*size), size)
+			must(t, err)
+			if i := int(n % size); i >= len(cs) || cs[i].ID != c.ID {
+				t.Errorf("reply on floor %d ranked %d excluding %v, got page %v", c.Floor, n, excludes, ids(cs))
+			}
+		}
+	}
+}
+
+func testEdit(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1, Message: "v1", AtMemberIDs: []int64{2}})
+	c.Message, c.AtMemberIDs = "v2", nil
+	must(t, r.Comment.EditComment(ctx, c))
+	c.Message = "v3"
+	must(t, r.Comment.EditComment(ctx, c))
+
+	got := getComment(t, r, c.ID)
+	if got.Message != "v3" || len(got.AtMemberIDs) != 0 || got.EditTime.IsZero() {
+		t.Errorf("got edited comment %q %v %v", got.Message, got.AtMemberIDs, got.EditTime)
+	}
+	vs, err := r.Comment.ListCommentVersion(ctx, c.ID)
+	must(t, err)
+	if len(vs) != 2 || vs[0].Message != "v2" || vs[1].Message != "v1" || len(vs[1].AtMemberIDs) != 1 {
+		t.Fatalf("got %d versions", len(vs))
+	}
+	if vs[0].CreateTime.Before(vs[1].CreateTime) {
+		t.Errorf("version v2 published at %v before v1 at %v", vs[0].CreateTime, vs[1].CreateTime)
+	}
+}
+
+func testLike(t *testing.T, r *Repos) {
+	createSubject(t, r, 1)
+	c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	steps := []struct {
+		memberID   int64
+		action     int8
+		like, hate int32
+	}{
+		{2, biz.LikeActionLike, 1, 0},
+		{2, biz.LikeActionLike, 1, 0},
+		{3, biz.LikeActionHate, 1, 1},
+		{2, biz.LikeActionHate, 0, 2},
+		{3, biz.LikeActionCancel, 0, 1},
+		{3, biz.LikeActionCancel, 0, 1},
+	}
+	for i, s := range steps {
+		must(t, r.Like.SaveLike(ctx, &biz.Like{CommentID: c.ID, MemberID: s.memberID, Action: s.action}))
+		if got := getComment(t, r, c.ID); got.Like != s.like || got.Hate != s.hate {
+			t.Errorf("step %d: got like %d hate %d, want %d %d", i, got.Like, got.Hate, s.like, s.hate)
+		}
+	}
+
+	c2 := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	must(t, r.Like.SaveLike(ctx, &biz.Like{CommentID: c2.ID, MemberID: 2, Action: biz.LikeActionLike}))
+	ls, err := r.Like.ListMemberLike(ctx, 2, 0, 1)
+	must(t, err)
+	if len(ls) != 1 || ls[0].CommentID != c.ID || ls[0].Action != biz.LikeActionHate {
+		t.Fatalf("got first like %+v", ls)
+	}
+	if ls, err = r.Like.ListMemberLike(ctx, 2, ls[0].ID, 10); err != nil || len(ls) != 1 || ls[0].CommentID != c2.ID {
+		t.Errorf("got %d likes after the cursor, error %v", len(ls), err)
+	}
+}
+
+func testConcurrentCreate(t *testing.T, r *Repos) {
+	const n = 20
+	createSubject(t, r, 1)
+	root := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		floors = make(map[int64]bool)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: int64(i), Message: "reply"}
+			if i%2 == 0 {
+				c.Root, c.Parent = root.ID, root.ID
+			}
+			err := r.Comment.CreateComment(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			floors[c.Root<<32|c.Floor] = true
+		}(i)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		t.Fatal(errs[0])
+	}
+	if len(floors) != n {
+		t.Errorf("got %d distinct floors for %d comments", len(floors), n)
+	}
+	s := getSubject(t, r, 1)
+	if s.Count != n/2+1 || s.RootCount != n/2+1 || s.AllCount != n+1 {
+		t.Errorf("got subject counts %d %d %d", s.Count, s.RootCount, s.AllCount)
+	}
+	if c := getComment(t, r, root.ID); c.Count != n/2 || c.RootCount != n/2 {
+		t.Errorf("got root counts %d %d", c.Count, c.RootCount)
+	}
+}
+
+func testConcurrentLike(t *testing.T, r *Repos) {
+	const n = 20
+	createSubject(t, r, 1)
+	c := createComment(t, r, &biz.Comment{ObjID: 1, MemberID: 1})
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every member likes twice
+			for j := 0; j < 2; j++ {
+				err := r.Like.SaveLike(ctx, &biz.Like{CommentID: c.ID, MemberID: int64(100 + i), Action: biz.LikeActionLike})
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		t.Fatal(errs[0])
+	}
+	if got := getComment(t, r, c.ID); got.Like != n {
+		t.Errorf("got %d likes from %d members", got.Like, n)
+	}
+}
@@ -0,0 +1,141 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// The subjects, comment indexes, comment contents and content histories
+// are sharded by subject, the rest of the tables are on the main database.
+// A comment id embeds its shard as id % shards, so a comment is found
+// without its subject.
+//
+// Writes touching both a shard and the main database, such as a comment
+// with its attachments, run in one transaction when the shard is on the main
+// database. Otherwise the shard transaction commits first with the event of
+// the change in the outbox of the shard, and the comment job redoes the
+// writes on the main database from the event when their commit failed.
+
+// openShards opens the shard databases and maps the logical shards to them.
+func (d *Data) openShards(c *conf.Data_Sharding) error {
+	d.shards = int64(c.GetShards())
+	if d.shards <= 0 {
+		d.shards = 1
+	}
+	for _, dc := range c.GetDatabases() {
+		db, err := openDB(dc)
+		if err != nil {
+			return err
+		}
+		d.shardDBs = append(d.shardDBs, db)
+	}
+	if len(d.shardDBs) == 0 {
+		d.shardDBs = []*sql.DB{d.db}
+	}
+
+	m := c.GetShardMap()
+	if len(m) != 0 && int64(len(m)) != d.shards {
+		return fmt.Errorf("shard map has %d shards, want %d", len(m), d.shards)
+	}
+	d.shardMap = make([]*sql.DB, d.shards)
+	for i := range d.shardMap {
+		n := i % len(d.shardDBs)
+		if len(m) != 0 {
+			n = int(m[i])
+		}
+		if n < 0 || n >= len(d.shardDBs) {
+			return fmt.Errorf("shard %d is mapped to unknown database %d", i, n)
+		}
+		d.shardMap[i] = d.shardDBs[n]
+	}
+	return nil
+}
+
+// subjectShard returns the shard of the subject by the hash of obj_id and obj_type.
+func (d *Data) subjectShard(objID int64, objType int32) int64 {
+	if d.shards == 1 {
+		return 0
+	}
+	var b [12]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(objID))
+	binary.BigEndian.PutUint32(b[8:], uint32(objType))
+	h := fnv.New64a()
+	_, _ = h.Write(b[:])
+	return int64(h.Sum64() % uint64(d.shards))
+}
+
+// commentShard returns the shard embedded in the comment id.
+func (d *Data) commentShard(id int64) int64 {
+	return id % d.shards
+}
+
+// subjectDB returns the database holding the subject and its comments.
+func (d *Data) subjectDB(objID int64, objType int32) *sql.DB {
+	return d.shardMap[d.subjectShard(objID, objType)]
+}
+
+// commentDB returns the database holding the comment.
+func (d *Data) commentDB(id int64) *sql.DB {
+	return d.shardMap[d.commentShard(id)]
+}
+
+// groupComment groups the comment ids by the database holding them.
+func (d *Data) groupComment(ids []int64) map[*sql.DB][]interface{} {
+	g := make(map[*sql.DB][]interface{})
+	for _, id := range ids {
+		db := d.commentDB(id)
+		g[db] = append(g[db], id)
+	}
+	return g
+}
+
+// nextCommentID allocates a comment id on the shard in tx, it is a
+// sequence of the shard database times the number of shards plus the shard.
+func (d *Data) nextCommentID(ctx context.Context, tx *sql.Tx, shard int64) (int64, error) {
+	res, err := tx.ExecContext(ctx, `INSERT INTO comment_id_seq (create_time) VALUES (?)`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	seq, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return seq*d.shards + shard, nil
+}
+
+// crossTx runs fn with a transaction on the main database and one on the
+// database of the shard, they are the same transaction when the shard is
+// on the main database.
+func (d *Data) crossTx(ctx context.Context, shard int64, fn func(main, shard *sql.Tx) error) error {
+	db := d.shardMap[shard]
+	if db == d.db {
+		return d.tx(ctx, func(tx *sql.Tx) error {
+			return fn(tx, tx)
+		})
+	}
+	stx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	mtx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		_ = stx.Rollback()
+		return err
+	}
+	if err = fn(mtx, stx); err != nil {
+		_ = stx.Rollback()
+		_ = mtx.Rollback()
+		return err
+	}
+	if err = stx.Commit(); err != nil {
+		_ = mtx.Rollback()
+		return err
+	}
+	return mtx.Commit()
+}
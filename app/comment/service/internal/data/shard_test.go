@@ -0,0 +1,188 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// newShardedTestData opens a main database and n shard databases in
+// separate SQLite files.
+func newShardedTestData(t *testing.T, shards int32, n int, shardMap []int32) (*Data, error) {
+	t.Helper()
+	var (
+		dir = t.TempDir()
+		dc  = func(name string) *conf.Data_Database {
+			return &conf.Data_Database{
+				Driver:  "sqlite3",
+				Source:  "file:" + filepath.Join(dir, name+".db"),
+				Migrate: true,
+			}
+		}
+		c = &conf.Data{
+			Database: dc("main"),
+			Sharding: &conf.Data_Sharding{Shards: shards, ShardMap: shardMap},
+		}
+	)
+	for i := 0; i < n; i++ {
+		c.Sharding.Databases = append(c.Sharding.Databases, dc(fmt.Sprintf("shard%d", i)))
+	}
+	d, cleanup, err := NewData(c, log.DefaultLogger)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(cleanup)
+	return d, nil
+}
+
+func TestShardConfig(t *testing.T) {
+	if _, err := newShardedTestData(t, 4, 2, []int32{0, 1}); err == nil {
+		t.Error("opened a shard map shorter than the shards")
+	}
+	if _, err := newShardedTestData(t, 2, 2, []int32{0, 2}); err == nil {
+		t.Error("opened a shard map with an unknown database")
+	}
+	d, err := newShardedTestData(t, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.shards != 1 || d.commentDB(42) != d.db {
+		t.Errorf("got %d shards without sharding", d.shards)
+	}
+}
+
+func TestShardedComment(t *testing.T) {
+	ctx := context.Background()
+	d, err := newShardedTestData(t, 4, 2, []int32{0, 1, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		subject = NewSubjectRepo(d, log.DefaultLogger)
+		repo    = NewCommentRepo(d, log.DefaultLogger)
+		like    = NewLikeRepo(d, log.DefaultLogger)
+		objIDs  []int64
+		ids     []int64
+	)
+	for obj := int64(1); obj <= 8; obj++ {
+		if err = subject.CreateSubject(ctx, &biz.Subject{ObjID: obj, ObjType: 1, MemberID: 9}); err != nil {
+			t.Fatal(err)
+		}
+		c := &biz.Comment{ObjID: obj, ObjType: 1, MemberID: 7, Message: "root"}
+		if err = repo.CreateComment(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		if shard := d.subjectShard(obj, 1); d.commentShard(c.ID) != shard {
+			t.Fatalf("comment %d of shard %d embeds shard %d", c.ID, shard, d.commentShard(c.ID))
+		}
+		objIDs = append(objIDs, obj)
+		ids = append(ids, c.ID)
+	}
+	reply := &biz.Comment{ObjID: 1, ObjType: 1, MemberID: 7, Root: ids[0], Parent: ids[0], Message: "reply"}
+	if err = repo.CreateComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, reply.ID)
+
+	// the rows are only on the database of their shard
+	for i, db := range d.shardDBs {
+		var n int
+		if err = db.QueryRow(`SELECT COUNT(*) FROM comment_index`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			t.Errorf("no comments on shard database %d", i)
+		}
+	}
+	var n int
+	if err = d.db.QueryRow(`SELECT COUNT(*) FROM comment_index`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("got %d comments on the main database, error %v", n, err)
+	}
+	// the events are in the outbox of the shard with the comments
+	var events int
+	for i, db := range d.shardDBs {
+		if err = db.QueryRow(`SELECT COUNT(*) FROM comment_event`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			t.Errorf("no events on shard database %d", i)
+		}
+		events += n
+	}
+	if events != len(ids) {
+		t.Errorf("got %d events on the shard databases, want %d", events, len(ids))
+	}
+	if err = d.db.QueryRow(`SELECT COUNT(*) FROM comment_event`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("got %d events on the main database, error %v", n, err)
+	}
+
+	for _, id := range ids {
+		if _, err = repo.GetComment(ctx, id); err != nil {
+			t.Fatalf("get comment %d: %v", id, err)
+		}
+	}
+	cs, err := repo.ListCommentByID(ctx, ids)
+	if err != nil || len(cs) != len(ids) {
+		t.Fatalf("got %d comments by id, error %v", len(cs), err)
+	}
+	rs, err := repo.ListReply(ctx, ids[0], biz.SortFloor, nil, 0, 10)
+	if err != nil || len(rs) != 1 {
+		t.Fatalf("got %d replies, error %v", len(rs), err)
+	}
+	ss, err := subject.ListSubject(ctx, objIDs)
+	if err != nil || len(ss) != len(objIDs) {
+		t.Fatalf("got %d subjects, error %v", len(ss), err)
+	}
+
+	// member comments are merged across the shards in create time and id desc order,
+	// the reply with the largest id is backdated to be listed last
+	_, err = d.commentDB(reply.ID).Exec(`UPDATE comment_index SET create_time = ? WHERE id = ?`,
+		time.Now().Add(-time.Hour), reply.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		f    = &biz.MemberCommentFilter{MemberID: 7, Limit: 4}
+		seen = make(map[int64]bool)
+		last *biz.Comment
+	)
+	for {
+		cs, err = repo.ListMemberComment(ctx, f, []int8{biz.CommentStateNormal})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range cs {
+			if last != nil && !newer(last, c) || seen[c.ID] {
+				t.Fatalf("comment %d listed after %d", c.ID, last.ID)
+			}
+			seen[c.ID], last = true, c
+		}
+		if len(cs) < f.Limit {
+			break
+		}
+		f.Cursor = last.ID
+	}
+	if len(seen) != len(ids) || last.ID != reply.ID {
+		t.Fatalf("listed %d member comments ending with %d, want %d ending with %d", len(seen), last.ID, len(ids), reply.ID)
+	}
+
+	if err = like.SaveLike(ctx, &biz.Like{CommentID: reply.ID, MemberID: 8, Action: biz.LikeActionLike}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := repo.GetComment(ctx, reply.ID)
+	if err != nil || c.Like != 1 {
+		t.Fatalf("got %d likes, error %v", c.Like, err)
+	}
+	if err = repo.DeleteComment(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = repo.GetComment(ctx, ids[0]); err != nil || c.RootCount != 0 {
+		t.Fatalf("got root count %d after delete, error %v", c.RootCount, err)
+	}
+}
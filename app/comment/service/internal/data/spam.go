@@ -0,0 +1,204 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// 共享的评论指纹:
+//   comment:spam:{key} 指纹的有序集合, 分数为创建的毫秒时间, 成员为 hash:simhash:字数:创建的纳秒时间
+// 检查和写入在 WATCH 的事务里, 并发写入同一个 key 时重试, 只保留窗口内最近的
+// maxFingerprints 个指纹.
+
+// maxFingerprints is the maximum number of fingerprints kept for a key.
+const maxFingerprints = 64
+
+// spamRetries is the times an add raced by a concurrent add is retried.
+const spamRetries = 3
+
+type fingerprints struct {
+	list   []*biz.Fingerprint
+	expire time.Time
+}
+
+// spamRepo is an in-memory fingerprint store, it detects per instance.
+type spamRepo struct {
+	mu    sync.Mutex
+	keys  map[string]*fingerprints
+	sweep time.Time
+	log   *log.Helper
+}
+
+// NewSpamRepo returns the fingerprints in redis when the limit is shared,
+// or in memory otherwise.
+func NewSpamRepo(data *Data, logger log.Logger) biz.SpamRepo {
+	if data.sharedLimit {
+		return &redisSpamRepo{rdb: data.rdb, log: log.NewHelper(logger)}
+	}
+	return &spamRepo{
+		keys:  make(map[string]*fingerprints),
+		sweep: time.Now(),
+		log:   log.NewHelper(logger),
+	}
+}
+
+func (r *spamRepo) AddFingerprint(ctx context.Context, keys []string, f *biz.Fingerprint, since time.Time, ttl time.Duration,
+	add func(fps [][]*biz.Fingerprint) bool) error {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Sub(r.sweep) >= bucketSweepInterval {
+		for k, fps := range r.keys {
+			if now.After(fps.expire) {
+				delete(r.keys, k)
+			}
+		}
+		r.sweep = now
+	}
+
+	lists := make([][]*biz.Fingerprint, 0, len(keys))
+	for _, key := range keys {
+		var list []*biz.Fingerprint
+		if fps, ok := r.keys[key]; ok {
+			for _, f := range fps.list {
+				if f.CreateTime.After(since) {
+					list = append(list, f)
+				}
+			}
+		}
+		lists = append(lists, list)
+	}
+	if !add(lists) {
+		return nil
+	}
+	for _, key := range keys {
+		fps, ok := r.keys[key]
+		if !ok {
+			fps = new(fingerprints)
+			r.keys[key] = fps
+		}
+		fps.list = append(fps.list, f)
+		if len(fps.list) > maxFingerprints {
+			fps.list = fps.list[len(fps.list)-maxFingerprints:]
+		}
+		fps.expire = now.Add(ttl)
+	}
+	return nil
+}
+
+func (r *spamRepo) RemoveFingerprint(ctx context.Context, keys []string, f *biz.Fingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		fps, ok := r.keys[key]
+		if !ok {
+			continue
+		}
+		for i, o := range fps.list {
+			if o == f {
+				fps.list = append(fps.list[:i], fps.list[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// redisSpamRepo is a fingerprint store in redis, it detects across instances.
+type redisSpamRepo struct {
+	rdb *redis.Client
+	log *log.Helper
+}
+
+func spamKey(key string) string {
+	return "comment:spam:" + key
+}
+
+func fingerprintMember(f *biz.Fingerprint) string {
+	return fmt.Sprintf("%016x:%016x:%d:%d", f.Hash, f.SimHash, f.Length, f.CreateTime.UnixNano())
+}
+
+func parseFingerprint(member string) (*biz.Fingerprint, error) {
+	var (
+		f    = new(biz.Fingerprint)
+		nsec int64
+	)
+	if _, err := fmt.Sscanf(member, "%016x:%016x:%d:%d", &f.Hash, &f.SimHash, &f.Length, &nsec); err != nil {
+		return nil, fmt.Errorf("fingerprint %q: %v", member, err)
+	}
+	f.CreateTime = time.Unix(0, nsec)
+	return f, nil
+}
+
+func unixMilli(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+func (r *redisSpamRepo) AddFingerprint(ctx context.Context, keys []string, f *biz.Fingerprint, since time.Time, ttl time.Duration,
+	add func(fps [][]*biz.Fingerprint) bool) (err error) {
+	rkeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rkeys = append(rkeys, spamKey(key))
+	}
+	for i := 0; i < spamRetries; i++ {
+		if err = r.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			return r.addFingerprint(ctx, tx, rkeys, f, since, ttl, add)
+		}, rkeys...); !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	return err
+}
+
+// addFingerprint lists and adds the fingerprints of keys watched by tx, the
+// add fails with redis.TxFailedErr when a key was changed after the list.
+func (r *redisSpamRepo) addFingerprint(ctx context.Context, tx *redis.Tx, keys []string, f *biz.Fingerprint, since time.Time,
+	ttl time.Duration, add func(fps [][]*biz.Fingerprint) bool) error {
+	lists := make([][]*biz.Fingerprint, 0, len(keys))
+	for _, key := range keys {
+		ms, err := tx.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: "(" + unixMilli(since), Max: "+inf"}).Result()
+		if err != nil {
+			return err
+		}
+		list := make([]*biz.Fingerprint, 0, len(ms))
+		for _, m := range ms {
+			f, err := parseFingerprint(m)
+			if err != nil {
+				return err
+			}
+			list = append(list, f)
+		}
+		lists = append(lists, list)
+	}
+	if !add(lists) {
+		return nil
+	}
+	_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZAdd(ctx, key, &redis.Z{Score: float64(f.CreateTime.UnixNano() / int64(time.Millisecond)), Member: fingerprintMember(f)})
+			pipe.ZRemRangeByScore(ctx, key, "-inf", "("+unixMilli(since))
+			pipe.ZRemRangeByRank(ctx, key, 0, -maxFingerprints-1)
+			pipe.PExpire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisSpamRepo) RemoveFingerprint(ctx context.Context, keys []string, f *biz.Fingerprint) error {
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZRem(ctx, spamKey(key), fingerprintMember(f))
+		}
+		return nil
+	})
+	return err
+}
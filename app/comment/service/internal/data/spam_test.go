@@ -0,0 +1,69 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSpamCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		open func(t *testing.T) *Data
+	}{
+		{"memory", newTestData},
+		{"redis", func(t *testing.T) *Data {
+			d, _ := newCachedTestData(t, nil)
+			d.sharedLimit = true
+			return d
+		}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 8
+			var (
+				ctx = context.Background()
+				c   = &conf.Comment{Spam: &conf.Comment_Spam{Window: durationpb.New(time.Minute), MaxDuplicates: 2}}
+				uc  = biz.NewSpamUsecase(c, NewSpamRepo(tt.open(t), log.DefaultLogger), log.DefaultLogger)
+				wg  sync.WaitGroup
+				fps = make(chan *biz.Fingerprint, n)
+			)
+			// a concurrent burst of duplicates is checked and recorded one by one
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					action, fp, err := uc.Check(ctx, &biz.Comment{MemberID: 1, IP: 2, Message: "same message"}, "")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if action == biz.SpamActionNone {
+						fps <- fp
+					}
+				}()
+			}
+			wg.Wait()
+			close(fps)
+			if len(fps) != 2 {
+				t.Fatalf("accepted %d of a burst of %d duplicates, want 2", len(fps), n)
+			}
+
+			// a forgotten fingerprint is not counted
+			uc.Forget(ctx, &biz.Comment{MemberID: 1, IP: 2}, <-fps)
+			action, _, err := uc.Check(ctx, &biz.Comment{MemberID: 1, IP: 3, Message: "Same message!"}, "")
+			if err != nil || action != biz.SpamActionNone {
+				t.Fatalf("got action %d err %v after a forget", action, err)
+			}
+			if action, _, _ = uc.Check(ctx, &biz.Comment{MemberID: 1, IP: 4, Message: "same message"}, ""); action != biz.SpamActionReject {
+				t.Fatalf("got action %d of a duplicate from the member", action)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+// localStorage keeps the attachment files in a local directory, which is
+// served at base_url by a static file server.
+type localStorage struct {
+	root    string
+	baseURL string
+	log     *log.Helper
+}
+
+// NewAttachmentStorage .
+func NewAttachmentStorage(c *conf.Data, logger log.Logger) biz.AttachmentStorage {
+	return &localStorage{
+		root:    c.GetStorage().GetRoot(),
+		baseURL: strings.TrimRight(c.GetStorage().GetBaseUrl(), "/"),
+		log:     log.NewHelper(logger),
+	}
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, data []byte) error {
+	name := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, data, 0644)
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+const subjectColumns = `id, obj_id, obj_type, member_id, count, root_count, all_count, state, create_time`
+
+type subjectRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewSubjectRepo .
+func NewSubjectRepo(data *Data, logger log.Logger) biz.SubjectRepo {
+	return &subjectRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+func (r *subjectRepo) CreateSubject(ctx context.Context, s *biz.Subject) error {
+	ctx, cancel := r.data.subjectBloom.writeContext(ctx)
+	defer cancel()
+	if err := r.data.subjectBloom.add(ctx, subjectBloomKey(s.ObjID, s.ObjType)); err != nil {
+		r.log.Errorf("add subject %d %d to bloom filter: %v", s.ObjID, s.ObjType, err)
+	}
+	now := time.Now()
+	res, err := r.data.subjectDB(s.ObjID, s.ObjType).ExecContext(ctx, `INSERT INTO comment_subject
+		(obj_id, obj_type, member_id, count, root_count, all_count, state, create_time, update_time)
+		VALUES (?, ?, ?, 0, 0, 0, ?, ?, ?)`,
+		s.ObjID, s.ObjType, s.MemberID, s.State, now, now)
+	if err != nil {
+		return err
+	}
+	if s.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+	s.CreateTime = now
+	if err = r.data.cacheCreateSubject(ctx, s.ObjID, s.ObjType); err != nil {
+		r.log.Errorf("uncache missing subject %d %d: %v", s.ObjID, s.ObjType, err)
+	}
+	return nil
+}
+
+func (r *subjectRepo) GetSubject(ctx context.Context, objID int64, objType int32) (*biz.Subject, error) {
+	ok, err := r.data.subjectBloom.mayContain(ctx, subjectBloomKey(objID, objType))
+	if err != nil {
+		r.log.Errorf("check subject %d %d in bloom filter: %v", objID, objType, err)
+	}
+	if !ok {
+		return nil, biz.ErrSubjectNotFound
+	}
+	if r.data.rdb != nil {
+		missing, err := r.data.cachedNoSubject(ctx, objID, objType)
+		if err != nil {
+			r.log.Errorf("get missing subject %d %d from cache: %v", objID, objType, err)
+		}
+		if missing {
+			return nil, biz.ErrSubjectNotFound
+		}
+	}
+	s, err := scanSubject(r.data.subjectDB(objID, objType).QueryRowContext(ctx, `SELECT `+subjectColumns+`
+		FROM comment_subject WHERE obj_id = ? AND obj_type = ?`, objID, objType))
+	if err == sql.ErrNoRows {
+		if r.data.rdb != nil {
+			if err = r.data.cacheNoSubject(ctx, objID, objType); err != nil {
+				r.log.Errorf("cache missing subject %d %d: %v", objID, objType, err)
+			}
+		}
+		return nil, biz.ErrSubjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = r.data.mergeSubjectDeltas(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *subjectRepo) ListSubject(ctx context.Context, objIDs []int64) ([]*biz.Subject, error) {
+	if len(objIDs) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(objIDs))
+	for _, id := range objIDs {
+		args = append(args, id)
+	}
+	// the obj types are unknown, so every shard database is searched
+	var ss []*biz.Subject
+	for _, db := range r.data.shardDBs {
+		rows, err := db.QueryContext(ctx, `SELECT `+subjectColumns+`
+			FROM comment_subject WHERE obj_id IN (`+placeholders(len(objIDs))+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		if ss, err = scanSubjects(rows, ss); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.data.mergeSubjectDeltas(ctx, ss...); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func scanSubjects(rows *sql.Rows, ss []*biz.Subject) ([]*biz.Subject, error) {
+	defer rows.Close()
+	for rows.Next() {
+		s, err := scanSubject(rows)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	return ss, rows.Err()
+}
+
+func scanSubject(s scanner) (*biz.Subject, error) {
+	sub := new(biz.Subject)
+	err := s.Scan(&sub.ID, &sub.ObjID, &sub.ObjType, &sub.MemberID, &sub.Count, &sub.RootCount, &sub.AllCount,
+		&sub.State, &sub.CreateTime)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Admin is a middleware authorizing the admin rpcs by the operator token in
+// the authorization metadata or header, the operator_id of a request must
+// be the operator of the token.
+func Admin(c *conf.Server_Admin) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if isAdmin(req) {
+				var auth string
+				if tr, ok := transport.FromServerContext(ctx); ok {
+					auth = tr.RequestHeader().Get("authorization")
+				}
+				if err := authorize(c, auth, req); err != nil {
+					return nil, err
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// AdminStream authorizes the requests of the admin streams like Admin.
+func AdminStream(c *conf.Server_Admin) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		var auth string
+		if md, ok := metadata.FromIncomingContext(ss.Context()); ok {
+			if vs := md.Get("authorization"); len(vs) > 0 {
+				auth = vs[0]
+			}
+		}
+		return handler(srv, &adminStream{ServerStream: ss, c: c, auth: auth})
+	}
+}
+
+type adminStream struct {
+	grpc.ServerStream
+	c    *conf.Server_Admin
+	auth string
+}
+
+func (s *adminStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if isAdmin(m) {
+		return authorize(s.c, s.auth, m)
+	}
+	return nil
+}
+
+// isAdmin reports whether req is a request of an admin rpc.
+func isAdmin(req interface{}) bool {
+	switch req.(type) {
+	case *v1.ListReportedCommentReq, *v1.BanMemberReq, *v1.UnbanMemberReq, *v1.ListBannedReq,
+		*v1.GetCommentHistoryReq, *v1.ExportMemberDataReq, *v1.EraseMemberReq, *v1.GetMemberErasureReq:
+		return true
+	}
+	return false
+}
+
+// authorize checks the bearer token of auth and the operator of req.
+func authorize(c *conf.Server_Admin, auth string, req interface{}) error {
+	token := strings.TrimPrefix(auth, "Bearer ")
+	if token == "" || token == auth {
+		return v1.ErrorOperatorUnauthorized("operator token is missing")
+	}
+	var (
+		operator int64
+		found    bool
+	)
+	for t, id := range c.GetTokens() {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			operator, found = id, true
+		}
+	}
+	if !found {
+		return v1.ErrorOperatorUnauthorized("operator token is invalid")
+	}
+	if r, ok := req.(interface{ GetOperatorId() int64 }); ok && r.GetOperatorId() != operator {
+		return v1.ErrorOperatorUnauthorized("operator %d is not the operator of the token", r.GetOperatorId())
+	}
+	return nil
+}
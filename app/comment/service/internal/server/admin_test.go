@@ -0,0 +1,38 @@
+package server
+
+import (
+	"testing"
+
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/conf"
+)
+
+func TestAuthorize(t *testing.T) {
+	c := &conf.Server_Admin{Tokens: map[string]int64{"secret": 7}}
+	for _, tc := range []struct {
+		name string
+		c    *conf.Server_Admin
+		auth string
+		req  interface{}
+		ok   bool
+	}{
+		{"operator of the token", c, "Bearer secret", &v1.EraseMemberReq{MemberId: 1, OperatorId: 7}, true},
+		{"request without operator", c, "Bearer secret", &v1.BanMemberReq{MemberId: 1}, true},
+		{"another operator", c, "Bearer secret", &v1.ExportMemberDataReq{MemberId: 1, OperatorId: 8}, false},
+		{"invalid token", c, "Bearer guess", &v1.ListBannedReq{}, false},
+		{"missing token", c, "", &v1.ListBannedReq{}, false},
+		{"not a bearer token", c, "secret", &v1.ListBannedReq{}, false},
+		{"no operators configured", nil, "Bearer secret", &v1.ListBannedReq{}, false},
+	} {
+		err := authorize(tc.c, tc.auth, tc.req)
+		if tc.ok && err != nil {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+		if !tc.ok && !v1.IsOperatorUnauthorized(err) {
+			t.Errorf("%s: got error %v, want operator unauthorized", tc.name, err)
+		}
+	}
+	if isAdmin(&v1.CreateCommentReq{}) || !isAdmin(&v1.GetCommentHistoryReq{}) {
+		t.Error("got the admin rpcs wrong")
+	}
+}
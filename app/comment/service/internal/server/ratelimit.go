@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	v1 "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+// RateLimit is a middleware limiting CreateComment by member, ip and member-subject.
+func RateLimit(uc *biz.RateLimitUsecase) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if r, ok := req.(*v1.CreateCommentReq); ok {
+				if err := uc.Allow(ctx, r.MemberId, r.Ip, r.ObjId, r.ObjType); err != nil {
+					return nil, err
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
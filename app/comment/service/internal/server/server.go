@@ -0,0 +1,8 @@
+package server
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderSet is service providers.
+var ProviderSet = wire.NewSet(NewGRPCServer, NewHTTPServer)
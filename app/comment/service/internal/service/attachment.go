@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) UploadAttachment(ctx context.Context, req *pb.UploadAttachmentReq) (*pb.UploadAttachmentReply, error) {
+	a, err := s.attach.Upload(ctx, req.MemberId, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UploadAttachmentReply{
+		Token:      a.Token,
+		Attachment: attachment(a),
+	}, nil
+}
+
+func tokens(ts []string) []*biz.Attachment {
+	as := make([]*biz.Attachment, 0, len(ts))
+	for _, t := range ts {
+		as = append(as, &biz.Attachment{Token: t})
+	}
+	return as
+}
+
+func attachments(as []*biz.Attachment) []*pb.Attachment {
+	pas := make([]*pb.Attachment, 0, len(as))
+	for _, a := range as {
+		pas = append(pas, attachment(a))
+	}
+	return pas
+}
+
+func attachment(a *biz.Attachment) *pb.Attachment {
+	return &pb.Attachment{
+		Url:    a.URL,
+		Format: a.Format,
+		Size:   a.Size,
+		Width:  a.Width,
+		Height: a.Height,
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) BlockMember(ctx context.Context, req *pb.BlockMemberReq) (*pb.BlockMemberReply, error) {
+	b := &biz.Block{
+		OwnerID:  req.OwnerId,
+		MemberID: req.MemberId,
+		Reason:   req.Reason,
+	}
+	if req.ExpireTime > 0 {
+		b.ExpireTime = time.Unix(req.ExpireTime, 0)
+	}
+	if err := s.block.Block(ctx, b); err != nil {
+		return nil, err
+	}
+	return &pb.BlockMemberReply{}, nil
+}
+func (s *CommentService) UnblockMember(ctx context.Context, req *pb.UnblockMemberReq) (*pb.UnblockMemberReply, error) {
+	if err := s.block.Unblock(ctx, req.OwnerId, req.MemberId); err != nil {
+		return nil, err
+	}
+	return &pb.UnblockMemberReply{}, nil
+}
+func (s *CommentService) ListBlocked(ctx context.Context, req *pb.ListBlockedReq) (*pb.ListBlockedReply, error) {
+	offset, limit := page(req.PageNo, req.PageSize)
+	bs, total, err := s.block.ListBlocked(ctx, req.OwnerId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListBlockedReply{List: blocked(bs), Total: total}, nil
+}
+func (s *CommentService) BanMember(ctx context.Context, req *pb.BanMemberReq) (*pb.BanMemberReply, error) {
+	b := &biz.Block{
+		MemberID: req.MemberId,
+		Reason:   req.Reason,
+	}
+	if req.ExpireTime > 0 {
+		b.ExpireTime = time.Unix(req.ExpireTime, 0)
+	}
+	if err := s.block.Ban(ctx, b); err != nil {
+		return nil, err
+	}
+	return &pb.BanMemberReply{}, nil
+}
+func (s *CommentService) UnbanMember(ctx context.Context, req *pb.UnbanMemberReq) (*pb.UnbanMemberReply, error) {
+	if err := s.block.Unban(ctx, req.MemberId); err != nil {
+		return nil, err
+	}
+	return &pb.UnbanMemberReply{}, nil
+}
+func (s *CommentService) ListBanned(ctx context.Context, req *pb.ListBannedReq) (*pb.ListBannedReply, error) {
+	offset, limit := page(req.PageNo, req.PageSize)
+	bs, total, err := s.block.ListBanned(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListBannedReply{List: blocked(bs), Total: total}, nil
+}
+
+func blocked(bs []*biz.Block) []*pb.ListBlockedReply_Blocked {
+	list := make([]*pb.ListBlockedReply_Blocked, 0, len(bs))
+	for _, b := range bs {
+		var expire int64
+		if !b.ExpireTime.IsZero() {
+			expire = b.ExpireTime.Unix()
+		}
+		list = append(list, &pb.ListBlockedReply_Blocked{
+			MemberId:   b.MemberID,
+			ExpireTime: expire,
+			Reason:     b.Reason,
+			CreateTime: b.CreateTime.Unix(),
+		})
+	}
+	return list
+}
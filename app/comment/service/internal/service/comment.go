@@ -1,32 +1,189 @@
-
 package service
 
-import(
+import (
 	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
 	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 50
+	// maxOffset is the deepest offset listed, the pages after it are empty.
+	maxOffset = 100000
 )
 
 type CommentService struct {
 	pb.UnimplementedCommentServiceServer
+
+	uc      *biz.CommentUsecase
+	report  *biz.ReportUsecase
+	block   *biz.BlockUsecase
+	mute    *biz.MuteUsecase
+	attach  *biz.AttachmentUsecase
+	export  *biz.ExportUsecase
+	erasure *biz.ErasureUsecase
+	log     *log.Helper
 }
 
-func NewCommentServiceService() *CommentService {
-	return &CommentService{}
+func NewCommentService(uc *biz.CommentUsecase, report *biz.ReportUsecase, block *biz.BlockUsecase,
+	mute *biz.MuteUsecase, attach *biz.AttachmentUsecase, export *biz.ExportUsecase,
+	erasure *biz.ErasureUsecase, logger log.Logger) *CommentService {
+	return &CommentService{
+		uc:      uc,
+		report:  report,
+		block:   block,
+		mute:    mute,
+		attach:  attach,
+		export:  export,
+		erasure: erasure,
+		log:     log.NewHelper(logger),
+	}
 }
 
 func (s *CommentService) CreateSubject(ctx context.Context, req *pb.CreateSubjectReq) (*pb.CreateSubjectReply, error) {
+	err := s.uc.CreateSubject(ctx, &biz.Subject{
+		ObjID:    req.ObjId,
+		ObjType:  req.ObjType,
+		MemberID: req.MemberId,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &pb.CreateSubjectReply{}, nil
 }
 func (s *CommentService) CreateComment(ctx context.Context, req *pb.CreateCommentReq) (*pb.CreateCommentReply, error) {
+	err := s.uc.CreateComment(ctx, &biz.Comment{
+		ObjID:       req.ObjId,
+		ObjType:     req.ObjType,
+		MemberID:    req.MemberId,
+		Root:        req.Root,
+		Parent:      req.Parent,
+		AtMemberIDs: req.AtMemberIds,
+		Message:     req.Message,
+		Meta:        req.Meta,
+		Content:     req.Content,
+		Attachments: tokens(req.Attachments),
+		IP:          req.Ip,
+		Platform:    req.Platform,
+		Device:      req.Device,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &pb.CreateCommentReply{}, nil
 }
 func (s *CommentService) DeleteComment(ctx context.Context, req *pb.DeleteCommentReq) (*pb.DeleteCommentReply, error) {
+	if err := s.uc.DeleteComment(ctx, req.CommentId); err != nil {
+		return nil, err
+	}
 	return &pb.DeleteCommentReply{}, nil
 }
 func (s *CommentService) ListComment(ctx context.Context, req *pb.ListCommentReq) (*pb.ListCommentReply, error) {
-	return &pb.ListCommentReply{}, nil
+	offset, limit := page(req.PageNo, req.PageSize)
+	cs, total, err := s.uc.ListComment(ctx, req.MemberId, req.ObjId, req.ObjType, int8(req.Sort), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListCommentReply{
+		List:  make([]*pb.ListCommentReply_Comment, 0, len(cs)),
+		Total: total,
+	}
+	for _, c := range cs {
+		reply.List = append(reply.List, &pb.ListCommentReply_Comment{
+			CommentId:   c.ID,
+			MemberId:    c.MemberID,
+			Floor:       c.Floor,
+			Like:        int64(c.Like),
+			Hate:        int64(c.Hate),
+			AtMemberIds: c.AtMemberIDs,
+			Message:     c.Message,
+			Meta:        c.Meta,
+			CreateTime:  c.CreateTime.Unix(),
+			Count:       c.RootCount,
+			Replies:     replies(c.Replies),
+			Mentions:    mentions(c.Mentions),
+			Content:     c.Content,
+			Attachments: attachments(c.Attachments),
+			EditTime:    editTime(c),
+		})
+	}
+	return reply, nil
 }
 func (s *CommentService) ListReply(ctx context.Context, req *pb.ListReplyReq) (*pb.ListReplyReply, error) {
-	return &pb.ListReplyReply{}, nil
+	offset, limit := page(req.PageNo, req.PageSize)
+	cs, total, err := s.uc.ListReply(ctx, req.MemberId, req.CommentId, int8(req.Sort), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListReplyReply{
+		Replies: replies(cs),
+		Total:   total,
+	}, nil
+}
+
+func replies(cs []*biz.Comment) []*pb.Reply {
+	rs := make([]*pb.Reply, 0, len(cs))
+	for _, c := range cs {
+		rs = append(rs, reply(c))
+	}
+	return rs
+}
+
+func reply(c *biz.Comment) *pb.Reply {
+	return &pb.Reply{
+		CommentId:     c.ID,
+		MemberId:      c.MemberID,
+		ParentId:      c.Parent,
+		ReplyMemberId: c.ReplyMemberID,
+		Floor:         c.Floor,
+		Like:          int64(c.Like),
+		Hate:          int64(c.Hate),
+		AtMemberIds:   c.AtMemberIDs,
+		Message:       c.Message,
+		CreateTime:    c.CreateTime.Unix(),
+		Mentions:      mentions(c.Mentions),
+		Content:       c.Content,
+		Attachments:   attachments(c.Attachments),
+		EditTime:      editTime(c),
+	}
+}
+
+func editTime(c *biz.Comment) int64 {
+	if c.EditTime.IsZero() {
+		return 0
+	}
+	return c.EditTime.Unix()
+}
+
+func mentions(ms []*biz.Mention) []*pb.Mention {
+	pms := make([]*pb.Mention, 0, len(ms))
+	for _, m := range ms {
+		pms = append(pms, &pb.Mention{
+			MemberId: m.MemberID,
+			Offset:   m.Offset,
+			Length:   m.Length,
+		})
+	}
+	return pms
+}
+
+// page converts page_no and page_size to offset and limit, the limit is
+// 0 for a page after maxOffset.
+func page(no, size int32) (offset, limit int) {
+	if no < 1 {
+		no = 1
+	}
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	if o := (int64(no) - 1) * int64(size); o <= maxOffset {
+		return int(o), int(size)
+	}
+	return maxOffset, 0
 }
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) GetComment(ctx context.Context, req *pb.GetCommentReq) (*pb.GetCommentReply, error) {
+	d, err := s.uc.GetComment(ctx, req.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCommentReply{Comment: detail(d)}, nil
+}
+func (s *CommentService) BatchGetComments(ctx context.Context, req *pb.BatchGetCommentsReq) (*pb.BatchGetCommentsReply, error) {
+	ds, err := s.uc.BatchGetComments(ctx, req.Ids)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.BatchGetCommentsReply{
+		Comments: make(map[int64]*pb.CommentDetail, len(ds)),
+	}
+	for id, d := range ds {
+		reply.Comments[id] = detail(d)
+	}
+	return reply, nil
+}
+
+func detail(d *biz.CommentDetail) *pb.CommentDetail {
+	c := d.Comment
+	pd := &pb.CommentDetail{
+		CommentId:     c.ID,
+		ObjId:         c.ObjID,
+		ObjType:       c.ObjType,
+		MemberId:      c.MemberID,
+		Root:          c.Root,
+		Parent:        c.Parent,
+		ReplyMemberId: c.ReplyMemberID,
+		Floor:         c.Floor,
+		Like:          int64(c.Like),
+		Hate:          int64(c.Hate),
+		Count:         c.RootCount,
+		State:         int32(c.State),
+		AtMemberIds:   c.AtMemberIDs,
+		Message:       c.Message,
+		Meta:          c.Meta,
+		Content:       c.Content,
+		Mentions:      mentions(c.Mentions),
+		Attachments:   attachments(c.Attachments),
+		CreateTime:    c.CreateTime.Unix(),
+		EditTime:      editTime(c),
+	}
+	if sub := d.Subject; sub != nil {
+		pd.Subject = &pb.CommentDetail_Subject{
+			ObjId:    sub.ObjID,
+			ObjType:  sub.ObjType,
+			MemberId: sub.MemberID,
+			Count:    sub.RootCount,
+			AllCount: sub.AllCount,
+			State:    int32(sub.State),
+		}
+	}
+	if d.RootComment != nil {
+		pd.RootComment = reply(d.RootComment)
+	}
+	if d.ParentComment != nil {
+		pd.ParentComment = reply(d.ParentComment)
+	}
+	return pd
+}
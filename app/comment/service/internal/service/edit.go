@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) EditComment(ctx context.Context, req *pb.EditCommentReq) (*pb.EditCommentReply, error) {
+	err := s.uc.EditComment(ctx, &biz.Comment{
+		ID:       req.CommentId,
+		MemberID: req.MemberId,
+		Message:  req.Message,
+		Meta:     req.Meta,
+		Content:  req.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.EditCommentReply{}, nil
+}
+func (s *CommentService) GetCommentHistory(ctx context.Context, req *pb.GetCommentHistoryReq) (*pb.GetCommentHistoryReply, error) {
+	vs, err := s.uc.GetCommentHistory(ctx, req.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.GetCommentHistoryReply{
+		List: make([]*pb.GetCommentHistoryReply_Version, 0, len(vs)),
+	}
+	for _, v := range vs {
+		reply.List = append(reply.List, &pb.GetCommentHistoryReply_Version{
+			Message:     v.Message,
+			Meta:        v.Meta,
+			Content:     v.Content,
+			AtMemberIds: v.AtMemberIDs,
+			CreateTime:  v.CreateTime.Unix(),
+		})
+	}
+	return reply, nil
+}
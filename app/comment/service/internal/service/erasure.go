@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// exportLine is a JSON line of ExportMemberData.
+type exportLine struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type exportLike struct {
+	ID         int64 `json:"id"`
+	CommentID  int64 `json:"comment_id"`
+	Action     int8  `json:"action"`
+	CreateTime int64 `json:"create_time"`
+}
+
+type exportReport struct {
+	ID         int64  `json:"id"`
+	CommentID  int64  `json:"comment_id"`
+	ObjID      int64  `json:"obj_id"`
+	ObjType    int32  `json:"obj_type"`
+	Reason     int32  `json:"reason"`
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+func (s *CommentService) ExportMemberData(req *pb.ExportMemberDataReq, stream pb.CommentService_ExportMemberDataServer) error {
+	return s.export.Export(stream.Context(), req.OperatorId, req.MemberId, func(r *biz.ExportRecord) error {
+		var (
+			data []byte
+			err  error
+		)
+		switch r.Type {
+		case biz.ExportTypeComment:
+			data, err = protojson.Marshal(detail(&biz.CommentDetail{Comment: r.Comment}))
+		case biz.ExportTypeLike:
+			data, err = json.Marshal(&exportLike{
+				ID:         r.Like.ID,
+				CommentID:  r.Like.CommentID,
+				Action:     r.Like.Action,
+				CreateTime: r.Like.CreateTime.Unix(),
+			})
+		case biz.ExportTypeReport:
+			data, err = json.Marshal(&exportReport{
+				ID:         r.Report.ID,
+				CommentID:  r.Report.CommentID,
+				ObjID:      r.Report.ObjID,
+				ObjType:    r.Report.ObjType,
+				Reason:     r.Report.Reason,
+				Content:    r.Report.Content,
+				CreateTime: r.Report.CreateTime.Unix(),
+			})
+		}
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(&exportLine{Type: r.Type, Data: data})
+		if err != nil {
+			return err
+		}
+		return stream.Send(&pb.ExportMemberDataReply{Line: string(line)})
+	})
+}
+func (s *CommentService) EraseMember(ctx context.Context, req *pb.EraseMemberReq) (*pb.EraseMemberReply, error) {
+	e := &biz.Erasure{
+		MemberID:   req.MemberId,
+		OperatorID: req.OperatorId,
+		Reason:     req.Reason,
+	}
+	if err := s.erasure.Erase(ctx, e); err != nil {
+		return nil, err
+	}
+	return &pb.EraseMemberReply{TaskId: e.ID}, nil
+}
+func (s *CommentService) GetMemberErasure(ctx context.Context, req *pb.GetMemberErasureReq) (*pb.GetMemberErasureReply, error) {
+	e, err := s.erasure.GetErasure(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMemberErasureReply{
+		MemberId:   e.MemberID,
+		OperatorId: e.OperatorID,
+		State:      int32(e.State),
+		Erased:     e.Erased,
+		CreateTime: e.CreateTime.Unix(),
+		FinishTime: unix(e.FinishTime),
+	}, nil
+}
+
+// unix returns the unix seconds of t, 0 for the zero time.
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
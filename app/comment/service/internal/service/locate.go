@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+)
+
+func (s *CommentService) LocateComment(ctx context.Context, req *pb.LocateCommentReq) (*pb.LocateCommentReply, error) {
+	loc, err := s.uc.LocateComment(ctx, req.MemberId, req.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	_, size := page(1, req.PageSize)
+	reply := &pb.LocateCommentReply{RootId: loc.RootID, PageSize: int32(size)}
+	reply.PageNo, reply.Index = pageOf(loc.RootRank, size)
+	if loc.ReplyRank >= 0 {
+		reply.ReplyPageNo, reply.ReplyIndex = pageOf(loc.ReplyRank, size)
+	}
+	return reply, nil
+}
+
+// pageOf returns the page number from 1 and the index in the page of the
+// item at rank in pages of size.
+func pageOf(rank int32, size int) (no, index int32) {
+	return rank/int32(size) + 1, rank % int32(size)
+}
@@ -0,0 +1,34 @@
+package service
+
+import "testing"
+
+func TestPageOf(t *testing.T) {
+	for _, tc := range []struct {
+		rank      int32
+		pageSize  int32
+		no, index int32
+	}{
+		{0, 10, 1, 0},
+		{9, 10, 1, 9},
+		{10, 10, 2, 0},
+		{19, 10, 2, 9},
+		{20, 10, 3, 0},
+		{0, 1, 1, 0},
+		{1, 1, 2, 0},
+		{defaultPageSize - 1, 0, 1, defaultPageSize - 1},
+		{defaultPageSize, 0, 2, 0},
+		{defaultPageSize, -1, 2, 0},
+		{maxPageSize - 1, maxPageSize + 1, 1, maxPageSize - 1},
+		{maxPageSize, maxPageSize + 1, 2, 0},
+	} {
+		_, size := page(1, tc.pageSize)
+		no, index := pageOf(tc.rank, size)
+		if no != tc.no || index != tc.index {
+			t.Errorf("rank %d in pages of %d: got page %d index %d, want page %d index %d",
+				tc.rank, tc.pageSize, no, index, tc.no, tc.index)
+		}
+		if offset, _ := page(no, tc.pageSize); offset+int(index) != int(tc.rank) {
+			t.Errorf("rank %d in pages of %d: page %d starts at %d", tc.rank, tc.pageSize, no, offset)
+		}
+	}
+}
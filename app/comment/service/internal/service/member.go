@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) ListMemberComments(ctx context.Context, req *pb.ListMemberCommentsReq) (*pb.ListMemberCommentsReply, error) {
+	_, limit := page(1, req.PageSize)
+	f := &biz.MemberCommentFilter{
+		MemberID: req.MemberId,
+		ObjType:  req.ObjType,
+		Cursor:   req.Cursor,
+		Limit:    limit,
+	}
+	if req.StartTime > 0 {
+		f.StartTime = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		f.EndTime = time.Unix(req.EndTime, 0)
+	}
+	cs, next, err := s.uc.ListMemberComment(ctx, req.ViewerId, f)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListMemberCommentsReply{
+		List:       make([]*pb.CommentDetail, 0, len(cs)),
+		NextCursor: next,
+		HasMore:    next != 0,
+	}
+	for _, c := range cs {
+		reply.List = append(reply.List, detail(&biz.CommentDetail{Comment: c}))
+	}
+	return reply, nil
+}
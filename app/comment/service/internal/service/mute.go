@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) MuteMember(ctx context.Context, req *pb.MuteMemberReq) (*pb.MuteMemberReply, error) {
+	err := s.mute.Mute(ctx, &biz.Mute{
+		MemberID:     req.MemberId,
+		MuteMemberID: req.MuteMemberId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MuteMemberReply{}, nil
+}
+func (s *CommentService) UnmuteMember(ctx context.Context, req *pb.UnmuteMemberReq) (*pb.UnmuteMemberReply, error) {
+	if err := s.mute.Unmute(ctx, req.MemberId, req.MuteMemberId); err != nil {
+		return nil, err
+	}
+	return &pb.UnmuteMemberReply{}, nil
+}
+func (s *CommentService) ListMuted(ctx context.Context, req *pb.ListMutedReq) (*pb.ListMutedReply, error) {
+	offset, limit := page(req.PageNo, req.PageSize)
+	ms, total, err := s.mute.ListMuted(ctx, req.MemberId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListMutedReply{
+		List:  make([]*pb.ListMutedReply_Muted, 0, len(ms)),
+		Total: total,
+	}
+	for _, m := range ms {
+		reply.List = append(reply.List, &pb.ListMutedReply_Muted{
+			MemberId:   m.MuteMemberID,
+			CreateTime: m.CreateTime.Unix(),
+		})
+	}
+	return reply, nil
+}
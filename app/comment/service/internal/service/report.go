@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/zldongly/comment/api/comment/service/v1"
+	"github.com/zldongly/comment/app/comment/service/internal/biz"
+)
+
+func (s *CommentService) ReportComment(ctx context.Context, req *pb.ReportCommentReq) (*pb.ReportCommentReply, error) {
+	err := s.report.ReportComment(ctx, &biz.Report{
+		CommentID: req.CommentId,
+		MemberID:  req.MemberId,
+		Reason:    int32(req.Reason),
+		Content:   req.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReportCommentReply{}, nil
+}
+func (s *CommentService) ListReportedComment(ctx context.Context, req *pb.ListReportedCommentReq) (*pb.ListReportedCommentReply, error) {
+	offset, limit := page(req.PageNo, req.PageSize)
+	stats, total, err := s.report.ListReportedComment(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListReportedCommentReply{
+		List:  make([]*pb.ListReportedCommentReply_Comment, 0, len(stats)),
+		Total: total,
+	}
+	for _, st := range stats {
+		var message string
+		if st.Comment != nil {
+			message = st.Comment.Message
+		}
+		reply.List = append(reply.List, &pb.ListReportedCommentReply_Comment{
+			CommentId:  st.CommentID,
+			ObjId:      st.ObjID,
+			ObjType:    st.ObjType,
+			MemberId:   st.MemberID,
+			Message:    message,
+			Count:      st.Count,
+			Reasons:    st.Reasons,
+			Hidden:     st.Hidden,
+			UpdateTime: st.UpdateTime.Unix(),
+		})
+	}
+	return reply, nil
+}